@@ -0,0 +1,160 @@
+package commands
+
+import (
+	"fmt"
+	"github.com/getflight/flight/service"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/spf13/cobra"
+)
+
+type Artifacts struct {
+	ArtifactService service.ArtifactServiceType
+}
+
+func (a *Artifacts) command() *cobra.Command {
+	command := &cobra.Command{
+		Use:   "artifacts",
+		Short: "Inspect the artifacts uploaded by flight deploy and the local artifact cache",
+		Long:  `Artifacts commands read the metadata saved with the artifacts of your deployments. Artifacts packaged or deployed are kept in the work path under their digest, and are referenced by their id, their digest or a prefix of their digest`,
+	}
+
+	command.AddCommand(a.downloadCommand())
+	command.AddCommand(a.inspectCommand())
+	command.AddCommand(a.listCommand())
+	command.AddCommand(a.pruneCommand())
+	command.AddCommand(a.sbomCommand())
+	command.AddCommand(a.verifyCommand())
+
+	return command
+}
+
+func (a *Artifacts) downloadCommand() *cobra.Command {
+	var output string
+
+	command := &cobra.Command{
+		Use:   "download <id>",
+		Short: "Download the zip of an artifact",
+		Long:  `Download writes the zip of an artifact from the cache, or downloads it and keeps it in the cache, checking that it matches its digest`,
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			err := a.ArtifactService.Download(args[0], output)
+
+			if err != nil {
+				log.Debugf("%+v", err)
+				log.Fatal(err.Error())
+			}
+		},
+	}
+
+	command.Flags().StringVarP(&output, "output", "o", "main.zip", "file to write the artifact zip to")
+
+	return command
+}
+
+func (a *Artifacts) inspectCommand() *cobra.Command {
+	command := &cobra.Command{
+		Use:   "inspect <id>",
+		Short: "Print the metadata and files of an artifact",
+		Long:  `Inspect prints the digest, commit and signing key of an artifact along with the files of its zip and their sizes`,
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			err := a.ArtifactService.Inspect(args[0])
+
+			if err != nil {
+				log.Debugf("%+v", err)
+				log.Fatal(err.Error())
+			}
+		},
+	}
+
+	return command
+}
+
+func (a *Artifacts) listCommand() *cobra.Command {
+	command := &cobra.Command{
+		Use:   "list",
+		Short: "List the cached and uploaded artifacts",
+		Long:  `List prints the artifacts of the local cache, then the artifacts uploaded to your organisation when logged in`,
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			err := a.ArtifactService.List()
+
+			if err != nil {
+				log.Debugf("%+v", err)
+				log.Fatal(err.Error())
+			}
+		},
+	}
+
+	return command
+}
+
+func (a *Artifacts) pruneCommand() *cobra.Command {
+	var olderThan string
+
+	command := &cobra.Command{
+		Use:   "prune",
+		Short: "Remove old artifacts from the cache",
+		Long:  `Prune removes the cached artifacts that were not packaged or deployed for the given age, the uploaded artifacts are kept`,
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			err := a.ArtifactService.Prune(olderThan)
+
+			if err != nil {
+				log.Debugf("%+v", err)
+				log.Fatal(err.Error())
+			}
+		},
+	}
+
+	command.Flags().StringVar(&olderThan, "older-than", "30d", "age of the artifacts to remove, in days such as 30d or as a duration such as 12h")
+
+	return command
+}
+
+func (a *Artifacts) sbomCommand() *cobra.Command {
+	command := &cobra.Command{
+		Use:   "sbom <id>",
+		Short: "Print the software bill of materials of an artifact",
+		Long:  `Sbom prints the CycloneDX json sbom generated when the artifact was packaged, listing the go modules of the executable and the files of the artifact`,
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			sbom, err := a.ArtifactService.Sbom(args[0])
+
+			if err != nil {
+				log.Debugf("%+v", err)
+				log.Fatal(err.Error())
+			}
+
+			fmt.Fprintln(cmd.OutOrStdout(), sbom)
+		},
+	}
+
+	return command
+}
+
+func (a *Artifacts) verifyCommand() *cobra.Command {
+	var environment string
+	var keys []string
+
+	command := &cobra.Command{
+		Use:   "verify <id>",
+		Short: "Verify that an artifact is signed by a trusted key",
+		Long:  `Verify hashes the zip of the artifact, downloaded unless cached, and checks the signature of its digest against the signing_keys of the environment and the given keys, failing when the artifact is unsigned, signed by another key or does not match its digest`,
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			err := a.ArtifactService.Verify(args[0], environment, keys)
+
+			if err != nil {
+				log.Debugf("%+v", err)
+				log.Fatal(err.Error())
+			}
+		},
+	}
+
+	command.Flags().StringVarP(&environment, "environment", "e", "", "environment whose signing_keys are trusted")
+	command.Flags().StringSliceVar(&keys, "key", nil, "public key to trust, may be repeated")
+
+	return command
+}
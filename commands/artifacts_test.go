@@ -0,0 +1,136 @@
+package commands
+
+import (
+	"bytes"
+	"github.com/getflight/flight/mocks"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestArtifactsCommand(t *testing.T) {
+	t.Run("command returns not nil command", func(t *testing.T) {
+		// given
+		artifacts := Artifacts{}
+
+		// when
+		command := artifacts.command()
+
+		// then
+		assert.NotNil(t, command)
+	})
+
+	t.Run("run list command calls artifact service", func(t *testing.T) {
+		// given
+		artifactServiceMock := &mocks.ArtifactServiceMock{}
+		artifactServiceMock.On("List").Return(nil)
+
+		artifacts := Artifacts{
+			ArtifactService: artifactServiceMock,
+		}
+
+		command := artifacts.listCommand()
+
+		// when
+		command.Run(command, []string{})
+
+		// then
+		artifactServiceMock.AssertExpectations(t)
+	})
+
+	t.Run("run inspect command calls artifact service with reference", func(t *testing.T) {
+		// given
+		artifactServiceMock := &mocks.ArtifactServiceMock{}
+		artifactServiceMock.On("Inspect", "1").Return(nil)
+
+		artifacts := Artifacts{
+			ArtifactService: artifactServiceMock,
+		}
+
+		command := artifacts.inspectCommand()
+
+		// when
+		command.Run(command, []string{"1"})
+
+		// then
+		artifactServiceMock.AssertExpectations(t)
+	})
+
+	t.Run("run download command calls artifact service with output", func(t *testing.T) {
+		// given
+		artifactServiceMock := &mocks.ArtifactServiceMock{}
+		artifactServiceMock.On("Download", "1", "artifact.zip").Return(nil)
+
+		artifacts := Artifacts{
+			ArtifactService: artifactServiceMock,
+		}
+
+		command := artifacts.downloadCommand()
+		_ = command.Flags().Set("output", "artifact.zip")
+
+		// when
+		command.Run(command, []string{"1"})
+
+		// then
+		artifactServiceMock.AssertExpectations(t)
+	})
+
+	t.Run("run prune command calls artifact service with default age", func(t *testing.T) {
+		// given
+		artifactServiceMock := &mocks.ArtifactServiceMock{}
+		artifactServiceMock.On("Prune", "30d").Return(nil)
+
+		artifacts := Artifacts{
+			ArtifactService: artifactServiceMock,
+		}
+
+		command := artifacts.pruneCommand()
+
+		// when
+		command.Run(command, []string{})
+
+		// then
+		artifactServiceMock.AssertExpectations(t)
+	})
+
+	t.Run("run sbom command prints sbom of artifact", func(t *testing.T) {
+		// given
+		artifactServiceMock := &mocks.ArtifactServiceMock{}
+		artifactServiceMock.On("Sbom", "1").Return("{}", nil)
+
+		artifacts := Artifacts{
+			ArtifactService: artifactServiceMock,
+		}
+
+		output := &bytes.Buffer{}
+		command := artifacts.sbomCommand()
+		command.SetOut(output)
+
+		// when
+		command.Run(command, []string{"1"})
+
+		// then
+		assert.Equal(t, "{}\n", output.String())
+		artifactServiceMock.AssertExpectations(t)
+	})
+
+	t.Run("run verify command calls artifact service with trusted keys", func(t *testing.T) {
+		// given
+		artifactServiceMock := &mocks.ArtifactServiceMock{}
+		artifactServiceMock.On("Verify", "1", "prod", []string{"ed25519:a", "ed25519:b"}).Return(nil)
+
+		artifacts := Artifacts{
+			ArtifactService: artifactServiceMock,
+		}
+
+		command := artifacts.verifyCommand()
+		_ = command.Flags().Set("environment", "prod")
+		_ = command.Flags().Set("key", "ed25519:a")
+		_ = command.Flags().Set("key", "ed25519:b")
+
+		// when
+		command.Run(command, []string{"1"})
+
+		// then
+		artifactServiceMock.AssertExpectations(t)
+	})
+}
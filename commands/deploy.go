@@ -2,6 +2,7 @@ package commands
 
 import (
 	"github.com/getflight/flight/formatters"
+	"github.com/getflight/flight/models"
 	"github.com/getflight/flight/service"
 	log "github.com/sirupsen/logrus"
 
@@ -14,6 +15,7 @@ type Deploy struct {
 
 func (d *Deploy) command() *cobra.Command {
 	var environment string
+	var noSecretScan bool
 
 	command := &cobra.Command{
 		Use:   "deploy",
@@ -22,7 +24,10 @@ func (d *Deploy) command() *cobra.Command {
 		Run: func(cmd *cobra.Command, args []string) {
 			log.SetFormatter(&formatters.TimestampFormatter{})
 
-			err := d.DeploymentService.Deploy(environment)
+			err := d.DeploymentService.Deploy(models.DeployOptions{
+				Environment: environment,
+				SecretScan:  !noSecretScan,
+			})
 
 			if err != nil {
 				log.Debugf("%+v", err)
@@ -32,6 +37,7 @@ func (d *Deploy) command() *cobra.Command {
 	}
 
 	command.Flags().StringVarP(&environment, "environment", "e", "", "environment to deploy to (required)")
+	command.Flags().BoolVar(&noSecretScan, "no-secret-scan", false, "upload the artifact without scanning its files for secrets")
 
 	err := command.MarkFlagRequired("environment")
 
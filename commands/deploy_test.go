@@ -2,6 +2,7 @@ package commands
 
 import (
 	"github.com/getflight/flight/mocks"
+	"github.com/getflight/flight/models"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"testing"
@@ -36,4 +37,24 @@ func TestDeployCommand(t *testing.T) {
 		// then
 		deploymentServiceMock.AssertExpectations(t)
 	})
+
+	t.Run("run command disables secret scan when flag is set", func(t *testing.T) {
+		// given
+		deploymentServiceMock := &mocks.DeploymentServiceMock{}
+		deploymentServiceMock.On("Deploy", models.DeployOptions{Environment: "prod", SecretScan: false}).Return(nil)
+
+		deploy := Deploy{
+			DeploymentService: deploymentServiceMock,
+		}
+
+		command := deploy.command()
+		_ = command.Flags().Set("environment", "prod")
+		_ = command.Flags().Set("no-secret-scan", "true")
+
+		// when
+		command.Run(command, []string{})
+
+		// then
+		deploymentServiceMock.AssertExpectations(t)
+	})
 }
@@ -0,0 +1,128 @@
+package commands
+
+import (
+	"github.com/getflight/flight/service"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/spf13/cobra"
+)
+
+type Domains struct {
+	DomainService service.DomainServiceType
+}
+
+func (d *Domains) command() *cobra.Command {
+	command := &cobra.Command{
+		Use:   "domains",
+		Short: "Manage the custom domains of your project",
+		Long:  `Domains commands apply to projects deployed with the gateway trigger, list the domains of an environment in flight.yml to keep them across deployments`,
+	}
+
+	command.AddCommand(d.addCommand())
+	command.AddCommand(d.listCommand())
+	command.AddCommand(d.verifyCommand())
+	command.AddCommand(d.removeCommand())
+
+	return command
+}
+
+func (d *Domains) addCommand() *cobra.Command {
+	var environment string
+
+	command := &cobra.Command{
+		Use:   "add [domain]",
+		Short: "Add a custom domain to your project",
+		Long:  `Add attaches a custom domain to your project and prints the dns records to create to validate its certificate`,
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			err := d.DomainService.Add(environment, args[0])
+
+			if err != nil {
+				log.Debugf("%+v", err)
+				log.Fatal(err.Error())
+			}
+		},
+	}
+
+	d.environmentFlag(command, &environment)
+
+	return command
+}
+
+func (d *Domains) listCommand() *cobra.Command {
+	var environment string
+
+	command := &cobra.Command{
+		Use:   "list",
+		Short: "List the custom domains of your project",
+		Long:  `List prints the custom domains of your project with the status of their certificate`,
+		Run: func(cmd *cobra.Command, args []string) {
+			err := d.DomainService.List(environment)
+
+			if err != nil {
+				log.Debugf("%+v", err)
+				log.Fatal(err.Error())
+			}
+		},
+	}
+
+	d.environmentFlag(command, &environment)
+
+	return command
+}
+
+func (d *Domains) verifyCommand() *cobra.Command {
+	var environment string
+
+	command := &cobra.Command{
+		Use:   "verify [domain]",
+		Short: "Wait for the certificate of a custom domain to be issued",
+		Long:  `Verify prints the dns records to create for a custom domain and waits until its certificate is issued`,
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			err := d.DomainService.Verify(environment, args[0])
+
+			if err != nil {
+				log.Debugf("%+v", err)
+				log.Fatal(err.Error())
+			}
+		},
+	}
+
+	d.environmentFlag(command, &environment)
+
+	return command
+}
+
+func (d *Domains) removeCommand() *cobra.Command {
+	var environment string
+
+	command := &cobra.Command{
+		Use:   "remove [domain]",
+		Short: "Remove a custom domain from your project",
+		Long:  `Remove detaches a custom domain from your project, remove it from flight.yml as well so it is not added back on the next deployment`,
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			err := d.DomainService.Remove(environment, args[0])
+
+			if err != nil {
+				log.Debugf("%+v", err)
+				log.Fatal(err.Error())
+			}
+		},
+	}
+
+	d.environmentFlag(command, &environment)
+
+	return command
+}
+
+func (d *Domains) environmentFlag(command *cobra.Command, environment *string) {
+	command.Flags().StringVarP(environment, "environment", "e", "", "environment of the domain (required)")
+
+	err := command.MarkFlagRequired("environment")
+
+	if err != nil {
+		log.Fatal(err)
+	}
+}
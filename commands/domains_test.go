@@ -0,0 +1,94 @@
+package commands
+
+import (
+	"github.com/getflight/flight/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+)
+
+func TestDomainsCommand(t *testing.T) {
+	t.Run("command returns not nil command with subcommands", func(t *testing.T) {
+		// given
+		domains := Domains{}
+
+		// when
+		command := domains.command()
+
+		// then
+		assert.NotNil(t, command)
+		assert.Equal(t, 4, len(command.Commands()))
+	})
+
+	t.Run("add command calls domain service when command is ran", func(t *testing.T) {
+		// given
+		domainServiceMock := &mocks.DomainServiceMock{}
+		domainServiceMock.On("Add", mock.Anything, "api.example.com").Return(nil)
+
+		domains := Domains{
+			DomainService: domainServiceMock,
+		}
+
+		command := domains.addCommand()
+
+		// when
+		command.Run(command, []string{"api.example.com"})
+
+		// then
+		domainServiceMock.AssertExpectations(t)
+	})
+
+	t.Run("list command calls domain service when command is ran", func(t *testing.T) {
+		// given
+		domainServiceMock := &mocks.DomainServiceMock{}
+		domainServiceMock.On("List", mock.Anything).Return(nil)
+
+		domains := Domains{
+			DomainService: domainServiceMock,
+		}
+
+		command := domains.listCommand()
+
+		// when
+		command.Run(command, []string{})
+
+		// then
+		domainServiceMock.AssertExpectations(t)
+	})
+
+	t.Run("verify command calls domain service when command is ran", func(t *testing.T) {
+		// given
+		domainServiceMock := &mocks.DomainServiceMock{}
+		domainServiceMock.On("Verify", mock.Anything, "api.example.com").Return(nil)
+
+		domains := Domains{
+			DomainService: domainServiceMock,
+		}
+
+		command := domains.verifyCommand()
+
+		// when
+		command.Run(command, []string{"api.example.com"})
+
+		// then
+		domainServiceMock.AssertExpectations(t)
+	})
+
+	t.Run("remove command calls domain service when command is ran", func(t *testing.T) {
+		// given
+		domainServiceMock := &mocks.DomainServiceMock{}
+		domainServiceMock.On("Remove", mock.Anything, "api.example.com").Return(nil)
+
+		domains := Domains{
+			DomainService: domainServiceMock,
+		}
+
+		command := domains.removeCommand()
+
+		// when
+		command.Run(command, []string{"api.example.com"})
+
+		// then
+		domainServiceMock.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,55 @@
+package commands
+
+import (
+	"fmt"
+	"github.com/getflight/flight/service"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/spf13/cobra"
+)
+
+type Export struct {
+	ExportService service.ExportServiceType
+}
+
+func (e *Export) command() *cobra.Command {
+	command := &cobra.Command{
+		Use:   "export",
+		Short: "Export what flight provisions for an environment",
+		Long:  `Export commands render the manifest resolved for an environment in the formats of other tools, for review or to deploy it outside of flight`,
+	}
+
+	command.AddCommand(e.cloudformationCommand())
+
+	return command
+}
+
+func (e *Export) cloudformationCommand() *cobra.Command {
+	var environment string
+
+	command := &cobra.Command{
+		Use:   "cloudformation",
+		Short: "Print an equivalent AWS SAM template",
+		Long:  `Cloudformation prints the function, trigger, databases and variables of an environment as an AWS SAM template, secrets and database passwords being parameters of the template`,
+		Run: func(cmd *cobra.Command, args []string) {
+			template, err := e.ExportService.Cloudformation(environment)
+
+			if err != nil {
+				log.Debugf("%+v", err)
+				log.Fatal(err.Error())
+			}
+
+			fmt.Fprint(cmd.OutOrStdout(), template)
+		},
+	}
+
+	command.Flags().StringVarP(&environment, "environment", "e", "", "environment to export (required)")
+
+	err := command.MarkFlagRequired("environment")
+
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	return command
+}
@@ -0,0 +1,44 @@
+package commands
+
+import (
+	"bytes"
+	"github.com/getflight/flight/mocks"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestExportCommand(t *testing.T) {
+	t.Run("command returns not nil command with subcommands", func(t *testing.T) {
+		// given
+		export := Export{}
+
+		// when
+		command := export.command()
+
+		// then
+		assert.NotNil(t, command)
+		assert.Equal(t, 1, len(command.Commands()))
+	})
+
+	t.Run("cloudformation command prints template of export service when command is ran", func(t *testing.T) {
+		// given
+		exportServiceMock := &mocks.ExportServiceMock{}
+		exportServiceMock.On("Cloudformation", "production").Return("Resources: {}\n", nil)
+
+		export := Export{
+			ExportService: exportServiceMock,
+		}
+
+		output := &bytes.Buffer{}
+		command := export.cloudformationCommand()
+		command.SetOut(output)
+		_ = command.Flags().Parse([]string{"-e", "production"})
+
+		// when
+		command.Run(command, []string{})
+
+		// then
+		assert.Equal(t, "Resources: {}\n", output.String())
+		exportServiceMock.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,37 @@
+package commands
+
+import (
+	"github.com/getflight/flight/service"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/spf13/cobra"
+)
+
+type Import struct {
+	ImportService service.ImportServiceType
+}
+
+func (i *Import) command() *cobra.Command {
+	var function string
+	var force bool
+
+	command := &cobra.Command{
+		Use:   "import <serverless.yml|template.yaml>",
+		Short: "Write flight.yml from a serverless.yml or AWS SAM template",
+		Long:  `Import translates a function of a serverless.yml or AWS SAM template to flight.yml, mapping its handler, events, environment variables and stages, and reports the settings it could not translate`,
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			err := i.ImportService.Import(args[0], function, force)
+
+			if err != nil {
+				log.Debugf("%+v", err)
+				log.Fatal(err.Error())
+			}
+		},
+	}
+
+	command.Flags().StringVarP(&function, "function", "f", "", "function to import when the file defines several")
+	command.Flags().BoolVar(&force, "force", false, "overwrite an existing flight.yml")
+
+	return command
+}
@@ -0,0 +1,39 @@
+package commands
+
+import (
+	"github.com/getflight/flight/mocks"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestImportCommand(t *testing.T) {
+	t.Run("command returns not nil command", func(t *testing.T) {
+		// given
+		importCommand := Import{}
+
+		// when
+		command := importCommand.command()
+
+		// then
+		assert.NotNil(t, command)
+	})
+
+	t.Run("command calls import service with file and function when command is ran", func(t *testing.T) {
+		// given
+		importServiceMock := &mocks.ImportServiceMock{}
+		importServiceMock.On("Import", "serverless.yml", "hello", false).Return(nil)
+
+		importCommand := Import{
+			ImportService: importServiceMock,
+		}
+
+		command := importCommand.command()
+		_ = command.Flags().Parse([]string{"--function", "hello"})
+
+		// when
+		command.Run(command, []string{"serverless.yml"})
+
+		// then
+		importServiceMock.AssertExpectations(t)
+	})
+}
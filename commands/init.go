@@ -0,0 +1,47 @@
+package commands
+
+import (
+	"github.com/getflight/flight/models"
+	"github.com/getflight/flight/service"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/spf13/cobra"
+)
+
+type Init struct {
+	InitService service.InitServiceType
+}
+
+func (i *Init) command() *cobra.Command {
+	options := models.InitOptions{}
+	var nonInteractive bool
+	var noIgnore bool
+
+	command := &cobra.Command{
+		Use:   "init",
+		Short: "Scaffold a flight.yml manifest for your project",
+		Long:  `Init detects the go module and main packages of the current directory and writes a commented flight.yml from a starter template (api, worker or cron), asking for the settings not given as flags`,
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Interactive = !nonInteractive
+			options.Ignore = !noIgnore
+
+			err := i.InitService.Init(options)
+
+			if err != nil {
+				log.Debugf("%+v", err)
+				log.Fatal(err.Error())
+			}
+		},
+	}
+
+	command.Flags().StringVar(&options.Name, "name", "", "project name, defaults to the name of the go module")
+	command.Flags().StringVarP(&options.Template, "template", "t", "", "starter template: api, worker or cron")
+	command.Flags().StringVar(&options.Main, "main", "", "main package to build, defaults to the detected one")
+	command.Flags().StringSliceVar(&options.Environments, "environments", nil, "environments to deploy to, defaults to dev")
+	command.Flags().StringSliceVar(&options.Databases, "databases", nil, "databases as name:driver, with driver mysql or postgresql")
+	command.Flags().BoolVar(&noIgnore, "no-flightignore", false, "do not write a .flightignore file")
+	command.Flags().BoolVarP(&nonInteractive, "yes", "y", false, "do not ask questions, use the flags and the detected or default values")
+	command.Flags().BoolVar(&options.Force, "force", false, "overwrite an existing flight.yml")
+
+	return command
+}
@@ -0,0 +1,45 @@
+package commands
+
+import (
+	"github.com/getflight/flight/mocks"
+	"github.com/getflight/flight/models"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestInitCommand(t *testing.T) {
+	t.Run("command returns not nil command", func(t *testing.T) {
+		// given
+		init := Init{}
+
+		// when
+		command := init.command()
+
+		// then
+		assert.NotNil(t, command)
+	})
+
+	t.Run("command calls init service with flag options when command is ran", func(t *testing.T) {
+		// given
+		initServiceMock := &mocks.InitServiceMock{}
+		initServiceMock.On("Init", models.InitOptions{
+			Name:         "api",
+			Template:     "worker",
+			Environments: []string{"dev", "prod"},
+			Ignore:       true,
+		}).Return(nil)
+
+		init := Init{
+			InitService: initServiceMock,
+		}
+
+		command := init.command()
+		_ = command.Flags().Parse([]string{"--name", "api", "--template", "worker", "--environments", "dev,prod", "--yes"})
+
+		// when
+		command.Run(command, []string{})
+
+		// then
+		initServiceMock.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,49 @@
+package commands
+
+import (
+	"fmt"
+	"github.com/getflight/flight/service"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/spf13/cobra"
+)
+
+type Keys struct {
+	KeyService service.KeyServiceType
+}
+
+func (k *Keys) command() *cobra.Command {
+	command := &cobra.Command{
+		Use:   "keys",
+		Short: "Manage the key signing your artifacts",
+		Long:  `Keys commands manage the ed25519 key signing the artifacts you deploy, environments with signing_keys only deploying artifacts signed by the keys they list`,
+	}
+
+	command.AddCommand(k.generateCommand())
+
+	return command
+}
+
+func (k *Keys) generateCommand() *cobra.Command {
+	var force bool
+
+	command := &cobra.Command{
+		Use:   "generate",
+		Short: "Generate your signing key and print its public key",
+		Long:  `Generate stores a new signing key in the work path and prints its public key, to be added to the signing_keys of your environments`,
+		Run: func(cmd *cobra.Command, args []string) {
+			publicKey, err := k.KeyService.Generate(force)
+
+			if err != nil {
+				log.Debugf("%+v", err)
+				log.Fatal(err.Error())
+			}
+
+			fmt.Fprintln(cmd.OutOrStdout(), publicKey)
+		},
+	}
+
+	command.Flags().BoolVar(&force, "force", false, "replace the existing signing key")
+
+	return command
+}
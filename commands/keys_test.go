@@ -0,0 +1,43 @@
+package commands
+
+import (
+	"bytes"
+	"github.com/getflight/flight/mocks"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestKeysCommand(t *testing.T) {
+	t.Run("command returns not nil command", func(t *testing.T) {
+		// given
+		keys := Keys{}
+
+		// when
+		command := keys.command()
+
+		// then
+		assert.NotNil(t, command)
+	})
+
+	t.Run("run generate command prints public key", func(t *testing.T) {
+		// given
+		keyServiceMock := &mocks.KeyServiceMock{}
+		keyServiceMock.On("Generate", true).Return("ed25519:key", nil)
+
+		keys := Keys{
+			KeyService: keyServiceMock,
+		}
+
+		output := &bytes.Buffer{}
+		command := keys.generateCommand()
+		command.SetOut(output)
+		_ = command.Flags().Set("force", "true")
+
+		// when
+		command.Run(command, []string{})
+
+		// then
+		assert.Equal(t, "ed25519:key\n", output.String())
+		keyServiceMock.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,34 @@
+package commands
+
+import (
+	"github.com/getflight/flight/service"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/spf13/cobra"
+)
+
+type Lint struct {
+	LintService service.LintServiceType
+}
+
+func (l *Lint) command() *cobra.Command {
+	var environment string
+
+	command := &cobra.Command{
+		Use:   "lint",
+		Short: "Check the flight.yml manifest against the policy of your organisation",
+		Long:  `Lint reports the values of flight.yml violating the built-in rules and the rules of .flight-policy.yml, violations at error severity also block flight deploy`,
+		Run: func(cmd *cobra.Command, args []string) {
+			err := l.LintService.Lint(environment)
+
+			if err != nil {
+				log.Debugf("%+v", err)
+				log.Fatal(err.Error())
+			}
+		},
+	}
+
+	command.Flags().StringVarP(&environment, "environment", "e", "", "environment to lint the manifest for (optional)")
+
+	return command
+}
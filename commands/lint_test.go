@@ -0,0 +1,39 @@
+package commands
+
+import (
+	"github.com/getflight/flight/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+)
+
+func TestLintCommand(t *testing.T) {
+	t.Run("command returns not nil command", func(t *testing.T) {
+		// given
+		lint := Lint{}
+
+		// when
+		command := lint.command()
+
+		// then
+		assert.NotNil(t, command)
+	})
+
+	t.Run("run command calls lint service when command is ran", func(t *testing.T) {
+		// given
+		lintServiceMock := &mocks.LintServiceMock{}
+		lintServiceMock.On("Lint", mock.Anything).Return(nil)
+
+		lint := Lint{
+			LintService: lintServiceMock,
+		}
+
+		command := lint.command()
+
+		// when
+		command.Run(command, []string{})
+
+		// then
+		lintServiceMock.AssertExpectations(t)
+	})
+}
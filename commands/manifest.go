@@ -0,0 +1,40 @@
+package commands
+
+import (
+	"github.com/getflight/flight/service"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/spf13/cobra"
+)
+
+type Manifest struct {
+	ManifestService service.ManifestServiceType
+}
+
+func (m *Manifest) command() *cobra.Command {
+	command := &cobra.Command{
+		Use:   "manifest",
+		Short: "Maintain the flight.yml manifest",
+		Long:  `Manifest commands apply to the flight.yml manifest of the current directory`,
+	}
+
+	command.AddCommand(m.migrateCommand())
+
+	return command
+}
+
+func (m *Manifest) migrateCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "migrate",
+		Short: "Upgrade flight.yml to the current manifest version",
+		Long:  `Migrate rewrites flight.yml in place in the current manifest version, replacing deprecated settings and keeping comments`,
+		Run: func(cmd *cobra.Command, args []string) {
+			err := m.ManifestService.Migrate()
+
+			if err != nil {
+				log.Debugf("%+v", err)
+				log.Fatal(err.Error())
+			}
+		},
+	}
+}
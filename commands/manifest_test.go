@@ -0,0 +1,39 @@
+package commands
+
+import (
+	"github.com/getflight/flight/mocks"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestManifestCommand(t *testing.T) {
+	t.Run("command returns not nil command with subcommands", func(t *testing.T) {
+		// given
+		manifest := Manifest{}
+
+		// when
+		command := manifest.command()
+
+		// then
+		assert.NotNil(t, command)
+		assert.Equal(t, 1, len(command.Commands()))
+	})
+
+	t.Run("migrate command calls manifest service when command is ran", func(t *testing.T) {
+		// given
+		manifestServiceMock := &mocks.ManifestServiceMock{}
+		manifestServiceMock.On("Migrate").Return(nil)
+
+		manifest := Manifest{
+			ManifestService: manifestServiceMock,
+		}
+
+		command := manifest.migrateCommand()
+
+		// when
+		command.Run(command, []string{})
+
+		// then
+		manifestServiceMock.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,36 @@
+package commands
+
+import (
+	"github.com/getflight/flight/service"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/spf13/cobra"
+)
+
+type Package struct {
+	PackageService service.PackageServiceType
+}
+
+func (p *Package) command() *cobra.Command {
+	var environment string
+	var output string
+
+	command := &cobra.Command{
+		Use:   "package",
+		Short: "Build the artifact zip without deploying it",
+		Long:  `Package writes the zip flight deploy uploads, so that it can be built offline, inspected, and its digest compared across machines, identical sources producing identical zips. The zip is signed with the key of the user, if any, and cached with its signature`,
+		Run: func(cmd *cobra.Command, args []string) {
+			err := p.PackageService.Package(environment, output)
+
+			if err != nil {
+				log.Debugf("%+v", err)
+				log.Fatal(err.Error())
+			}
+		},
+	}
+
+	command.Flags().StringVarP(&output, "output", "o", "main.zip", "file to write the artifact zip to")
+	command.Flags().StringVarP(&environment, "environment", "e", "", "environment to package the project for (optional)")
+
+	return command
+}
@@ -0,0 +1,40 @@
+package commands
+
+import (
+	"github.com/getflight/flight/mocks"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestPackageCommand(t *testing.T) {
+	t.Run("command returns not nil command", func(t *testing.T) {
+		// given
+		pkg := Package{}
+
+		// when
+		command := pkg.command()
+
+		// then
+		assert.NotNil(t, command)
+	})
+
+	t.Run("run command calls package service with output when command is ran", func(t *testing.T) {
+		// given
+		packageServiceMock := &mocks.PackageServiceMock{}
+		packageServiceMock.On("Package", "dev", "build.zip").Return(nil)
+
+		pkg := Package{
+			PackageService: packageServiceMock,
+		}
+
+		command := pkg.command()
+		_ = command.Flags().Set("output", "build.zip")
+		_ = command.Flags().Set("environment", "dev")
+
+		// when
+		command.Run(command, []string{})
+
+		// then
+		packageServiceMock.AssertExpectations(t)
+	})
+}
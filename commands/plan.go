@@ -0,0 +1,40 @@
+package commands
+
+import (
+	"github.com/getflight/flight/service"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/spf13/cobra"
+)
+
+type Plan struct {
+	PlanService service.PlanServiceType
+}
+
+func (p *Plan) command() *cobra.Command {
+	var environment string
+
+	command := &cobra.Command{
+		Use:   "plan",
+		Short: "Show what a deployment would configure",
+		Long:  `Plan validates flight.yml and shows the settings a deployment to the environment would apply, without deploying`,
+		Run: func(cmd *cobra.Command, args []string) {
+			err := p.PlanService.Plan(environment)
+
+			if err != nil {
+				log.Debugf("%+v", err)
+				log.Fatal(err.Error())
+			}
+		},
+	}
+
+	command.Flags().StringVarP(&environment, "environment", "e", "", "environment to plan the deployment for (required)")
+
+	err := command.MarkFlagRequired("environment")
+
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	return command
+}
@@ -0,0 +1,39 @@
+package commands
+
+import (
+	"github.com/getflight/flight/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+)
+
+func TestPlanCommand(t *testing.T) {
+	t.Run("command returns not nil command", func(t *testing.T) {
+		// given
+		plan := Plan{}
+
+		// when
+		command := plan.command()
+
+		// then
+		assert.NotNil(t, command)
+	})
+
+	t.Run("run command calls plan service when command is ran", func(t *testing.T) {
+		// given
+		planServiceMock := &mocks.PlanServiceMock{}
+		planServiceMock.On("Plan", mock.Anything).Return(nil)
+
+		plan := Plan{
+			PlanService: planServiceMock,
+		}
+
+		command := plan.command()
+
+		// when
+		command.Run(command, []string{})
+
+		// then
+		planServiceMock.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,79 @@
+package commands
+
+import (
+	"github.com/getflight/flight/service"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/spf13/cobra"
+)
+
+type Queue struct {
+	QueueService service.QueueServiceType
+}
+
+func (q *Queue) command() *cobra.Command {
+	command := &cobra.Command{
+		Use:   "queue",
+		Short: "Inspect and manage the queue triggering your project",
+		Long:  `Queue commands apply to projects deployed with the queue trigger`,
+	}
+
+	command.AddCommand(q.statusCommand())
+	command.AddCommand(q.redriveCommand())
+
+	return command
+}
+
+func (q *Queue) statusCommand() *cobra.Command {
+	var environment string
+
+	command := &cobra.Command{
+		Use:   "status",
+		Short: "Show the depth of the queue and of its dead letter queue",
+		Long:  `Status prints the number of available, in flight and delayed messages of the queue, and the number of messages in its dead letter queue`,
+		Run: func(cmd *cobra.Command, args []string) {
+			err := q.QueueService.Status(environment)
+
+			if err != nil {
+				log.Debugf("%+v", err)
+				log.Fatal(err.Error())
+			}
+		},
+	}
+
+	q.environmentFlag(command, &environment)
+
+	return command
+}
+
+func (q *Queue) redriveCommand() *cobra.Command {
+	var environment string
+
+	command := &cobra.Command{
+		Use:   "redrive",
+		Short: "Move the messages of the dead letter queue back to the queue",
+		Long:  `Redrive sends the messages of the dead letter queue back to the queue, so they are processed again by your project`,
+		Run: func(cmd *cobra.Command, args []string) {
+			err := q.QueueService.Redrive(environment)
+
+			if err != nil {
+				log.Debugf("%+v", err)
+				log.Fatal(err.Error())
+			}
+		},
+	}
+
+	q.environmentFlag(command, &environment)
+
+	return command
+}
+
+func (q *Queue) environmentFlag(command *cobra.Command, environment *string) {
+	command.Flags().StringVarP(environment, "environment", "e", "", "environment of the queue (required)")
+
+	err := command.MarkFlagRequired("environment")
+
+	if err != nil {
+		log.Fatal(err)
+	}
+}
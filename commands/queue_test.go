@@ -0,0 +1,58 @@
+package commands
+
+import (
+	"github.com/getflight/flight/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+)
+
+func TestQueueCommand(t *testing.T) {
+	t.Run("command returns not nil command with subcommands", func(t *testing.T) {
+		// given
+		queue := Queue{}
+
+		// when
+		command := queue.command()
+
+		// then
+		assert.NotNil(t, command)
+		assert.Equal(t, 2, len(command.Commands()))
+	})
+
+	t.Run("status command calls queue service when command is ran", func(t *testing.T) {
+		// given
+		queueServiceMock := &mocks.QueueServiceMock{}
+		queueServiceMock.On("Status", mock.Anything).Return(nil)
+
+		queue := Queue{
+			QueueService: queueServiceMock,
+		}
+
+		command := queue.statusCommand()
+
+		// when
+		command.Run(command, []string{})
+
+		// then
+		queueServiceMock.AssertExpectations(t)
+	})
+
+	t.Run("redrive command calls queue service when command is ran", func(t *testing.T) {
+		// given
+		queueServiceMock := &mocks.QueueServiceMock{}
+		queueServiceMock.On("Redrive", mock.Anything).Return(nil)
+
+		queue := Queue{
+			QueueService: queueServiceMock,
+		}
+
+		command := queue.redriveCommand()
+
+		// when
+		command.Run(command, []string{})
+
+		// then
+		queueServiceMock.AssertExpectations(t)
+	})
+}
@@ -10,8 +10,22 @@ import (
 )
 
 type Root struct {
+	ArtifactService   *service.ArtifactService
 	DeploymentService *service.DeploymentService
+	DomainService     *service.DomainService
+	ExportService     *service.ExportService
+	ImportService     *service.ImportService
+	InitService       *service.InitService
+	KeyService        *service.KeyService
+	LintService       *service.LintService
 	LoginService      *service.LoginService
+	ManifestService   *service.ManifestService
+	PackageService    *service.PackageService
+	PlanService       *service.PlanService
+	QueueService      *service.QueueService
+	SchemaService     *service.SchemaService
+	SecretService     *service.SecretService
+	ValidationService *service.ValidationService
 	VersionService    *service.VersionService
 	verbose           bool
 	workPath          string
@@ -34,8 +48,22 @@ func (r *Root) Execute() {
 	rootCmd.PersistentFlags().StringVar(&r.workPath, "work-path", "", "path to store local data")
 	rootCmd.PersistentFlags().StringVar(&r.apiUrl, "api-url", "", "configure a different api for flight to use when running commands")
 
+	rootCmd.AddCommand(r.artifactsCommand())
 	rootCmd.AddCommand(r.deployCommand())
+	rootCmd.AddCommand(r.domainsCommand())
+	rootCmd.AddCommand(r.exportCommand())
+	rootCmd.AddCommand(r.importCommand())
+	rootCmd.AddCommand(r.initCommand())
+	rootCmd.AddCommand(r.keysCommand())
+	rootCmd.AddCommand(r.lintCommand())
 	rootCmd.AddCommand(r.loginCommand())
+	rootCmd.AddCommand(r.manifestCommand())
+	rootCmd.AddCommand(r.packageCommand())
+	rootCmd.AddCommand(r.planCommand())
+	rootCmd.AddCommand(r.queueCommand())
+	rootCmd.AddCommand(r.schemaCommand())
+	rootCmd.AddCommand(r.secretsCommand())
+	rootCmd.AddCommand(r.validateCommand())
 	rootCmd.AddCommand(r.versionCommand())
 
 	if err := rootCmd.Execute(); err != nil {
@@ -58,6 +86,14 @@ func (r *Root) initConfig() {
 	}
 }
 
+func (r *Root) artifactsCommand() *cobra.Command {
+	artifacts := &Artifacts{
+		ArtifactService: r.ArtifactService,
+	}
+
+	return artifacts.command()
+}
+
 func (r *Root) deployCommand() *cobra.Command {
 	deploy := &Deploy{
 		DeploymentService: r.DeploymentService,
@@ -66,6 +102,54 @@ func (r *Root) deployCommand() *cobra.Command {
 	return deploy.command()
 }
 
+func (r *Root) domainsCommand() *cobra.Command {
+	domains := &Domains{
+		DomainService: r.DomainService,
+	}
+
+	return domains.command()
+}
+
+func (r *Root) exportCommand() *cobra.Command {
+	export := &Export{
+		ExportService: r.ExportService,
+	}
+
+	return export.command()
+}
+
+func (r *Root) importCommand() *cobra.Command {
+	importCommand := &Import{
+		ImportService: r.ImportService,
+	}
+
+	return importCommand.command()
+}
+
+func (r *Root) initCommand() *cobra.Command {
+	init := &Init{
+		InitService: r.InitService,
+	}
+
+	return init.command()
+}
+
+func (r *Root) keysCommand() *cobra.Command {
+	keys := &Keys{
+		KeyService: r.KeyService,
+	}
+
+	return keys.command()
+}
+
+func (r *Root) lintCommand() *cobra.Command {
+	lint := &Lint{
+		LintService: r.LintService,
+	}
+
+	return lint.command()
+}
+
 func (r *Root) loginCommand() *cobra.Command {
 	login := &Login{
 		LoginService: r.LoginService,
@@ -74,6 +158,62 @@ func (r *Root) loginCommand() *cobra.Command {
 	return login.command()
 }
 
+func (r *Root) manifestCommand() *cobra.Command {
+	manifest := &Manifest{
+		ManifestService: r.ManifestService,
+	}
+
+	return manifest.command()
+}
+
+func (r *Root) packageCommand() *cobra.Command {
+	pkg := &Package{
+		PackageService: r.PackageService,
+	}
+
+	return pkg.command()
+}
+
+func (r *Root) planCommand() *cobra.Command {
+	plan := &Plan{
+		PlanService: r.PlanService,
+	}
+
+	return plan.command()
+}
+
+func (r *Root) queueCommand() *cobra.Command {
+	queue := &Queue{
+		QueueService: r.QueueService,
+	}
+
+	return queue.command()
+}
+
+func (r *Root) schemaCommand() *cobra.Command {
+	schema := &Schema{
+		SchemaService: r.SchemaService,
+	}
+
+	return schema.command()
+}
+
+func (r *Root) secretsCommand() *cobra.Command {
+	secrets := &Secrets{
+		SecretService: r.SecretService,
+	}
+
+	return secrets.command()
+}
+
+func (r *Root) validateCommand() *cobra.Command {
+	validate := &Validate{
+		ValidationService: r.ValidationService,
+	}
+
+	return validate.command()
+}
+
 func (r *Root) versionCommand() *cobra.Command {
 	version := &Version{
 		VersionService: r.VersionService,
@@ -0,0 +1,31 @@
+package commands
+
+import (
+	"fmt"
+	"github.com/getflight/flight/service"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/spf13/cobra"
+)
+
+type Schema struct {
+	SchemaService service.SchemaServiceType
+}
+
+func (s *Schema) command() *cobra.Command {
+	return &cobra.Command{
+		Use:   "schema",
+		Short: "Print the json schema of the flight.yml manifest",
+		Long:  `Schema prints the json schema of flight.yml, to be used by editors for autocompletion or by pre-commit hooks for validation`,
+		Run: func(cmd *cobra.Command, args []string) {
+			schema, err := s.SchemaService.GetSchema()
+
+			if err != nil {
+				log.Debugf("%+v", err)
+				log.Fatal(err.Error())
+			}
+
+			fmt.Fprintln(cmd.OutOrStdout(), schema)
+		},
+	}
+}
@@ -0,0 +1,42 @@
+package commands
+
+import (
+	"bytes"
+	"github.com/getflight/flight/mocks"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestSchemaCommand(t *testing.T) {
+	t.Run("command returns not nil command", func(t *testing.T) {
+		// given
+		schema := Schema{}
+
+		// when
+		command := schema.command()
+
+		// then
+		assert.NotNil(t, command)
+	})
+
+	t.Run("run command prints schema from schema service when command is ran", func(t *testing.T) {
+		// given
+		schemaServiceMock := &mocks.SchemaServiceMock{}
+		schemaServiceMock.On("GetSchema").Return("{}", nil)
+
+		schema := Schema{
+			SchemaService: schemaServiceMock,
+		}
+
+		output := &bytes.Buffer{}
+		command := schema.command()
+		command.SetOut(output)
+
+		// when
+		command.Run(command, []string{})
+
+		// then
+		assert.Equal(t, "{}\n", output.String())
+		schemaServiceMock.AssertExpectations(t)
+	})
+}
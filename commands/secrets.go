@@ -0,0 +1,128 @@
+package commands
+
+import (
+	"fmt"
+	"github.com/getflight/flight/service"
+	"io"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/spf13/cobra"
+)
+
+type Secrets struct {
+	SecretService service.SecretServiceType
+}
+
+func (s *Secrets) command() *cobra.Command {
+	command := &cobra.Command{
+		Use:   "secrets",
+		Short: "Encrypt the values of secret variables",
+		Long:  `Secrets commands encrypt values to be committed in flight.yml as ENC[...], they are decrypted when deploying with the key stored in the work path`,
+	}
+
+	command.AddCommand(s.encryptCommand())
+	command.AddCommand(s.decryptCommand())
+	command.AddCommand(s.publicKeyCommand())
+	command.AddCommand(s.rotateKeyCommand())
+
+	return command
+}
+
+func (s *Secrets) encryptCommand() *cobra.Command {
+	var environment string
+
+	command := &cobra.Command{
+		Use:   "encrypt [value]",
+		Short: "Encrypt a value for the recipients of an environment",
+		Long:  `Encrypt prints the value encrypted for the recipients of the environment, or for your key when no environment or recipients are configured. The value is read from stdin when not given.`,
+		Args:  cobra.MaximumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			value, err := s.readValue(cmd, args)
+
+			if err == nil {
+				value, err = s.SecretService.Encrypt(environment, value)
+			}
+
+			if err != nil {
+				log.Debugf("%+v", err)
+				log.Fatal(err.Error())
+			}
+
+			fmt.Fprintln(cmd.OutOrStdout(), value)
+		},
+	}
+
+	command.Flags().StringVarP(&environment, "environment", "e", "", "environment whose recipients can decrypt the value")
+
+	return command
+}
+
+func (s *Secrets) decryptCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "decrypt [value]",
+		Short: "Decrypt an encrypted value",
+		Long:  `Decrypt prints a value encrypted for your key. The value is read from stdin when not given.`,
+		Args:  cobra.MaximumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			value, err := s.readValue(cmd, args)
+
+			if err == nil {
+				value, err = s.SecretService.Decrypt(value)
+			}
+
+			if err != nil {
+				log.Debugf("%+v", err)
+				log.Fatal(err.Error())
+			}
+
+			fmt.Fprintln(cmd.OutOrStdout(), value)
+		},
+	}
+}
+
+func (s *Secrets) publicKeyCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "public-key",
+		Short: "Print your public key",
+		Long:  `Public key prints your public key, to be added to the recipients of an environment in flight.yml`,
+		Run: func(cmd *cobra.Command, args []string) {
+			publicKey, err := s.SecretService.PublicKey()
+
+			if err != nil {
+				log.Debugf("%+v", err)
+				log.Fatal(err.Error())
+			}
+
+			fmt.Fprintln(cmd.OutOrStdout(), publicKey)
+		},
+	}
+}
+
+func (s *Secrets) rotateKeyCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "rotate-key",
+		Short: "Replace your key and encrypt the values of flight.yml again",
+		Long:  `Rotate key generates a new key, then encrypts every value of flight.yml again with your new public key in place of the previous one`,
+		Run: func(cmd *cobra.Command, args []string) {
+			err := s.SecretService.RotateKey()
+
+			if err != nil {
+				log.Debugf("%+v", err)
+				log.Fatal(err.Error())
+			}
+		},
+	}
+}
+
+// readValue returns the value given as argument, or read from stdin without its trailing newline
+func (s *Secrets) readValue(cmd *cobra.Command, args []string) (string, error) {
+	if len(args) > 0 {
+		return args[0], nil
+	}
+
+	content, err := io.ReadAll(cmd.InOrStdin())
+
+	return strings.TrimSuffix(string(content), "\n"), err
+}
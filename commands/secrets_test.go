@@ -0,0 +1,105 @@
+package commands
+
+import (
+	"bytes"
+	"github.com/getflight/flight/mocks"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestSecretsCommand(t *testing.T) {
+	t.Run("command returns not nil command with subcommands", func(t *testing.T) {
+		// given
+		secrets := Secrets{}
+
+		// when
+		command := secrets.command()
+
+		// then
+		assert.NotNil(t, command)
+		assert.Equal(t, 4, len(command.Commands()))
+	})
+
+	t.Run("encrypt command prints value encrypted by secret service when command is ran", func(t *testing.T) {
+		// given
+		secretServiceMock := &mocks.SecretServiceMock{}
+		secretServiceMock.On("Encrypt", "", "secret").Return("ENC[secret]", nil)
+
+		secrets := Secrets{
+			SecretService: secretServiceMock,
+		}
+
+		output := &bytes.Buffer{}
+		command := secrets.encryptCommand()
+		command.SetOut(output)
+
+		// when
+		command.Run(command, []string{"secret"})
+
+		// then
+		assert.Equal(t, "ENC[secret]\n", output.String())
+		secretServiceMock.AssertExpectations(t)
+	})
+
+	t.Run("decrypt command prints value read from stdin and decrypted by secret service when command is ran", func(t *testing.T) {
+		// given
+		secretServiceMock := &mocks.SecretServiceMock{}
+		secretServiceMock.On("Decrypt", "ENC[secret]").Return("secret", nil)
+
+		secrets := Secrets{
+			SecretService: secretServiceMock,
+		}
+
+		output := &bytes.Buffer{}
+		command := secrets.decryptCommand()
+		command.SetIn(strings.NewReader("ENC[secret]\n"))
+		command.SetOut(output)
+
+		// when
+		command.Run(command, []string{})
+
+		// then
+		assert.Equal(t, "secret\n", output.String())
+		secretServiceMock.AssertExpectations(t)
+	})
+
+	t.Run("public-key command prints public key from secret service when command is ran", func(t *testing.T) {
+		// given
+		secretServiceMock := &mocks.SecretServiceMock{}
+		secretServiceMock.On("PublicKey").Return("key", nil)
+
+		secrets := Secrets{
+			SecretService: secretServiceMock,
+		}
+
+		output := &bytes.Buffer{}
+		command := secrets.publicKeyCommand()
+		command.SetOut(output)
+
+		// when
+		command.Run(command, []string{})
+
+		// then
+		assert.Equal(t, "key\n", output.String())
+		secretServiceMock.AssertExpectations(t)
+	})
+
+	t.Run("rotate-key command calls secret service when command is ran", func(t *testing.T) {
+		// given
+		secretServiceMock := &mocks.SecretServiceMock{}
+		secretServiceMock.On("RotateKey").Return(nil)
+
+		secrets := Secrets{
+			SecretService: secretServiceMock,
+		}
+
+		command := secrets.rotateKeyCommand()
+
+		// when
+		command.Run(command, []string{})
+
+		// then
+		secretServiceMock.AssertExpectations(t)
+	})
+}
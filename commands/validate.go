@@ -0,0 +1,34 @@
+package commands
+
+import (
+	"github.com/getflight/flight/service"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/spf13/cobra"
+)
+
+type Validate struct {
+	ValidationService service.ValidationServiceType
+}
+
+func (v *Validate) command() *cobra.Command {
+	var environment string
+
+	command := &cobra.Command{
+		Use:   "validate",
+		Short: "Validate the flight.yml manifest",
+		Long:  `Validate reports every problem found in flight.yml along with its position in the file, the allowed values and suggestions for typos`,
+		Run: func(cmd *cobra.Command, args []string) {
+			err := v.ValidationService.Validate(environment)
+
+			if err != nil {
+				log.Debugf("%+v", err)
+				log.Fatal(err.Error())
+			}
+		},
+	}
+
+	command.Flags().StringVarP(&environment, "environment", "e", "", "environment to validate the manifest for (optional)")
+
+	return command
+}
@@ -0,0 +1,39 @@
+package commands
+
+import (
+	"github.com/getflight/flight/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+)
+
+func TestValidateCommand(t *testing.T) {
+	t.Run("command returns not nil command", func(t *testing.T) {
+		// given
+		validate := Validate{}
+
+		// when
+		command := validate.command()
+
+		// then
+		assert.NotNil(t, command)
+	})
+
+	t.Run("run command calls validation service when command is ran", func(t *testing.T) {
+		// given
+		validationServiceMock := &mocks.ValidationServiceMock{}
+		validationServiceMock.On("Validate", mock.Anything).Return(nil)
+
+		validate := Validate{
+			ValidationService: validationServiceMock,
+		}
+
+		command := validate.command()
+
+		// when
+		command.Run(command, []string{})
+
+		// then
+		validationServiceMock.AssertExpectations(t)
+	})
+}
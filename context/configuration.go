@@ -2,16 +2,34 @@ package context
 
 import (
 	"github.com/getflight/flight/models"
+	"github.com/mitchellh/mapstructure"
 	"github.com/pkg/errors"
+	"github.com/samber/lo"
+	"gopkg.in/yaml.v3"
+	"os"
+	"path/filepath"
+	"strings"
 
 	log "github.com/sirupsen/logrus"
 
 	"github.com/spf13/viper"
 )
 
+const (
+	manifestTagName = "json"
+)
+
+var (
+	// yamlExtensions are the extensions of the manifest files read as yaml, the other formats found by viper being
+	// decoded by viper
+	yamlExtensions = []string{".yaml", ".yml"}
+)
+
 type ConfigurationType interface {
 	Init() error
 	GetManifest() (models.Manifest, error)
+	GetManifestFile() string
+	GetManifestNode() (*yaml.Node, error)
 }
 
 type Configuration struct {
@@ -32,9 +50,44 @@ func (c *Configuration) Init() error {
 	return nil
 }
 
+// GetManifest returns the manifest, upgraded to the current version with a warning for each deprecated
+// setting found
 func (c *Configuration) GetManifest() (models.Manifest, error) {
 	manifest := models.Manifest{}
-	err := viper.Unmarshal(&manifest)
+	node, warnings, err := c.readManifestNode()
+
+	if err != nil {
+		return manifest, errors.WithStack(err)
+	}
+
+	for _, warning := range warnings {
+		log.Warnf("%s: %s, run flight manifest migrate to upgrade", c.GetManifestFile(), warning)
+	}
+
+	return DecodeManifest(node)
+}
+
+// DecodeManifest decodes a manifest document, the keys of the document being the json names of the fields
+func DecodeManifest(node *yaml.Node) (models.Manifest, error) {
+	manifest := models.Manifest{}
+	values := map[string]interface{}{}
+	err := node.Decode(&values)
+
+	if err != nil {
+		return manifest, errors.WithStack(err)
+	}
+
+	decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		TagName:          manifestTagName,
+		WeaklyTypedInput: true,
+		Result:           &manifest,
+	})
+
+	if err != nil {
+		return manifest, errors.WithStack(err)
+	}
+
+	err = decoder.Decode(values)
 
 	if err != nil {
 		return manifest, errors.WithStack(err)
@@ -42,3 +95,76 @@ func (c *Configuration) GetManifest() (models.Manifest, error) {
 
 	return manifest, nil
 }
+
+// GetManifestFile returns the path of the manifest file that was read during Init
+func (c *Configuration) GetManifestFile() string {
+	return viper.ConfigFileUsed()
+}
+
+// GetManifestNode returns the yaml document of the manifest file, upgraded to the current version. Unlike
+// GetManifest, the nodes keep track of the original keys and their position in the file.
+func (c *Configuration) GetManifestNode() (*yaml.Node, error) {
+	node, _, err := c.readManifestNode()
+
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return node, nil
+}
+
+// IsYamlManifest returns whether the manifest file is a yaml file, which flight can edit in place
+func IsYamlManifest(file string) bool {
+	return lo.Contains[string](yamlExtensions, strings.ToLower(filepath.Ext(file)))
+}
+
+func (c *Configuration) readManifestNode() (*yaml.Node, []string, error) {
+	node, err := c.decodeManifestNode()
+
+	if err != nil {
+		return nil, nil, errors.WithStack(err)
+	}
+
+	_, warnings, err := MigrateManifest(node)
+
+	if err != nil {
+		return nil, nil, errors.WithStack(err)
+	}
+
+	return node, warnings, nil
+}
+
+// decodeManifestNode parses a yaml manifest file, keeping the position of its nodes. Manifests in the other
+// formats supported by viper, such as toml or hcl, are decoded by viper, their nodes having no position.
+func (c *Configuration) decodeManifestNode() (*yaml.Node, error) {
+	file := c.GetManifestFile()
+	node := &yaml.Node{}
+
+	if !IsYamlManifest(file) {
+		settings := &yaml.Node{}
+		err := settings.Encode(viper.AllSettings())
+
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+
+		node.Kind = yaml.DocumentNode
+		node.Content = []*yaml.Node{settings}
+
+		return node, nil
+	}
+
+	content, err := os.ReadFile(file)
+
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	err = yaml.Unmarshal(content, node)
+
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return node, nil
+}
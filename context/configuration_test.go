@@ -3,6 +3,8 @@ package context
 import (
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
 	"testing"
 )
 
@@ -18,4 +20,37 @@ func TestConfiguration(t *testing.T) {
 		assert.NotNil(t, err)
 		assert.IsType(t, viper.ConfigFileNotFoundError{}, err)
 	})
+
+	t.Run("GetManifest with toml manifest decodes manifest through viper", func(t *testing.T) {
+		// given
+		file := filepath.Join(t.TempDir(), "flight.toml")
+		assert.Nil(t, os.WriteFile(file, []byte("name = \"app\"\nfiles = [\"static\"]\n\n[[environments]]\nname = \"dev\"\n"), 0644))
+
+		viper.SetConfigFile(file)
+		assert.Nil(t, viper.ReadInConfig())
+		t.Cleanup(viper.Reset)
+
+		configuration := Configuration{}
+
+		// when
+		manifest, err := configuration.GetManifest()
+
+		// then
+		assert.Nil(t, err)
+		assert.Equal(t, "app", manifest.Name)
+		assert.Equal(t, []string{"static"}, *manifest.Package.Includes)
+		assert.Equal(t, "dev", manifest.Environments[0].Name)
+	})
+
+	t.Run("IsYamlManifest returns whether manifest is yaml", func(t *testing.T) {
+		// when
+		yml := IsYamlManifest("flight.yml")
+		yaml := IsYamlManifest("flight.YAML")
+		toml := IsYamlManifest("flight.toml")
+
+		// then
+		assert.True(t, yml)
+		assert.True(t, yaml)
+		assert.False(t, toml)
+	})
 }
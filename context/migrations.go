@@ -0,0 +1,157 @@
+package context
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// ManifestVersion is the version of the manifest format supported by this release
+	ManifestVersion = 2
+	versionKey      = "version"
+)
+
+// manifestMigration upgrades the manifest document from the previous version to its version, returning a
+// deprecation warning for each change made
+type manifestMigration struct {
+	version int
+	migrate func(root *yaml.Node) []string
+}
+
+var (
+	manifestMigrations = []manifestMigration{
+		{version: 2, migrate: migrateFilesToPackageIncludes},
+	}
+)
+
+// MigrateManifest upgrades the manifest document to the current version in place, keeping the comments and
+// positions of the nodes, and returns the version the manifest was in. Manifests without a version are of
+// version 1.
+func MigrateManifest(document *yaml.Node) (int, []string, error) {
+	root := document
+
+	if root.Kind == yaml.DocumentNode && len(root.Content) > 0 {
+		root = root.Content[0]
+	}
+
+	if root.Kind != yaml.MappingNode {
+		return 0, nil, nil
+	}
+
+	version := 1
+	versionNode := findMappingValue(root, versionKey)
+
+	if versionNode != nil {
+		var err error
+		version, err = strconv.Atoi(versionNode.Value)
+
+		if err != nil || version < 1 {
+			return 0, nil, errors.New(fmt.Sprintf("manifest version %s must be a positive number", versionNode.Value))
+		}
+	}
+
+	if version > ManifestVersion {
+		return 0, nil, errors.New(fmt.Sprintf("manifest version %d is not supported by this release of flight, update flight to use it", version))
+	}
+
+	if version == ManifestVersion {
+		return version, nil, nil
+	}
+
+	var warnings []string
+
+	for _, migration := range manifestMigrations {
+		if migration.version > version {
+			warnings = append(warnings, migration.migrate(root)...)
+		}
+	}
+
+	if versionNode == nil {
+		versionKeyNode := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: versionKey}
+
+		// the comment at the top of the file stays above the version
+		if len(root.Content) > 0 {
+			versionKeyNode.HeadComment = root.Content[0].HeadComment
+			root.Content[0].HeadComment = ""
+		}
+
+		root.Content = append([]*yaml.Node{
+			versionKeyNode,
+			{Kind: yaml.ScalarNode, Tag: "!!int", Value: strconv.Itoa(ManifestVersion)},
+		}, root.Content...)
+	} else {
+		versionNode.Value = strconv.Itoa(ManifestVersion)
+	}
+
+	return version, warnings, nil
+}
+
+// migrateFilesToPackageIncludes moves the files included in the artifact from files to package.includes, the
+// files being appended to the includes when the package already has some
+func migrateFilesToPackageIncludes(root *yaml.Node) []string {
+	index := findMappingIndex(root, "files")
+
+	if index < 0 {
+		return nil
+	}
+
+	packageNode := findMappingValue(root, "package")
+
+	if packageNode != nil && packageNode.Kind != yaml.MappingNode {
+		return []string{"files is deprecated since manifest version 2, move it to package.includes"}
+	}
+
+	key, value := root.Content[index], root.Content[index+1]
+	var includesNode *yaml.Node
+
+	if packageNode != nil {
+		includesNode = findMappingValue(packageNode, "includes")
+	}
+
+	if includesNode != nil && (includesNode.Kind != yaml.SequenceNode || value.Kind != yaml.SequenceNode) {
+		return []string{"files is deprecated since manifest version 2, move it to package.includes"}
+	}
+
+	root.Content = append(root.Content[:index], root.Content[index+2:]...)
+
+	if includesNode != nil {
+		includesNode.Content = append(includesNode.Content, value.Content...)
+
+		return []string{"files is deprecated since manifest version 2, its files were added to package.includes"}
+	}
+
+	if packageNode == nil {
+		// the package takes the place of the files in the document
+		packageNode = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		packageKey := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: "package"}
+		root.Content = append(root.Content[:index], append([]*yaml.Node{packageKey, packageNode}, root.Content[index:]...)...)
+	}
+
+	key.Value = "includes"
+	packageNode.Content = append(packageNode.Content, key, value)
+
+	return []string{"files is deprecated since manifest version 2, use package.includes instead"}
+}
+
+func findMappingIndex(node *yaml.Node, key string) int {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func findMappingValue(node *yaml.Node, key string) *yaml.Node {
+	index := findMappingIndex(node, key)
+
+	if index < 0 {
+		return nil
+	}
+
+	return node.Content[index+1]
+}
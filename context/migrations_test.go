@@ -0,0 +1,102 @@
+package context
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+	"testing"
+)
+
+func TestMigrations(t *testing.T) {
+	t.Run("MigrateManifest without version moves files to package includes", func(t *testing.T) {
+		// given
+		document := getDocument(t, "# project\nname: app\nfiles:\n  # assets\n  - static\ntrigger: gateway\n")
+
+		// when
+		version, warnings, err := MigrateManifest(document)
+
+		// then
+		assert.Nil(t, err)
+		assert.Equal(t, 1, version)
+		assert.Equal(t, []string{"files is deprecated since manifest version 2, use package.includes instead"}, warnings)
+		assert.Equal(t, "# project\nversion: 2\nname: app\npackage:\n  includes:\n    # assets\n    - static\ntrigger: gateway\n", encodeDocument(t, document))
+	})
+
+	t.Run("MigrateManifest with files and package includes appends files to includes", func(t *testing.T) {
+		// given
+		document := getDocument(t, "name: app\nfiles:\n  - static\npackage:\n  includes:\n    - configs\n")
+
+		// when
+		_, warnings, err := MigrateManifest(document)
+
+		// then
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"files is deprecated since manifest version 2, its files were added to package.includes"}, warnings)
+
+		migrated := encodeDocument(t, document)
+		assert.Equal(t, "version: 2\nname: app\npackage:\n  includes:\n    - configs\n    - static\n", migrated)
+		assert.Nil(t, yaml.Unmarshal([]byte(migrated), &yaml.Node{}))
+	})
+
+	t.Run("MigrateManifest with files and package includes that are not lists keeps files", func(t *testing.T) {
+		// given
+		content := "name: app\nfiles: static\npackage:\n  includes:\n    - configs\n"
+		document := getDocument(t, content)
+
+		// when
+		_, warnings, err := MigrateManifest(document)
+
+		// then
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"files is deprecated since manifest version 2, move it to package.includes"}, warnings)
+		assert.Equal(t, "version: 2\n"+content, encodeDocument(t, document))
+	})
+
+	t.Run("MigrateManifest with current version does not change manifest", func(t *testing.T) {
+		// given
+		content := "version: 2\nname: app\n"
+		document := getDocument(t, content)
+
+		// when
+		version, warnings, err := MigrateManifest(document)
+
+		// then
+		assert.Nil(t, err)
+		assert.Equal(t, 2, version)
+		assert.Empty(t, warnings)
+		assert.Equal(t, content, encodeDocument(t, document))
+	})
+
+	t.Run("MigrateManifest with newer version returns error", func(t *testing.T) {
+		// given
+		document := getDocument(t, "version: 3\nname: app\n")
+
+		// when
+		_, _, err := MigrateManifest(document)
+
+		// then
+		assert.NotNil(t, err)
+	})
+}
+
+func getDocument(t *testing.T, content string) *yaml.Node {
+	document := &yaml.Node{}
+
+	if err := yaml.Unmarshal([]byte(content), document); err != nil {
+		t.Fatal(err)
+	}
+
+	return document
+}
+
+func encodeDocument(t *testing.T, document *yaml.Node) string {
+	content := &bytes.Buffer{}
+	encoder := yaml.NewEncoder(content)
+	encoder.SetIndent(2)
+
+	if err := encoder.Encode(document); err != nil {
+		t.Fatal(err)
+	}
+
+	return content.String()
+}
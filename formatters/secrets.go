@@ -0,0 +1,46 @@
+package formatters
+
+import (
+	"encoding/json"
+	"strings"
+	"sync"
+)
+
+const (
+	secretMask = "****"
+)
+
+var (
+	secrets      []string
+	secretsMutex sync.RWMutex
+)
+
+// MaskSecret hides the given value in every log written through the formatters from now on. The json encoded
+// form of the value is hidden as well, so the secret does not leak through request dumps.
+func MaskSecret(secret string) {
+	if secret == "" {
+		return
+	}
+
+	secretsMutex.Lock()
+	defer secretsMutex.Unlock()
+
+	secrets = append(secrets, secret)
+
+	encoded, err := json.Marshal(secret)
+
+	if err == nil && string(encoded[1:len(encoded)-1]) != secret {
+		secrets = append(secrets, string(encoded[1:len(encoded)-1]))
+	}
+}
+
+func maskSecrets(message string) string {
+	secretsMutex.RLock()
+	defer secretsMutex.RUnlock()
+
+	for _, secret := range secrets {
+		message = strings.ReplaceAll(message, secret, secretMask)
+	}
+
+	return message
+}
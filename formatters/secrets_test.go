@@ -0,0 +1,38 @@
+package formatters
+
+import (
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestSecrets(t *testing.T) {
+	t.Run("Format masks registered secrets", func(t *testing.T) {
+		// given
+		MaskSecret("p4ss\"word")
+		formatter := TextFormatter{}
+		entry := &log.Entry{
+			Time:    time.Now(),
+			Message: `password p4ss"word in body {"value":"p4ss\"word"}`,
+		}
+
+		// when
+		result, err := formatter.Format(entry)
+
+		// then
+		assert.Nil(t, err)
+		assert.Equal(t, `password **** in body {"value":"****"}`+"\n", string(result))
+	})
+
+	t.Run("MaskSecret ignores empty secret", func(t *testing.T) {
+		// given
+		MaskSecret("")
+
+		// when
+		result := maskSecrets("message")
+
+		// then
+		assert.Equal(t, "message", result)
+	})
+}
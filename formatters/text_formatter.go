@@ -36,5 +36,5 @@ func (f *TextFormatter) appendValue(b *bytes.Buffer, value interface{}) {
 		stringVal = fmt.Sprint(value)
 	}
 
-	b.WriteString(stringVal)
+	b.WriteString(maskSecrets(stringVal))
 }
@@ -39,5 +39,5 @@ func (f *TimestampFormatter) appendValue(b *bytes.Buffer, value interface{}) {
 
 	stringVal = fmt.Sprintf("%s %s", time.Now().Format("15:04:05"), stringVal)
 
-	b.WriteString(stringVal)
+	b.WriteString(maskSecrets(stringVal))
 }
@@ -0,0 +1,261 @@
+package helpers
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"github.com/getflight/flight/models"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/samber/lo"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	artifactsWorkPath = "artifacts"
+	// artifactDigestAlgorithm is the directory of the cached artifacts, named after the algorithm of their digest
+	artifactDigestAlgorithm = "sha256"
+	cachedZipExtension      = ".zip"
+	cachedMetadataExtension = ".json"
+	cacheMode               = 0755
+	// minDigestPrefix is the shortest digest prefix accepted as a reference to a cached artifact
+	minDigestPrefix = 7
+)
+
+// CacheArtifact stores an artifact zip in the work path under its digest, along with its metadata. The zip of
+// an artifact already cached is kept, as identical digests have identical contents, while its metadata is
+// completed with the fields given, such as the id of the artifact once uploaded.
+func (h *FileHelper) CacheArtifact(artifact models.CachedArtifact, content string) error {
+	artifact.Digest = ArtifactDigest(content)
+	artifact.Size = int64(len(content))
+	artifact.CachedAt = time.Now().UTC()
+
+	zipPath, err := h.getCachedArtifactPath(artifact.Digest, cachedZipExtension)
+
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	err = h.FileSystem.MkdirAll(filepath.Dir(zipPath), cacheMode)
+
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	if _, err = h.FileSystem.Stat(zipPath); err != nil {
+		// Written under a temporary name first, so that other commands never read a partial zip
+		temporaryPath := fmt.Sprintf("%s.%d.tmp", zipPath, os.Getpid())
+		err = h.FileSystem.WriteFile(temporaryPath, []byte(content), 0644)
+
+		if err != nil {
+			return errors.WithStack(err)
+		}
+
+		err = h.FileSystem.Rename(temporaryPath, zipPath)
+
+		if err != nil {
+			return errors.WithStack(err)
+		}
+	}
+
+	metadataPath, err := h.getCachedArtifactPath(artifact.Digest, cachedMetadataExtension)
+
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	unlock, err := h.lockFile(metadataPath)
+
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	defer unlock()
+
+	if cached, err := h.readCachedMetadata(metadataPath); err == nil {
+		artifact = mergeCachedArtifact(cached, artifact)
+	}
+
+	metadata, err := json.MarshalIndent(artifact, "", "  ")
+
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	log.Debugf("caching artifact %s in %s", artifact.Digest, zipPath)
+
+	return errors.WithStack(h.FileSystem.WriteFile(metadataPath, metadata, 0644))
+}
+
+// CachedArtifacts returns the cached artifacts, the most recently cached first
+func (h *FileHelper) CachedArtifacts() ([]models.CachedArtifact, error) {
+	cachePath, err := h.getWorkPath(filepath.Join(artifactsWorkPath, artifactDigestAlgorithm))
+
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	entries, err := h.FileSystem.ReadDir(cachePath)
+
+	if os.IsNotExist(err) {
+		return []models.CachedArtifact{}, nil
+	}
+
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	artifacts := []models.CachedArtifact{}
+
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != cachedMetadataExtension {
+			continue
+		}
+
+		artifact, err := h.readCachedMetadata(filepath.Join(cachePath, entry.Name()))
+
+		if err != nil {
+			log.Warnf("skipping cached artifact %s: %v", entry.Name(), err)
+
+			continue
+		}
+
+		artifacts = append(artifacts, artifact)
+	}
+
+	sort.SliceStable(artifacts, func(i, j int) bool {
+		return artifacts[i].CachedAt.After(artifacts[j].CachedAt)
+	})
+
+	return artifacts, nil
+}
+
+// CachedArtifact returns the cached artifact with the given id, digest or digest prefix, which is nil when the
+// artifact is not cached
+func (h *FileHelper) CachedArtifact(reference string) (*models.CachedArtifact, error) {
+	artifacts, err := h.CachedArtifacts()
+
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	var matches []models.CachedArtifact
+
+	for _, artifact := range artifacts {
+		if artifact.ID == reference || artifact.Digest == reference {
+			return &artifact, nil
+		}
+
+		if len(reference) >= minDigestPrefix && strings.HasPrefix(strings.TrimPrefix(artifact.Digest, artifactDigestAlgorithm+":"), reference) {
+			matches = append(matches, artifact)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return nil, nil
+	case 1:
+		return &matches[0], nil
+	}
+
+	return nil, errors.New(fmt.Sprintf("%s matches %d cached artifacts, give a longer digest", reference, len(matches)))
+}
+
+// ReadCachedArtifact returns the zip of a cached artifact, checking that it still has the digest it is
+// stored under
+func (h *FileHelper) ReadCachedArtifact(digest string) (string, error) {
+	zipPath, err := h.getCachedArtifactPath(digest, cachedZipExtension)
+
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+
+	content, err := h.FileSystem.ReadFile(zipPath)
+
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+
+	if ArtifactDigest(string(content)) != digest {
+		return "", errors.New(fmt.Sprintf("cached artifact %s does not match its digest, remove %s", digest, zipPath))
+	}
+
+	return string(content), nil
+}
+
+// PruneCachedArtifacts removes the artifacts cached before the given age and returns them
+func (h *FileHelper) PruneCachedArtifacts(olderThan time.Duration) ([]models.CachedArtifact, error) {
+	artifacts, err := h.CachedArtifacts()
+
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	limit := time.Now().Add(-olderThan)
+	var pruned []models.CachedArtifact
+
+	for _, artifact := range artifacts {
+		if !artifact.CachedAt.Before(limit) {
+			continue
+		}
+
+		for _, extension := range []string{cachedZipExtension, cachedMetadataExtension} {
+			path, err := h.getCachedArtifactPath(artifact.Digest, extension)
+
+			if err != nil {
+				return pruned, errors.WithStack(err)
+			}
+
+			err = h.FileSystem.Remove(path)
+
+			if err != nil && !os.IsNotExist(err) {
+				return pruned, errors.WithStack(err)
+			}
+		}
+
+		pruned = append(pruned, artifact)
+	}
+
+	return pruned, nil
+}
+
+// getCachedArtifactPath returns the path of a file of a cached artifact, named after the hexadecimal part of its
+// digest
+func (h *FileHelper) getCachedArtifactPath(digest string, extension string) (string, error) {
+	algorithm, sum, found := strings.Cut(digest, ":")
+
+	if _, err := hex.DecodeString(sum); !found || algorithm != artifactDigestAlgorithm || err != nil || sum == "" {
+		return "", errors.New(fmt.Sprintf("%s is not a %s artifact digest", digest, artifactDigestAlgorithm))
+	}
+
+	return h.getWorkPath(filepath.Join(artifactsWorkPath, artifactDigestAlgorithm, sum+extension))
+}
+
+func (h *FileHelper) readCachedMetadata(path string) (models.CachedArtifact, error) {
+	artifact := models.CachedArtifact{}
+	content, err := h.FileSystem.ReadFile(path)
+
+	if err != nil {
+		return artifact, errors.WithStack(err)
+	}
+
+	err = json.Unmarshal(content, &artifact)
+
+	return artifact, errors.WithStack(err)
+}
+
+// mergeCachedArtifact keeps the metadata of a cached artifact that a later packaging does not know about. The
+// signature is never kept, as it belongs to the packaging or deployment that cached the artifact last.
+func mergeCachedArtifact(cached models.CachedArtifact, artifact models.CachedArtifact) models.CachedArtifact {
+	artifact.ID = lo.Ternary[string](artifact.ID != "", artifact.ID, cached.ID)
+	artifact.Project = lo.Ternary[string](artifact.Project != "", artifact.Project, cached.Project)
+	artifact.CommitHash = lo.Ternary[string](artifact.CommitHash != "", artifact.CommitHash, cached.CommitHash)
+	artifact.CommitMessage = lo.Ternary[string](artifact.CommitMessage != "", artifact.CommitMessage, cached.CommitMessage)
+
+	return artifact
+}
@@ -0,0 +1,165 @@
+package helpers
+
+import (
+	"encoding/json"
+	"github.com/getflight/flight/models"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestArtifactCache(t *testing.T) {
+	t.Run("CacheArtifact stores zip under digest and completes metadata", func(t *testing.T) {
+		// given
+		fileHelper := getArtifactCacheHelper(t)
+		content := getScanZip(t, map[string]string{"main": "executable"})
+		digest := ArtifactDigest(content)
+
+		// when
+		packageErr := fileHelper.CacheArtifact(models.CachedArtifact{Project: "test", CommitHash: "abc"}, content)
+		deployErr := fileHelper.CacheArtifact(models.CachedArtifact{ID: "1", KeyID: "key"}, content)
+
+		// then
+		assert.Nil(t, packageErr)
+		assert.Nil(t, deployErr)
+
+		zipPath := filepath.Join(UserWorkPath, ".flight", "artifacts", "sha256", strings.TrimPrefix(digest, "sha256:")+".zip")
+		stored, err := os.ReadFile(zipPath)
+		assert.Nil(t, err)
+		assert.Equal(t, content, string(stored))
+
+		artifacts, err := fileHelper.CachedArtifacts()
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(artifacts))
+		assert.Equal(t, "1", artifacts[0].ID)
+		assert.Equal(t, digest, artifacts[0].Digest)
+		assert.Equal(t, "test", artifacts[0].Project)
+		assert.Equal(t, "abc", artifacts[0].CommitHash)
+		assert.Equal(t, "key", artifacts[0].KeyID)
+		assert.Equal(t, int64(len(content)), artifacts[0].Size)
+	})
+
+	t.Run("CacheArtifact does not keep signature of artifact cached unsigned again", func(t *testing.T) {
+		// given
+		fileHelper := getArtifactCacheHelper(t)
+		content := getScanZip(t, map[string]string{"main": "executable"})
+		assert.Nil(t, fileHelper.CacheArtifact(models.CachedArtifact{ID: "1", Signature: "signature", KeyID: "key"}, content))
+
+		// when
+		err := fileHelper.CacheArtifact(models.CachedArtifact{Project: "test"}, content)
+
+		// then
+		assert.Nil(t, err)
+
+		artifacts, _ := fileHelper.CachedArtifacts()
+		assert.Equal(t, "1", artifacts[0].ID)
+		assert.Equal(t, "test", artifacts[0].Project)
+		assert.Empty(t, artifacts[0].Signature)
+		assert.Empty(t, artifacts[0].KeyID)
+	})
+
+	t.Run("CachedArtifacts without cache returns empty list", func(t *testing.T) {
+		// given
+		fileHelper := getArtifactCacheHelper(t)
+
+		// when
+		artifacts, err := fileHelper.CachedArtifacts()
+
+		// then
+		assert.Nil(t, err)
+		assert.Empty(t, artifacts)
+	})
+
+	t.Run("CachedArtifact finds artifact by id, digest and digest prefix", func(t *testing.T) {
+		// given
+		fileHelper := getArtifactCacheHelper(t)
+		content := getScanZip(t, map[string]string{"main": "executable"})
+		digest := ArtifactDigest(content)
+		assert.Nil(t, fileHelper.CacheArtifact(models.CachedArtifact{ID: "1"}, content))
+
+		// when
+		byID, idErr := fileHelper.CachedArtifact("1")
+		byDigest, digestErr := fileHelper.CachedArtifact(digest)
+		byPrefix, prefixErr := fileHelper.CachedArtifact(digest[len("sha256:") : len("sha256:")+minDigestPrefix])
+		unknown, unknownErr := fileHelper.CachedArtifact("2")
+
+		// then
+		assert.Nil(t, idErr)
+		assert.Nil(t, digestErr)
+		assert.Nil(t, prefixErr)
+		assert.Nil(t, unknownErr)
+		assert.Equal(t, digest, byID.Digest)
+		assert.Equal(t, digest, byDigest.Digest)
+		assert.Equal(t, digest, byPrefix.Digest)
+		assert.Nil(t, unknown)
+	})
+
+	t.Run("ReadCachedArtifact with modified zip returns error", func(t *testing.T) {
+		// given
+		fileHelper := getArtifactCacheHelper(t)
+		content := getScanZip(t, map[string]string{"main": "executable"})
+		digest := ArtifactDigest(content)
+		assert.Nil(t, fileHelper.CacheArtifact(models.CachedArtifact{}, content))
+
+		zipPath, _ := fileHelper.getCachedArtifactPath(digest, cachedZipExtension)
+		assert.Nil(t, os.WriteFile(zipPath, []byte("modified"), 0644))
+
+		// when
+		_, err := fileHelper.ReadCachedArtifact(digest)
+
+		// then
+		assert.ErrorContains(t, err, "does not match its digest")
+	})
+
+	t.Run("PruneCachedArtifacts removes artifacts cached before age", func(t *testing.T) {
+		// given
+		fileHelper := getArtifactCacheHelper(t)
+		old := getScanZip(t, map[string]string{"main": "old"})
+		recent := getScanZip(t, map[string]string{"main": "recent"})
+		assert.Nil(t, fileHelper.CacheArtifact(models.CachedArtifact{}, old))
+		assert.Nil(t, fileHelper.CacheArtifact(models.CachedArtifact{}, recent))
+
+		metadataPath, _ := fileHelper.getCachedArtifactPath(ArtifactDigest(old), cachedMetadataExtension)
+		metadata, _ := json.Marshal(models.CachedArtifact{Digest: ArtifactDigest(old), CachedAt: time.Now().Add(-31 * 24 * time.Hour)})
+		assert.Nil(t, os.WriteFile(metadataPath, metadata, 0644))
+
+		// when
+		pruned, err := fileHelper.PruneCachedArtifacts(30 * 24 * time.Hour)
+
+		// then
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(pruned))
+		assert.Equal(t, ArtifactDigest(old), pruned[0].Digest)
+
+		artifacts, _ := fileHelper.CachedArtifacts()
+		assert.Equal(t, 1, len(artifacts))
+		assert.Equal(t, ArtifactDigest(recent), artifacts[0].Digest)
+
+		zipPath, _ := fileHelper.getCachedArtifactPath(ArtifactDigest(old), cachedZipExtension)
+		_, statErr := os.Stat(zipPath)
+		assert.True(t, os.IsNotExist(statErr))
+	})
+
+	t.Run("getCachedArtifactPath with invalid digest returns error", func(t *testing.T) {
+		// given
+		fileHelper := getArtifactCacheHelper(t)
+
+		// when
+		_, err := fileHelper.getCachedArtifactPath("sha256:../../token", cachedZipExtension)
+
+		// then
+		assert.EqualError(t, err, "sha256:../../token is not a sha256 artifact digest")
+	})
+}
+
+func getArtifactCacheHelper(t *testing.T) *FileHelper {
+	UserWorkPath = t.TempDir()
+	t.Cleanup(func() {
+		UserWorkPath = ""
+	})
+
+	return &FileHelper{FileSystem: &FileSystem{}}
+}
@@ -0,0 +1,126 @@
+package helpers
+
+import (
+	"bytes"
+	"debug/buildinfo"
+	"debug/elf"
+	"debug/macho"
+	"debug/pe"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	defaultArchitecture = "x86_64"
+	targetOs            = "linux"
+)
+
+var (
+	architectureMachines = map[string]elf.Machine{"x86_64": elf.EM_X86_64, "arm64": elf.EM_AARCH64}
+	architectureGoArchs  = map[string]string{"x86_64": "amd64", "arm64": "arm64"}
+)
+
+// checkExecutable verifies that the executable is a linux elf file built for the architecture declared in the
+// manifest, as lambda only reports other executables when the function is invoked. Dynamically linked and cgo
+// executables are reported, as the libraries they need may be missing from the lambda runtime.
+func checkExecutable(name string, data []byte, architecture string) error {
+	if architecture == "" {
+		architecture = defaultArchitecture
+	}
+
+	machine, found := architectureMachines[architecture]
+
+	if !found {
+		return errors.New(fmt.Sprintf("unsupported architecture %s", architecture))
+	}
+
+	goArch := architectureGoArchs[architecture]
+	file, err := elf.NewFile(bytes.NewReader(data))
+
+	if err != nil {
+		log.Debugf("executable %s is not an elf file: %v", name, err)
+
+		return errors.New(fmt.Sprintf("executable %s is %s, not a linux elf file, rebuild it with GOOS=%s GOARCH=%s", name, executableFormat(data), targetOs, goArch))
+	}
+
+	if file.Machine != machine {
+		return errors.New(fmt.Sprintf("executable %s is built for %s but the manifest declares %s, rebuild it with GOARCH=%s", name, file.Machine, architecture, goArch))
+	}
+
+	for _, prog := range file.Progs {
+		if prog.Type == elf.PT_INTERP {
+			libraries, _ := file.ImportedLibraries()
+			log.Warnf("executable %s is dynamically linked %s, rebuild it with CGO_ENABLED=0 unless the lambda runtime provides them", name, formatLibraries(libraries))
+
+			break
+		}
+	}
+
+	return checkBuildInfo(name, data)
+}
+
+// checkBootstrap verifies that a bootstrap script can be run by lambda, which needs the interpreter on the
+// first line and fails on the carriage returns of windows line endings
+func checkBootstrap(name string, data []byte) error {
+	firstLine, _, _ := strings.Cut(string(data), "\n")
+
+	if !strings.HasPrefix(firstLine, "#!") {
+		return errors.New(fmt.Sprintf("bootstrap %s must start with an interpreter line such as #!/bin/sh", name))
+	}
+
+	if bytes.Contains(data, []byte("\r\n")) {
+		return errors.New(fmt.Sprintf("bootstrap %s has windows line endings, convert it to unix line endings", name))
+	}
+
+	return nil
+}
+
+// checkBuildInfo prints the go version and module of the executable from its embedded build info, which
+// executables not built by go do not have
+func checkBuildInfo(name string, data []byte) error {
+	info, err := buildinfo.Read(bytes.NewReader(data))
+
+	if err != nil {
+		log.Debugf("executable %s has no go build info: %v", name, err)
+
+		return nil
+	}
+
+	log.Infof("executable %s built with %s from module %s %s", name, info.GoVersion, info.Main.Path, info.Main.Version)
+
+	for _, setting := range info.Settings {
+		switch {
+		case setting.Key == "GOOS" && setting.Value != targetOs:
+			return errors.New(fmt.Sprintf("executable %s is built for %s, rebuild it with GOOS=%s", name, setting.Value, targetOs))
+		case setting.Key == "CGO_ENABLED" && setting.Value == "1":
+			log.Warnf("executable %s is built with cgo, rebuild it with CGO_ENABLED=0 unless it needs c libraries", name)
+		}
+	}
+
+	return nil
+}
+
+// executableFormat names the format of an executable that is not an elf file, for the error to explain how
+// it was built
+func executableFormat(data []byte) string {
+	if _, err := macho.NewFile(bytes.NewReader(data)); err == nil {
+		return "a macos mach-o file"
+	}
+
+	if _, err := pe.NewFile(bytes.NewReader(data)); err == nil {
+		return "a windows pe file"
+	}
+
+	return "an unknown file"
+}
+
+func formatLibraries(libraries []string) string {
+	if len(libraries) == 0 {
+		return "to system libraries"
+	}
+
+	return "to " + strings.Join(libraries, ", ")
+}
@@ -0,0 +1,155 @@
+package helpers
+
+import (
+	"bytes"
+	"debug/elf"
+	"encoding/binary"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestExecutable(t *testing.T) {
+	t.Run("checkExecutable with matching architecture returns nil", func(t *testing.T) {
+		// given
+		data := getElfHeader(t, elf.EM_AARCH64)
+
+		// when
+		err := checkExecutable("main", data, "arm64")
+
+		// then
+		assert.Nil(t, err)
+	})
+
+	t.Run("checkExecutable without architecture defaults to x86_64", func(t *testing.T) {
+		// given
+		data := getElfHeader(t, elf.EM_X86_64)
+
+		// when
+		err := checkExecutable("main", data, "")
+
+		// then
+		assert.Nil(t, err)
+	})
+
+	t.Run("checkExecutable with different architecture returns error", func(t *testing.T) {
+		// given
+		data := getElfHeader(t, elf.EM_X86_64)
+
+		// when
+		err := checkExecutable("main", data, "arm64")
+
+		// then
+		assert.NotNil(t, err)
+		assert.Contains(t, err.Error(), "GOARCH=arm64")
+	})
+
+	t.Run("checkExecutable with macos executable returns error", func(t *testing.T) {
+		// given
+		data := []byte{0xcf, 0xfa, 0xed, 0xfe, 0x07, 0x00, 0x00, 0x01, 0x03, 0x00, 0x00, 0x00, 0x02, 0x00, 0x00, 0x00, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0}
+
+		// when
+		err := checkExecutable("main", data, "")
+
+		// then
+		assert.EqualError(t, err, "executable main is a macos mach-o file, not a linux elf file, rebuild it with GOOS=linux GOARCH=amd64")
+	})
+
+	t.Run("checkExecutable with unknown file returns error", func(t *testing.T) {
+		// given
+		data := []byte("#!/bin/sh")
+
+		// when
+		err := checkExecutable("main", data, "arm64")
+
+		// then
+		assert.EqualError(t, err, "executable main is an unknown file, not a linux elf file, rebuild it with GOOS=linux GOARCH=arm64")
+	})
+
+	t.Run("checkExecutable with dynamically linked executable returns nil", func(t *testing.T) {
+		// given
+		data := getDynamicElf(t, elf.EM_X86_64, "/lib64/ld-linux-x86-64.so.2")
+
+		// when
+		err := checkExecutable("main", data, "x86_64")
+
+		// then
+		assert.Nil(t, err)
+	})
+
+	t.Run("checkExecutable with unsupported architecture returns error", func(t *testing.T) {
+		// given
+		data := getElfHeader(t, elf.EM_X86_64)
+
+		// when
+		err := checkExecutable("main", data, "mips")
+
+		// then
+		assert.EqualError(t, err, "unsupported architecture mips")
+	})
+}
+
+func TestBootstrap(t *testing.T) {
+	t.Run("checkBootstrap with shell script returns nil", func(t *testing.T) {
+		// when
+		err := checkBootstrap("bootstrap.sh", []byte("#!/bin/sh\nexport GODEBUG=madvdontneed=1\nexec ./main\n"))
+
+		// then
+		assert.Nil(t, err)
+	})
+
+	t.Run("checkBootstrap without interpreter line returns error", func(t *testing.T) {
+		// when
+		err := checkBootstrap("bootstrap.sh", []byte("exec ./main\n"))
+
+		// then
+		assert.EqualError(t, err, "bootstrap bootstrap.sh must start with an interpreter line such as #!/bin/sh")
+	})
+
+	t.Run("checkBootstrap with windows line endings returns error", func(t *testing.T) {
+		// when
+		err := checkBootstrap("bootstrap.sh", []byte("#!/bin/sh\r\nexec ./main\r\n"))
+
+		// then
+		assert.EqualError(t, err, "bootstrap bootstrap.sh has windows line endings, convert it to unix line endings")
+	})
+}
+
+// getDynamicElf returns an elf file with the program header of the interpreter loading dynamic libraries
+func getDynamicElf(t *testing.T, machine elf.Machine, interpreter string) []byte {
+	header := getElfHeader(t, machine)
+	binary.LittleEndian.PutUint64(header[32:], 64)
+	binary.LittleEndian.PutUint16(header[54:], 56)
+	binary.LittleEndian.PutUint16(header[56:], 1)
+
+	prog := elf.Prog64{Type: uint32(elf.PT_INTERP), Off: 120, Filesz: uint64(len(interpreter)), Memsz: uint64(len(interpreter))}
+
+	buffer := bytes.NewBuffer(header)
+	err := binary.Write(buffer, binary.LittleEndian, prog)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buffer.WriteString(interpreter)
+
+	return buffer.Bytes()
+}
+
+func getElfHeader(t *testing.T, machine elf.Machine) []byte {
+	header := elf.Header64{
+		Ident:   [elf.EI_NIDENT]byte{0x7f, 'E', 'L', 'F', byte(elf.ELFCLASS64), byte(elf.ELFDATA2LSB), byte(elf.EV_CURRENT)},
+		Type:    uint16(elf.ET_EXEC),
+		Machine: uint16(machine),
+		Version: uint32(elf.EV_CURRENT),
+		Ehsize:  64,
+	}
+
+	buffer := &bytes.Buffer{}
+	err := binary.Write(buffer, binary.LittleEndian, header)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return buffer.Bytes()
+}
@@ -2,14 +2,22 @@ package helpers
 
 import (
 	"archive/zip"
+	"bytes"
+	"compress/flate"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"github.com/getflight/flight/models"
 	"github.com/spf13/afero"
+	"io"
 	"os"
 	"path/filepath"
-	"strings"
+	"sort"
+	"sync"
+	"time"
 
 	"github.com/pkg/errors"
+	"github.com/samber/lo"
 	log "github.com/sirupsen/logrus"
 )
 
@@ -21,20 +29,52 @@ const (
 	organisationFilename = "organisation"
 	tokenFilename        = "token"
 	zipFilename          = "main.zip"
+	executableMode       = 0755
+	// secretFileMode is the mode of the private keys of the work path, only readable by the user
+	secretFileMode = 0600
+	// zipCompressionLevel is fixed so that the zip does not depend on the defaults of the go version
+	zipCompressionLevel = flate.BestCompression
+	// defaultBootstrap runs the executable, lambda running the bootstrap of the function with the provided runtime
+	defaultBootstrap = "#!/bin/sh\nexec \"${LAMBDA_TASK_ROOT:-.}/main\" \"$@\"\n"
 )
 
 var (
 	UserWorkPath = ""
+	// zipModified is the modification time of every zip entry, the earliest time zip files can store
+	zipModified = time.Date(1980, 1, 1, 0, 0, 0, 0, time.UTC)
 )
 
+// zipEntry is a file of the artifact zip
+type zipEntry struct {
+	name string
+	data []byte
+	mode os.FileMode
+}
+
 type FileHelperType interface {
+	CacheArtifact(artifact models.CachedArtifact, content string) error
+	CachedArtifact(reference string) (*models.CachedArtifact, error)
+	CachedArtifacts() ([]models.CachedArtifact, error)
+	GenerateSbom(content string) (string, error)
 	Package(manifest models.Manifest) (string, error)
+	PruneCachedArtifacts(olderThan time.Duration) ([]models.CachedArtifact, error)
+	ReadCachedArtifact(digest string) (string, error)
 	ReadFile(filename string) (string, error)
+	ScanSecrets(content string) ([]models.SecretFinding, error)
 	WriteFile(value string, filename string) error
+	WriteSecretFile(value string, filename string) error
 }
 
 type FileHelper struct {
 	FileSystem FileSystemType
+	// buildPath is the build directory of the current run, so that commands running in parallel do not
+	// overwrite each other's artifact
+	buildPath string
+	// buildMutex is held while the build directory is created or a file in it is used, as the interrupt handler
+	// removes the directory while the command is still running
+	buildMutex sync.Mutex
+	cleanup    sync.Once
+	cleaned    bool
 }
 
 // Package bundles an executable into a zip file in order to prepare for the lambda deployment
@@ -54,8 +94,23 @@ func (h *FileHelper) Package(manifest models.Manifest) (string, error) {
 		return "", errors.WithStack(err)
 	}
 
-	// Get the file handle
-	content, err := h.ReadFile(filepath.Join(buildWorkPath, zipFilename))
+	zipPath, unlock, err := h.lockBuildPath(zipFilename)
+
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+
+	data, err := h.FileSystem.ReadFile(zipPath)
+	unlock()
+
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+
+	content := string(data)
+
+	// Fail before the upload when the zip exceeds the limits of lambda
+	err = checkPackageSize(content)
 
 	if err != nil {
 		return "", errors.WithStack(err)
@@ -64,6 +119,26 @@ func (h *FileHelper) Package(manifest models.Manifest) (string, error) {
 	return content, nil
 }
 
+// ArtifactDigest returns the sha256 digest of an artifact, identical for zips packaged from the same files
+func ArtifactDigest(content string) string {
+	sum := sha256.Sum256([]byte(content))
+
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// ArtifactFiles returns the files of an artifact zip, in the order of the zip
+func ArtifactFiles(content string) ([]models.ArtifactFile, error) {
+	reader, err := zip.NewReader(bytes.NewReader([]byte(content)), int64(len(content)))
+
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return lo.Map[*zip.File, models.ArtifactFile](reader.File, func(file *zip.File, _ int) models.ArtifactFile {
+		return models.ArtifactFile{Name: file.Name, Mode: file.Mode(), Size: file.UncompressedSize64, CompressedSize: file.CompressedSize64}
+	}), nil
+}
+
 func (h *FileHelper) ReadFile(filename string) (string, error) {
 	path, err := h.getWorkPath(filename)
 
@@ -71,6 +146,14 @@ func (h *FileHelper) ReadFile(filename string) (string, error) {
 		return "", err
 	}
 
+	unlock, err := h.lockFile(path)
+
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+
+	defer unlock()
+
 	log.Debugf("reading from %v", path)
 
 	content, err := h.FileSystem.ReadFile(path)
@@ -79,6 +162,15 @@ func (h *FileHelper) ReadFile(filename string) (string, error) {
 }
 
 func (h *FileHelper) WriteFile(value string, filename string) error {
+	return h.writeFile(value, filename, 0644)
+}
+
+// WriteSecretFile writes a file of the work path only readable by the user, such as private keys
+func (h *FileHelper) WriteSecretFile(value string, filename string) error {
+	return h.writeFile(value, filename, secretFileMode)
+}
+
+func (h *FileHelper) writeFile(value string, filename string, mode os.FileMode) error {
 	err := h.prepareWrite()
 
 	if err != nil {
@@ -91,9 +183,50 @@ func (h *FileHelper) WriteFile(value string, filename string) error {
 		return err
 	}
 
-	log.Debugf("writing to %v %v", path, value)
+	unlock, err := h.lockFile(path)
+
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	defer unlock()
+
+	log.Debugf("writing to %v", path)
 
-	return h.FileSystem.WriteFile(path, []byte(value), 0644)
+	err = h.FileSystem.WriteFile(path, []byte(value), mode)
+
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	// The mode of an existing file is kept by WriteFile, such as keys written before their mode was restricted
+	return errors.WithStack(h.FileSystem.Chmod(path, mode))
+}
+
+// Cleanup removes the build directory of the current run, called when flight exits. Only the first call removes
+// it, flight exiting either when the command ends, fails or is interrupted, and no build directory is created
+// afterwards.
+func (h *FileHelper) Cleanup() {
+	h.cleanup.Do(func() {
+		h.buildMutex.Lock()
+		defer h.buildMutex.Unlock()
+
+		h.cleaned = true
+
+		if h.buildPath == "" {
+			return
+		}
+
+		log.Debugf("removing build directory %v", h.buildPath)
+
+		err := h.FileSystem.RemoveAll(h.buildPath)
+
+		if err != nil {
+			log.Errorf("failed to remove build directory %v: %v", h.buildPath, err)
+		}
+
+		h.buildPath = ""
+	})
 }
 
 func (h *FileHelper) getWorkPath(filename string) (string, error) {
@@ -124,6 +257,53 @@ func (h *FileHelper) prepareWrite() error {
 	return h.FileSystem.MkdirAll(baseWorkPath, os.ModeDir)
 }
 
+// lockBuildPath returns the path of a file in the build directory of the current run, created on first use. The
+// directory is not removed by Cleanup until the returned function is called once the file is no longer used.
+func (h *FileHelper) lockBuildPath(filename string) (string, func(), error) {
+	h.buildMutex.Lock()
+	path, err := h.getBuildPath(filename)
+
+	if err != nil {
+		h.buildMutex.Unlock()
+
+		return "", nil, errors.WithStack(err)
+	}
+
+	return path, h.buildMutex.Unlock, nil
+}
+
+func (h *FileHelper) getBuildPath(filename string) (string, error) {
+	if h.cleaned {
+		return "", errors.New("build directory was removed as flight is exiting")
+	}
+
+	if h.buildPath == "" {
+		err := h.prepareWrite()
+
+		if err != nil {
+			return "", errors.WithStack(err)
+		}
+
+		baseBuildPath, err := h.getWorkPath(buildWorkPath)
+
+		if err != nil {
+			return "", errors.WithStack(err)
+		}
+
+		buildPath, err := h.FileSystem.MkdirTemp(baseBuildPath, fmt.Sprintf("run-%d-", os.Getpid()))
+
+		if err != nil {
+			return "", errors.WithStack(err)
+		}
+
+		log.Debugf("build directory %v", buildPath)
+
+		h.buildPath = buildPath
+	}
+
+	return filepath.Join(h.buildPath, filename), nil
+}
+
 func (h *FileHelper) openFile(filename string) (afero.File, error) {
 	path, err := h.getWorkPath(filename)
 
@@ -144,7 +324,33 @@ func (h *FileHelper) openFile(filename string) (afero.File, error) {
 
 func (h *FileHelper) writeZip(zipFilename string, manifest models.Manifest) error {
 
-	zipPath, err := h.getWorkPath(filepath.Join(buildWorkPath, zipFilename))
+	zipPath, unlock, err := h.lockBuildPath(zipFilename)
+
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	defer unlock()
+
+	bootstrap, err := h.getZipBootstrap(manifest)
+
+	if err != nil {
+		return errors.WithStack(errors.Wrap(err, fmt.Sprintf("error while writing bootstrap")))
+	}
+
+	executable, err := h.getZipExecutable(manifest, executableFilename)
+
+	if err != nil {
+		return errors.WithStack(errors.Wrap(err, fmt.Sprintf("error while writing executable")))
+	}
+
+	includes, err := h.getZipIncludes(manifest)
+
+	if err != nil {
+		return errors.WithStack(errors.Wrap(err, fmt.Sprintf("error while writing includes")))
+	}
+
+	entries, err := sortZipEntries(append([]zipEntry{*bootstrap, *executable}, includes...))
 
 	if err != nil {
 		return errors.WithStack(err)
@@ -154,6 +360,10 @@ func (h *FileHelper) writeZip(zipFilename string, manifest models.Manifest) erro
 
 	zipFile, err := h.FileSystem.Create(zipPath)
 
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
 	defer func() {
 		closeErr := zipFile.Close()
 		if closeErr != nil {
@@ -162,116 +372,146 @@ func (h *FileHelper) writeZip(zipFilename string, manifest models.Manifest) erro
 	}()
 
 	zipWriter := zip.NewWriter(zipFile)
-	defer zipWriter.Close()
+	zipWriter.RegisterCompressor(zip.Deflate, func(out io.Writer) (io.WriteCloser, error) {
+		return flate.NewWriter(out, zipCompressionLevel)
+	})
 
-	err = h.writeZipBootstrap(zipWriter)
+	for _, entry := range entries {
+		err = writeZipEntry(zipWriter, entry)
 
-	if err != nil {
-		return errors.WithStack(errors.Wrap(err, fmt.Sprintf("error while writing bootstrap")))
+		if err != nil {
+			return errors.WithStack(err)
+		}
+	}
+
+	return errors.WithStack(zipWriter.Close())
+}
+
+// getZipBootstrap returns the bootstrap running the executable, or the bootstrap script of the manifest for
+// functions needing to prepare their environment first
+func (h *FileHelper) getZipBootstrap(manifest models.Manifest) (*zipEntry, error) {
+	if manifest.Package == nil || manifest.Package.Bootstrap == "" {
+		return &zipEntry{name: bootstrapFilename, data: []byte(defaultBootstrap), mode: executableMode}, nil
 	}
 
-	err = h.writeZipExecutable(zipWriter, manifest, executableFilename)
+	data, err := h.FileSystem.ReadFile(manifest.Package.Bootstrap)
 
 	if err != nil {
-		return errors.WithStack(errors.Wrap(err, fmt.Sprintf("error while writing executable")))
+		return nil, errors.WithStack(err)
 	}
 
-	err = h.writeZipIncludes(zipWriter, manifest)
+	err = checkBootstrap(manifest.Package.Bootstrap, data)
 
 	if err != nil {
-		return errors.WithStack(errors.Wrap(err, fmt.Sprintf("error while writing includes")))
+		return nil, errors.WithStack(err)
 	}
 
-	return nil
+	return &zipEntry{name: bootstrapFilename, data: data, mode: executableMode}, nil
 }
 
-func (h *FileHelper) writeZipExecutable(writer *zip.Writer, manifest models.Manifest, destination string) error {
+func (h *FileHelper) getZipExecutable(manifest models.Manifest, destination string) (*zipEntry, error) {
 
 	if manifest.Name == "" {
-		return errors.WithStack(errors.New("name in manifest cannot be empty"))
-	}
-
-	exeWriter, err := writer.CreateHeader(&zip.FileHeader{
-		CreatorVersion: 3 << 8,     // indicates Unix
-		ExternalAttrs:  0777 << 16, // -rwxrwxrwx file permissions
-		Name:           destination,
-		Method:         zip.Deflate,
-	})
-
-	if err != nil {
-		return errors.WithStack(err)
+		return nil, errors.WithStack(errors.New("name in manifest cannot be empty"))
 	}
 
 	data, err := h.FileSystem.ReadFile(manifest.Name)
 
 	if err != nil {
-		return errors.WithStack(err)
+		return nil, errors.WithStack(err)
 	}
 
-	_, err = exeWriter.Write(data)
+	err = checkExecutable(manifest.Name, data, manifest.Architecture)
 
 	if err != nil {
-		return errors.WithStack(err)
+		return nil, errors.WithStack(err)
 	}
 
-	return nil
+	return &zipEntry{name: destination, data: data, mode: executableMode}, nil
 }
 
-func (h *FileHelper) writeZipIncludes(writer *zip.Writer, manifest models.Manifest) error {
-	if manifest.Files == nil {
+// getIgnoredPatterns returns the patterns of the .flightignore file of the project, which is optional
+func (h *FileHelper) getIgnoredPatterns() []string {
+	content, err := h.FileSystem.ReadFile(ignoreFilename)
+
+	if err != nil {
+		log.Debugf("no %s file: %v", ignoreFilename, err)
+
 		return nil
 	}
 
-	for _, include := range *manifest.Files {
-		err := filepath.Walk(include,
-			func(path string, info os.FileInfo, err error) error {
-				if err != nil {
-					return errors.WithStack(err)
-				}
+	return parseIgnore(string(content))
+}
 
-				if !info.IsDir() {
+func (h *FileHelper) getZipIncludes(manifest models.Manifest) ([]zipEntry, error) {
+	var entries []zipEntry
+
+	if manifest.Package == nil || manifest.Package.Includes == nil {
+		return entries, nil
+	}
 
-					data, err := h.FileSystem.ReadFile(path)
+	ignored := h.getIgnoredPatterns()
+	symlinks := manifest.Package.Symlinks
 
-					if err != nil {
-						return errors.WithStack(err)
-					}
+	if symlinks == "" {
+		symlinks = SymlinksFollow
+	}
 
-					// rewrite path for unix specific path separators
-					path = strings.ReplaceAll(path, "\\", "/")
-					log.Debug(path)
+	for _, include := range *manifest.Package.Includes {
+		parsed, err := ParseInclude(include)
 
-					err = h.writeZipFile(writer, path, data)
+		if err != nil {
+			return nil, errors.Wrap(err, fmt.Sprintf("invalid include %s", include))
+		}
 
-					if err != nil {
-						return errors.WithStack(err)
-					}
-				}
+		err = h.walkInclude(parsed.Source, parsed.Destination, symlinks, ignored, nil, &entries)
 
-				return nil
-			})
 		if err != nil {
-			return errors.WithStack(err)
+			return nil, errors.WithStack(err)
 		}
 	}
 
-	return nil
+	return entries, nil
 }
 
-func (h *FileHelper) writeZipBootstrap(writer *zip.Writer) error {
-	return h.writeZipFile(writer, bootstrapFilename, []byte(executableFilename))
+// sortZipEntries orders the entries by name so that the same files always produce the same zip. Files
+// included twice are only kept once, the last include winning so that environments can replace the files of
+// the project, and includes cannot replace the bootstrap or the executable.
+func sortZipEntries(entries []zipEntry) ([]zipEntry, error) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].name < entries[j].name
+	})
+
+	var sorted []zipEntry
+
+	for i, entry := range entries {
+		if i > 0 && entries[i-1].name == entry.name {
+			if entry.name == bootstrapFilename || entry.name == executableFilename {
+				return nil, errors.New(fmt.Sprintf("included file %s conflicts with the %s of the function", entry.name, entry.name))
+			}
+
+			log.Debugf("%s is included more than once, keeping the last include", entry.name)
+			sorted[len(sorted)-1] = entry
+
+			continue
+		}
+
+		sorted = append(sorted, entry)
+	}
+
+	return sorted, nil
 }
 
-func (h *FileHelper) writeZipFile(writer *zip.Writer, name string, content []byte) error {
-	header := &zip.FileHeader{Name: name, Method: zip.Deflate}
-	header.SetMode(0755)
+func writeZipEntry(writer *zip.Writer, entry zipEntry) error {
+	header := &zip.FileHeader{Name: entry.name, Method: zip.Deflate, Modified: zipModified}
+	header.SetMode(entry.mode)
 	link, err := writer.CreateHeader(header)
 
 	if err != nil {
 		return errors.WithStack(err)
 	}
 
-	if _, err = link.Write(content); err != nil {
+	if _, err = link.Write(entry.data); err != nil {
 		return errors.WithStack(err)
 	}
 
@@ -1,10 +1,14 @@
 package helpers
 
 import (
+	"archive/zip"
+	"bytes"
+	"debug/elf"
 	"github.com/getflight/flight/mocks"
 	"github.com/getflight/flight/models"
 	"github.com/spf13/afero"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"io/fs"
 	"os"
 	"path/filepath"
@@ -72,7 +76,10 @@ func TestFileHelper(t *testing.T) {
 		fileSystemMock := &mocks.FileSystemMock{}
 		fileSystemMock.On("UserHomeDir").Return("home", nil)
 		fileSystemMock.On("MkdirAll", filepath.Join("home", ".flight", "build"), os.ModeDir).Return(nil)
+		fileSystemMock.On("OpenFile", filepath.Join("home", ".flight", "token.lock"), os.O_CREATE|os.O_EXCL|os.O_WRONLY, fs.FileMode(0644)).Return(getLockFile(t), nil)
 		fileSystemMock.On("WriteFile", filepath.Join("home", ".flight", "token"), []byte(value), fs.FileMode(0644)).Return(nil)
+		fileSystemMock.On("Chmod", filepath.Join("home", ".flight", "token"), fs.FileMode(0644)).Return(nil)
+		fileSystemMock.On("Remove", filepath.Join("home", ".flight", "token.lock")).Return(nil)
 
 		fileHelper := FileHelper{FileSystem: fileSystemMock}
 
@@ -89,7 +96,9 @@ func TestFileHelper(t *testing.T) {
 		value := "test"
 		fileSystemMock := &mocks.FileSystemMock{}
 		fileSystemMock.On("UserHomeDir").Return("home", nil)
+		fileSystemMock.On("OpenFile", filepath.Join("home", ".flight", "token.lock"), os.O_CREATE|os.O_EXCL|os.O_WRONLY, fs.FileMode(0644)).Return(getLockFile(t), nil)
 		fileSystemMock.On("ReadFile", filepath.Join("home", ".flight", "token")).Return([]byte(value), nil)
+		fileSystemMock.On("Remove", filepath.Join("home", ".flight", "token.lock")).Return(nil)
 
 		fileHelper := FileHelper{FileSystem: fileSystemMock}
 
@@ -128,14 +137,16 @@ func TestFileHelper(t *testing.T) {
 
 		fileSystemMock := &mocks.FileSystemMock{}
 		fileSystemMock.On("UserHomeDir").Return("home", nil)
-		fileSystemMock.On("Create", filepath.Join("home", ".flight", "build", "main")).Return(f, nil)
-		fileSystemMock.On("ReadFile", "test-name").Return([]byte{}, nil)
+		fileSystemMock.On("MkdirAll", filepath.Join("home", ".flight", "build"), os.ModeDir).Return(nil)
+		fileSystemMock.On("MkdirTemp", filepath.Join("home", ".flight", "build"), mock.Anything).Return(filepath.Join("home", ".flight", "build", "run-1"), nil)
+		fileSystemMock.On("Create", filepath.Join("home", ".flight", "build", "run-1", "main")).Return(f, nil)
+		fileSystemMock.On("ReadFile", "test-name").Return(getElfHeader(t, elf.EM_X86_64), nil)
 
 		fileHelper := FileHelper{FileSystem: fileSystemMock}
 
 		manifest := models.Manifest{
 			Name:         "test-name",
-			Files:        nil,
+			Package:      nil,
 			Trigger:      "",
 			Environments: nil,
 		}
@@ -147,4 +158,125 @@ func TestFileHelper(t *testing.T) {
 		assert.Nil(t, err)
 		fileSystemMock.AssertExpectations(t)
 	})
+
+	t.Run("writeZip writes identical zips with sorted entries and fixed times", func(t *testing.T) {
+		// given
+		memFs := new(afero.MemMapFs)
+		first, _ := afero.TempFile(memFs, "", "first")
+		second, _ := afero.TempFile(memFs, "", "second")
+		zipPath := filepath.Join("home", ".flight", "build", "run-1", "main.zip")
+
+		fileSystemMock := &mocks.FileSystemMock{}
+		fileSystemMock.On("UserHomeDir").Return("home", nil)
+		fileSystemMock.On("MkdirAll", filepath.Join("home", ".flight", "build"), os.ModeDir).Return(nil)
+		fileSystemMock.On("MkdirTemp", filepath.Join("home", ".flight", "build"), mock.Anything).Return(filepath.Join("home", ".flight", "build", "run-1"), nil).Once()
+		fileSystemMock.On("Create", zipPath).Return(first, nil).Once()
+		fileSystemMock.On("Create", zipPath).Return(second, nil).Once()
+		fileSystemMock.On("ReadFile", "test-name").Return(getElfHeader(t, elf.EM_X86_64), nil)
+		fileSystemMock.On("ReadFile", ".flightignore").Return([]byte{}, os.ErrNotExist)
+
+		fileHelper := FileHelper{FileSystem: fileSystemMock}
+		manifest := models.Manifest{Name: "test-name"}
+
+		// when
+		firstErr := fileHelper.writeZip("main.zip", manifest)
+		secondErr := fileHelper.writeZip("main.zip", manifest)
+
+		// then
+		assert.Nil(t, firstErr)
+		assert.Nil(t, secondErr)
+
+		firstContent, _ := afero.ReadFile(memFs, first.Name())
+		secondContent, _ := afero.ReadFile(memFs, second.Name())
+		assert.Equal(t, firstContent, secondContent)
+
+		reader, err := zip.NewReader(bytes.NewReader(firstContent), int64(len(firstContent)))
+		assert.Nil(t, err)
+		assert.Equal(t, 2, len(reader.File))
+		assert.Equal(t, "bootstrap", reader.File[0].Name)
+		assert.Equal(t, "main", reader.File[1].Name)
+		assert.Equal(t, fs.FileMode(0755), reader.File[1].Mode())
+		assert.Equal(t, zipModified, reader.File[1].Modified.UTC())
+	})
+
+	t.Run("ArtifactFiles lists files of zip with sizes", func(t *testing.T) {
+		// given
+		content := getScanZip(t, map[string]string{"main": "executable", "static/index.html": "index"})
+
+		// when
+		files, err := ArtifactFiles(content)
+
+		// then
+		assert.Nil(t, err)
+		assert.Equal(t, 2, len(files))
+		assert.Equal(t, "main", files[0].Name)
+		assert.Equal(t, uint64(len("executable")), files[0].Size)
+		assert.Equal(t, "static/index.html", files[1].Name)
+	})
+
+	t.Run("getZipBootstrap without bootstrap returns script running executable", func(t *testing.T) {
+		// given
+		fileHelper := FileHelper{FileSystem: &mocks.FileSystemMock{}}
+
+		// when
+		bootstrap, err := fileHelper.getZipBootstrap(models.Manifest{Name: "test-name"})
+
+		// then
+		assert.Nil(t, err)
+		assert.Equal(t, zipEntry{name: "bootstrap", data: []byte("#!/bin/sh\nexec \"${LAMBDA_TASK_ROOT:-.}/main\" \"$@\"\n"), mode: 0755}, *bootstrap)
+	})
+
+	t.Run("getZipBootstrap with bootstrap returns script of manifest", func(t *testing.T) {
+		// given
+		script := []byte("#!/bin/sh\nexport AWS_LAMBDA_EXEC_WRAPPER=/opt/wrapper\nexec ./main\n")
+
+		fileSystemMock := &mocks.FileSystemMock{}
+		fileSystemMock.On("ReadFile", "scripts/bootstrap.sh").Return(script, nil)
+
+		fileHelper := FileHelper{FileSystem: fileSystemMock}
+
+		// when
+		bootstrap, err := fileHelper.getZipBootstrap(models.Manifest{Name: "test-name", Package: &models.ManifestPackage{Bootstrap: "scripts/bootstrap.sh"}})
+
+		// then
+		assert.Nil(t, err)
+		assert.Equal(t, zipEntry{name: "bootstrap", data: script, mode: 0755}, *bootstrap)
+	})
+
+	t.Run("getZipBootstrap with invalid bootstrap returns error", func(t *testing.T) {
+		// given
+		fileSystemMock := &mocks.FileSystemMock{}
+		fileSystemMock.On("ReadFile", "bootstrap.sh").Return([]byte("exec ./main"), nil)
+
+		fileHelper := FileHelper{FileSystem: fileSystemMock}
+
+		// when
+		_, err := fileHelper.getZipBootstrap(models.Manifest{Name: "test-name", Package: &models.ManifestPackage{Bootstrap: "bootstrap.sh"}})
+
+		// then
+		assert.ErrorContains(t, err, "must start with an interpreter line")
+	})
+
+	t.Run("sortZipEntries sorts entries and keeps last of files included twice", func(t *testing.T) {
+		// given
+		entries := []zipEntry{{name: "static/b.txt", data: []byte("project")}, {name: "bootstrap"}, {name: "static/a.txt"}, {name: "static/b.txt", data: []byte("environment")}}
+
+		// when
+		sorted, err := sortZipEntries(entries)
+
+		// then
+		assert.Nil(t, err)
+		assert.Equal(t, []zipEntry{{name: "bootstrap"}, {name: "static/a.txt"}, {name: "static/b.txt", data: []byte("environment")}}, sorted)
+	})
+
+	t.Run("sortZipEntries returns error when include replaces executable", func(t *testing.T) {
+		// given
+		entries := []zipEntry{{name: "main"}, {name: "main"}}
+
+		// when
+		_, err := sortZipEntries(entries)
+
+		// then
+		assert.EqualError(t, err, "included file main conflicts with the main of the function")
+	})
 }
@@ -0,0 +1,86 @@
+package helpers
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	lockSuffix        = ".lock"
+	lockRetryInterval = 50 * time.Millisecond
+	// lockStaleAge is the age after which a lock is considered left behind by a command that was killed, flight
+	// only holding locks while reading or writing a file
+	lockStaleAge = time.Minute
+)
+
+var (
+	// lockTimeout is how long a command waits for another command to release a lock
+	lockTimeout = 10 * time.Second
+)
+
+// lockFile locks a file of the work path shared by the flight commands running in parallel, such as the token or
+// the organisation, by creating a lock file next to it. The returned function releases the lock.
+func (h *FileHelper) lockFile(path string) (func(), error) {
+	lockPath := path + lockSuffix
+	deadline := time.Now().Add(lockTimeout)
+
+	for {
+		lock, err := h.FileSystem.OpenFile(lockPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+
+		if err == nil {
+			_, err = lock.WriteString(strconv.Itoa(os.Getpid()))
+			closeErr := lock.Close()
+
+			if err == nil {
+				err = closeErr
+			}
+
+			if err != nil {
+				h.unlockFile(lockPath)
+
+				return nil, errors.WithStack(err)
+			}
+
+			log.Debugf("locked %v", path)
+
+			return func() {
+				h.unlockFile(lockPath)
+			}, nil
+		}
+
+		// Nothing to protect when the work path does not exist yet, reading the file fails the same way
+		if os.IsNotExist(err) {
+			return func() {}, nil
+		}
+
+		if !os.IsExist(err) {
+			return nil, errors.WithStack(err)
+		}
+
+		if info, statErr := h.FileSystem.Stat(lockPath); statErr == nil && time.Since(info.ModTime()) > lockStaleAge {
+			log.Warnf("removing stale lock %v", lockPath)
+			h.unlockFile(lockPath)
+
+			continue
+		}
+
+		if time.Now().After(deadline) {
+			return nil, errors.New(fmt.Sprintf("%s is locked by another flight command, remove %s if no other command is running", path, lockPath))
+		}
+
+		time.Sleep(lockRetryInterval)
+	}
+}
+
+func (h *FileHelper) unlockFile(lockPath string) {
+	err := h.FileSystem.Remove(lockPath)
+
+	if err != nil && !os.IsNotExist(err) {
+		log.Errorf("failed to remove lock %v: %v", lockPath, err)
+	}
+}
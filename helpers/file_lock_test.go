@@ -0,0 +1,171 @@
+package helpers
+
+import (
+	"fmt"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFileLock(t *testing.T) {
+	t.Run("lockFile creates lock file removed on unlock", func(t *testing.T) {
+		// given
+		path := filepath.Join(t.TempDir(), "token")
+		fileHelper := FileHelper{FileSystem: &FileSystem{}}
+
+		// when
+		unlock, err := fileHelper.lockFile(path)
+
+		// then
+		assert.Nil(t, err)
+		content, readErr := os.ReadFile(path + ".lock")
+		assert.Nil(t, readErr)
+		assert.Equal(t, []byte(fmt.Sprint(os.Getpid())), content)
+
+		unlock()
+		_, statErr := os.Stat(path + ".lock")
+		assert.True(t, os.IsNotExist(statErr))
+	})
+
+	t.Run("lockFile waits for lock to be released", func(t *testing.T) {
+		// given
+		path := filepath.Join(t.TempDir(), "token")
+		fileHelper := FileHelper{FileSystem: &FileSystem{}}
+		unlock, _ := fileHelper.lockFile(path)
+
+		go func() {
+			time.Sleep(2 * lockRetryInterval)
+			unlock()
+		}()
+
+		// when
+		secondUnlock, err := fileHelper.lockFile(path)
+
+		// then
+		assert.Nil(t, err)
+		secondUnlock()
+	})
+
+	t.Run("lockFile with lock held returns error after timeout", func(t *testing.T) {
+		// given
+		lockTimeout = 2 * lockRetryInterval
+		path := filepath.Join(t.TempDir(), "token")
+		fileHelper := FileHelper{FileSystem: &FileSystem{}}
+		unlock, _ := fileHelper.lockFile(path)
+		defer unlock()
+
+		// when
+		_, err := fileHelper.lockFile(path)
+
+		// then
+		assert.EqualError(t, err, path+" is locked by another flight command, remove "+path+".lock if no other command is running")
+
+		// revert
+		lockTimeout = 10 * time.Second
+	})
+
+	t.Run("lockFile removes stale lock", func(t *testing.T) {
+		// given
+		path := filepath.Join(t.TempDir(), "token")
+		assert.Nil(t, os.WriteFile(path+".lock", []byte("1"), 0644))
+		stale := time.Now().Add(-2 * lockStaleAge)
+		assert.Nil(t, os.Chtimes(path+".lock", stale, stale))
+
+		fileHelper := FileHelper{FileSystem: &FileSystem{}}
+
+		// when
+		unlock, err := fileHelper.lockFile(path)
+
+		// then
+		assert.Nil(t, err)
+		unlock()
+	})
+
+	t.Run("lockFile without work path does not lock", func(t *testing.T) {
+		// given
+		path := filepath.Join(t.TempDir(), ".flight", "token")
+		fileHelper := FileHelper{FileSystem: &FileSystem{}}
+
+		// when
+		unlock, err := fileHelper.lockFile(path)
+
+		// then
+		assert.Nil(t, err)
+		unlock()
+		_, statErr := os.Stat(filepath.Dir(path))
+		assert.True(t, os.IsNotExist(statErr))
+	})
+
+	t.Run("lockBuildPath returns separate build directory per run removed on cleanup", func(t *testing.T) {
+		// given
+		UserWorkPath = t.TempDir()
+		first := FileHelper{FileSystem: &FileSystem{}}
+		second := FileHelper{FileSystem: &FileSystem{}}
+
+		// when
+		firstPath, firstUnlock, firstErr := first.lockBuildPath(zipFilename)
+		firstUnlock()
+		secondPath, secondUnlock, secondErr := second.lockBuildPath(zipFilename)
+		secondUnlock()
+
+		// then
+		assert.Nil(t, firstErr)
+		assert.Nil(t, secondErr)
+		assert.NotEqual(t, firstPath, secondPath)
+		assert.Equal(t, filepath.Join(UserWorkPath, ".flight", "build"), filepath.Dir(filepath.Dir(firstPath)))
+
+		samePath, sameUnlock, _ := first.lockBuildPath(zipFilename)
+		sameUnlock()
+		assert.Equal(t, firstPath, samePath)
+
+		first.Cleanup()
+		first.Cleanup()
+		_, statErr := os.Stat(filepath.Dir(firstPath))
+		assert.True(t, os.IsNotExist(statErr))
+		_, statErr = os.Stat(filepath.Dir(secondPath))
+		assert.Nil(t, statErr)
+
+		_, _, err := first.lockBuildPath(zipFilename)
+		assert.EqualError(t, err, "build directory was removed as flight is exiting")
+
+		// revert
+		UserWorkPath = ""
+	})
+
+	t.Run("Cleanup waits for build path to be unlocked", func(t *testing.T) {
+		// given
+		UserWorkPath = t.TempDir()
+		fileHelper := FileHelper{FileSystem: &FileSystem{}}
+		path, unlock, _ := fileHelper.lockBuildPath(zipFilename)
+		cleaned := make(chan struct{})
+
+		// when
+		go func() {
+			fileHelper.Cleanup()
+			close(cleaned)
+		}()
+
+		time.Sleep(2 * lockRetryInterval)
+		writeErr := os.WriteFile(path, []byte("zip"), 0644)
+		unlock()
+		<-cleaned
+
+		// then
+		assert.Nil(t, writeErr)
+		_, statErr := os.Stat(filepath.Dir(path))
+		assert.True(t, os.IsNotExist(statErr))
+
+		// revert
+		UserWorkPath = ""
+	})
+}
+
+func getLockFile(t *testing.T) afero.File {
+	file, err := afero.TempFile(new(afero.MemMapFs), "", "lock")
+	assert.Nil(t, err)
+
+	return file
+}
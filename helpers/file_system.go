@@ -10,11 +10,19 @@ import (
 )
 
 type FileSystemType interface {
+	Chmod(name string, mode fs.FileMode) error
 	Create(name string) (afero.File, error)
 	MkdirAll(path string, perm fs.FileMode) error
+	MkdirTemp(dir string, pattern string) (string, error)
 	NewWriter(w io.Writer) *zip.Writer
 	Open(name string) (afero.File, error)
+	OpenFile(name string, flag int, perm fs.FileMode) (afero.File, error)
+	ReadDir(name string) ([]fs.DirEntry, error)
 	ReadFile(filename string) ([]byte, error)
+	Remove(name string) error
+	RemoveAll(path string) error
+	Rename(oldpath string, newpath string) error
+	Stat(name string) (fs.FileInfo, error)
 	UserHomeDir() (string, error)
 	WriteFile(filename string, data []byte, perm fs.FileMode) error
 }
@@ -30,10 +38,42 @@ func (s *FileSystem) MkdirAll(path string, perm fs.FileMode) error {
 	return os.MkdirAll(path, perm)
 }
 
+func (s *FileSystem) Chmod(name string, mode fs.FileMode) error {
+	return os.Chmod(name, mode)
+}
+
+func (s *FileSystem) MkdirTemp(dir string, pattern string) (string, error) {
+	return os.MkdirTemp(dir, pattern)
+}
+
 func (s *FileSystem) Open(name string) (afero.File, error) {
 	return os.Open(name)
 }
 
+func (s *FileSystem) OpenFile(name string, flag int, perm fs.FileMode) (afero.File, error) {
+	return os.OpenFile(name, flag, perm)
+}
+
+func (s *FileSystem) ReadDir(name string) ([]fs.DirEntry, error) {
+	return os.ReadDir(name)
+}
+
+func (s *FileSystem) Remove(name string) error {
+	return os.Remove(name)
+}
+
+func (s *FileSystem) RemoveAll(path string) error {
+	return os.RemoveAll(path)
+}
+
+func (s *FileSystem) Rename(oldpath string, newpath string) error {
+	return os.Rename(oldpath, newpath)
+}
+
+func (s *FileSystem) Stat(name string) (fs.FileInfo, error) {
+	return os.Stat(name)
+}
+
 func (s *FileSystem) Create(name string) (afero.File, error) {
 	return os.Create(name)
 }
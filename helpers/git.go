@@ -0,0 +1,24 @@
+package helpers
+
+import (
+	"os/exec"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// GitCommit returns the hash and the subject of the commit checked out in the current directory, which are empty
+// outside of a git repository
+func GitCommit() (string, string) {
+	output, err := exec.Command("git", "log", "-1", "--format=%H%n%s").Output()
+
+	if err != nil {
+		log.Debugf("no git commit found: %v", err)
+
+		return "", ""
+	}
+
+	hash, message, _ := strings.Cut(strings.TrimSpace(string(output)), "\n")
+
+	return hash, message
+}
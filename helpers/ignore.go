@@ -0,0 +1,56 @@
+package helpers
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+const (
+	ignoreFilename = ".flightignore"
+)
+
+// parseIgnore returns the patterns of a .flightignore file, one pattern per line, skipping blank lines and
+// comments starting with #
+func parseIgnore(content string) []string {
+	var patterns []string
+
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		patterns = append(patterns, line)
+	}
+
+	return patterns
+}
+
+// isIgnored returns whether a path matches one of the patterns. Patterns ending with / only match directories,
+// patterns without / match the name of the file or directory at any depth, other patterns match the whole path.
+func isIgnored(patterns []string, path string, isDir bool) bool {
+	path = filepath.ToSlash(filepath.Clean(path))
+
+	for _, pattern := range patterns {
+		if strings.HasSuffix(pattern, "/") {
+			if !isDir {
+				continue
+			}
+
+			pattern = strings.TrimSuffix(pattern, "/")
+		}
+
+		name := path
+
+		if !strings.Contains(pattern, "/") {
+			name = filepath.Base(path)
+		}
+
+		if matched, _ := filepath.Match(strings.TrimPrefix(pattern, "/"), name); matched {
+			return true
+		}
+	}
+
+	return false
+}
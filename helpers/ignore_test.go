@@ -0,0 +1,31 @@
+package helpers
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestIgnore(t *testing.T) {
+	t.Run("parseIgnore returns patterns without comments and blank lines", func(t *testing.T) {
+		// given
+		content := "# comment\n\n.git/\n  *.md  \n"
+
+		// when
+		patterns := parseIgnore(content)
+
+		// then
+		assert.Equal(t, []string{".git/", "*.md"}, patterns)
+	})
+
+	t.Run("isIgnored matches names at any depth and paths from the root", func(t *testing.T) {
+		// given
+		patterns := []string{".git/", "*_test.go", "static/drafts/*"}
+
+		// when / then
+		assert.True(t, isIgnored(patterns, "static/.git", true))
+		assert.False(t, isIgnored(patterns, "static/.git", false))
+		assert.True(t, isIgnored(patterns, "templates/page_test.go", false))
+		assert.True(t, isIgnored(patterns, "static/drafts/post.html", false))
+		assert.False(t, isIgnored(patterns, "static/post.html", false))
+	})
+}
@@ -0,0 +1,176 @@
+package helpers
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// SymlinksFollow zips the files symlinks point to, which is the default
+	SymlinksFollow = "follow"
+	// SymlinksPreserve zips symlinks as links, their target being resolved in the function directory
+	SymlinksPreserve = "preserve"
+	symlinkMode      = os.ModeSymlink | 0777
+)
+
+// Include is a file or directory of the project zipped with the executable, written at its destination in
+// the zip
+type Include struct {
+	Source      string
+	Destination string
+}
+
+// ParseInclude parses an include of the manifest, either a path zipped at the same path or a source:destination
+// mapping zipping the source at the destination
+func ParseInclude(include string) (Include, error) {
+	source, destination, mapped := strings.Cut(include, ":")
+
+	if source == "" {
+		return Include{}, errors.New("source cannot be empty")
+	}
+
+	if !mapped {
+		if filepath.IsAbs(source) {
+			return Include{}, errors.New("absolute sources need a destination, such as /path/to/file:file")
+		}
+
+		destination = source
+	}
+
+	destination = path.Clean(filepath.ToSlash(destination))
+
+	if mapped && (destination == "." || strings.TrimSpace(destination) == "") {
+		return Include{}, errors.New("destination cannot be empty")
+	}
+
+	if path.IsAbs(destination) || destination == ".." || strings.HasPrefix(destination, "../") {
+		return Include{}, errors.New(fmt.Sprintf("destination %s must be inside the function directory", destination))
+	}
+
+	return Include{Source: source, Destination: destination}, nil
+}
+
+// walkInclude adds the files of an include to the entries, renaming the source to the destination. Symlinks to
+// directories are walked when followed, the directories of the symlinks followed so far preventing loops.
+func (h *FileHelper) walkInclude(source string, destination string, symlinks string, ignored []string, followed []string, entries *[]zipEntry) error {
+	return filepath.Walk(source,
+		func(filePath string, info os.FileInfo, err error) error {
+			if err != nil {
+				return errors.WithStack(err)
+			}
+
+			if isIgnored(ignored, filePath, info.IsDir()) {
+				log.Debugf("ignoring %s", filePath)
+
+				if info.IsDir() {
+					return filepath.SkipDir
+				}
+
+				return nil
+			}
+
+			relative, err := filepath.Rel(source, filePath)
+
+			if err != nil {
+				return errors.WithStack(err)
+			}
+
+			// rewrite path for unix specific path separators
+			name := path.Join(destination, filepath.ToSlash(relative))
+
+			switch {
+			case info.IsDir():
+				return nil
+			case info.Mode()&os.ModeSymlink != 0 && symlinks == SymlinksPreserve:
+				target, err := os.Readlink(filePath)
+
+				if err != nil {
+					return errors.WithStack(err)
+				}
+
+				log.Debugf("%s -> %s", name, target)
+				*entries = append(*entries, zipEntry{name: name, data: []byte(filepath.ToSlash(target)), mode: symlinkMode})
+			case info.Mode()&os.ModeSymlink != 0:
+				target, err := os.Stat(filePath)
+
+				if err != nil {
+					return errors.Wrap(err, fmt.Sprintf("failed to follow symlink %s", filePath))
+				}
+
+				if target.IsDir() {
+					followed, err = followDirectory(filePath, followed)
+
+					if err != nil {
+						return errors.WithStack(err)
+					}
+
+					return h.walkInclude(filePath+string(filepath.Separator), name, symlinks, ignored, followed, entries)
+				}
+
+				return h.addIncludedFile(filePath, name, target.Mode(), entries)
+			default:
+				return h.addIncludedFile(filePath, name, info.Mode(), entries)
+			}
+
+			return nil
+		})
+}
+
+// followDirectory returns the directories of the symlinks followed once the symlink is, or an error when the
+// symlink points to one of them or to a parent, which would walk the same files forever
+func followDirectory(link string, followed []string) ([]string, error) {
+	target, err := filepath.EvalSymlinks(link)
+
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	location, err := filepath.EvalSymlinks(filepath.Dir(link))
+
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	followed = append(append([]string{}, followed...), location)
+
+	for _, directory := range followed {
+		if relative, err := filepath.Rel(target, directory); err == nil && relative != ".." && !strings.HasPrefix(relative, ".."+string(filepath.Separator)) {
+			return nil, errors.New(fmt.Sprintf("symlink %s loops to %s, use symlinks: %s to keep the link", link, target, SymlinksPreserve))
+		}
+	}
+
+	return followed, nil
+}
+
+func (h *FileHelper) addIncludedFile(filePath string, name string, mode os.FileMode, entries *[]zipEntry) error {
+	data, err := h.FileSystem.ReadFile(filePath)
+
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	log.Debug(name)
+	*entries = append(*entries, zipEntry{name: name, data: data, mode: includeMode(name, mode)})
+
+	return nil
+}
+
+// includeMode returns the mode of an included file in the zip, group and other users getting the read and
+// execute permissions of the owner, so that the zip does not depend on the umask of the machine and lambda, which
+// runs the function as a user other than the owner, can read the files the owner can
+func includeMode(name string, mode os.FileMode) os.FileMode {
+	owner := mode.Perm() & 0500
+	normalized := owner | 0200 | owner>>3 | owner>>6
+
+	if normalized&0004 == 0 {
+		log.Warnf("%s is not readable by its owner (mode %s), the function will not be able to read it", name, mode.Perm())
+	}
+
+	return normalized
+}
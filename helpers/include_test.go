@@ -0,0 +1,158 @@
+package helpers
+
+import (
+	"github.com/stretchr/testify/assert"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestInclude(t *testing.T) {
+	t.Run("ParseInclude without destination keeps path", func(t *testing.T) {
+		// when
+		include, err := ParseInclude("static/")
+
+		// then
+		assert.Nil(t, err)
+		assert.Equal(t, Include{Source: "static/", Destination: "static"}, include)
+	})
+
+	t.Run("ParseInclude with destination maps source", func(t *testing.T) {
+		// when
+		include, err := ParseInclude("configs/prod.yml:config.yml")
+
+		// then
+		assert.Nil(t, err)
+		assert.Equal(t, Include{Source: "configs/prod.yml", Destination: "config.yml"}, include)
+	})
+
+	t.Run("ParseInclude with invalid include returns error", func(t *testing.T) {
+		for include, message := range map[string]string{
+			":config.yml":          "source cannot be empty",
+			"configs/prod.yml:":    "destination cannot be empty",
+			"/etc/config.yml":      "absolute sources need a destination, such as /path/to/file:file",
+			"config.yml:/var/task": "destination /var/task must be inside the function directory",
+			"config.yml:../x.yml":  "destination ../x.yml must be inside the function directory",
+		} {
+			_, err := ParseInclude(include)
+			assert.EqualError(t, err, message, include)
+		}
+	})
+
+	t.Run("walkInclude maps source to destination and keeps executable bit", func(t *testing.T) {
+		// given
+		directory := getIncludeDirectory(t)
+		fileHelper := FileHelper{FileSystem: &FileSystem{}}
+		var entries []zipEntry
+
+		// when
+		err := fileHelper.walkInclude(filepath.Join(directory, "static"), "public", SymlinksFollow, nil, nil, &entries)
+
+		// then
+		assert.Nil(t, err)
+		assert.Equal(t, []zipEntry{
+			{name: "public/index.html", data: []byte("index"), mode: 0644},
+			{name: "public/link.html", data: []byte("index"), mode: 0644},
+			{name: "public/run.sh", data: []byte("run"), mode: 0755},
+		}, entries)
+	})
+
+	t.Run("walkInclude preserves symlinks when requested", func(t *testing.T) {
+		// given
+		directory := getIncludeDirectory(t)
+		fileHelper := FileHelper{FileSystem: &FileSystem{}}
+		var entries []zipEntry
+
+		// when
+		err := fileHelper.walkInclude(filepath.Join(directory, "static"), "static", SymlinksPreserve, nil, nil, &entries)
+
+		// then
+		assert.Nil(t, err)
+		assert.Equal(t, zipEntry{name: "static/link.html", data: []byte("index.html"), mode: symlinkMode}, entries[1])
+	})
+
+	t.Run("walkInclude follows symlinks to directories", func(t *testing.T) {
+		// given
+		directory := getIncludeDirectory(t)
+		assert.Nil(t, os.Symlink(filepath.Join(directory, "static"), filepath.Join(directory, "configs", "static")))
+		fileHelper := FileHelper{FileSystem: &FileSystem{}}
+		var entries []zipEntry
+
+		// when
+		err := fileHelper.walkInclude(filepath.Join(directory, "configs"), "configs", SymlinksFollow, []string{"*.sh"}, nil, &entries)
+
+		// then
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"configs/prod.yml", "configs/static/index.html", "configs/static/link.html"}, getEntryNames(entries))
+	})
+
+	t.Run("walkInclude with symlink loop returns error", func(t *testing.T) {
+		// given
+		directory := getIncludeDirectory(t)
+		assert.Nil(t, os.Symlink("..", filepath.Join(directory, "static", "parent")))
+		fileHelper := FileHelper{FileSystem: &FileSystem{}}
+		var entries []zipEntry
+
+		// when
+		err := fileHelper.walkInclude(filepath.Join(directory, "static"), "static", SymlinksFollow, nil, nil, &entries)
+
+		// then
+		assert.ErrorContains(t, err, "use symlinks: preserve to keep the link")
+	})
+
+	t.Run("includeMode keeps read and execute bits of owner", func(t *testing.T) {
+		assert.Equal(t, fs.FileMode(0755), includeMode("run.sh", 0700))
+		assert.Equal(t, fs.FileMode(0755), includeMode("run.sh", 0500))
+		assert.Equal(t, fs.FileMode(0644), includeMode("config.yml", 0600))
+		assert.Equal(t, fs.FileMode(0644), includeMode("config.yml", 0400))
+		assert.Equal(t, fs.FileMode(0200), includeMode("secret.yml", 0200))
+	})
+
+	t.Run("includeMode returns same mode whatever the umask", func(t *testing.T) {
+		for _, mode := range []fs.FileMode{0664, 0644, 0640, 0600} {
+			assert.Equal(t, fs.FileMode(0644), includeMode("config.yml", mode))
+		}
+
+		for _, mode := range []fs.FileMode{0775, 0755, 0750, 0700} {
+			assert.Equal(t, fs.FileMode(0755), includeMode("run.sh", mode))
+		}
+	})
+}
+
+// getIncludeDirectory returns a directory with static files, one of them linked, and a configs directory
+func getIncludeDirectory(t *testing.T) string {
+	directory := t.TempDir()
+
+	for name, content := range map[string]string{"static/index.html": "index", "static/run.sh": "run", "configs/prod.yml": "prod"} {
+		filePath := filepath.Join(directory, name)
+
+		if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := os.WriteFile(filePath, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := os.Chmod(filepath.Join(directory, "static", "run.sh"), 0750); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.Symlink("index.html", filepath.Join(directory, "static", "link.html")); err != nil {
+		t.Fatal(err)
+	}
+
+	return directory
+}
+
+func getEntryNames(entries []zipEntry) []string {
+	var names []string
+
+	for _, entry := range entries {
+		names = append(names, entry.name)
+	}
+
+	return names
+}
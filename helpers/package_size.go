@@ -0,0 +1,89 @@
+package helpers
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"sort"
+
+	"github.com/pkg/errors"
+	"github.com/samber/lo"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// maxZipSize is the limit of lambda on the size of the zip uploaded for a function
+	maxZipSize = 50 * 1024 * 1024
+	// maxUnzippedSize is the limit of lambda on the size of the function once unzipped
+	maxUnzippedSize = 250 * 1024 * 1024
+	// packageSizeReport is the number of largest files reported when a limit is exceeded
+	packageSizeReport = 10
+)
+
+// checkPackageSize verifies that the zip fits in the limits of lambda before it is uploaded, and reports the
+// largest files of the zip when it does not
+func checkPackageSize(content string) error {
+	reader, err := zip.NewReader(bytes.NewReader([]byte(content)), int64(len(content)))
+
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	zipSize := uint64(len(content))
+	unzippedSize := lo.SumBy[*zip.File, uint64](reader.File, func(file *zip.File) uint64 {
+		return file.UncompressedSize64
+	})
+
+	log.Debugf("artifact is %s zipped and %s unzipped", FormatSize(zipSize), FormatSize(unzippedSize))
+
+	var exceeded []string
+
+	if zipSize > maxZipSize {
+		exceeded = append(exceeded, fmt.Sprintf("%s zipped, over the limit of %s", FormatSize(zipSize), FormatSize(maxZipSize)))
+	}
+
+	if unzippedSize > maxUnzippedSize {
+		exceeded = append(exceeded, fmt.Sprintf("%s unzipped, over the limit of %s", FormatSize(unzippedSize), FormatSize(maxUnzippedSize)))
+	}
+
+	if len(exceeded) == 0 {
+		return nil
+	}
+
+	files := make([]*zip.File, len(reader.File))
+	copy(files, reader.File)
+
+	sort.SliceStable(files, func(i, j int) bool {
+		return files[i].UncompressedSize64 > files[j].UncompressedSize64
+	})
+
+	for _, limit := range exceeded {
+		log.Errorf("artifact is %s", limit)
+	}
+
+	log.Error("largest files:")
+
+	for _, file := range lo.Slice[*zip.File](files, 0, packageSizeReport) {
+		log.Errorf("  %s: %s, %s zipped", file.Name, FormatSize(file.UncompressedSize64), FormatSize(file.CompressedSize64))
+	}
+
+	return errors.New("artifact exceeds the size limits of lambda, strip the executable with -ldflags=\"-s -w\" or remove large files from the package includes")
+}
+
+// FormatSize returns a size in bytes in the largest unit under which it stays above 1
+func FormatSize(size uint64) string {
+	units := []string{"B", "KB", "MB", "GB"}
+	value := float64(size)
+	unit := 0
+
+	for value >= 1024 && unit < len(units)-1 {
+		value /= 1024
+		unit++
+	}
+
+	if unit == 0 {
+		return fmt.Sprintf("%d B", size)
+	}
+
+	return fmt.Sprintf("%.1f %s", value, units[unit])
+}
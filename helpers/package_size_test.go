@@ -0,0 +1,81 @@
+package helpers
+
+import (
+	"archive/zip"
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestPackageSize(t *testing.T) {
+	t.Run("checkPackageSize within limits returns nil", func(t *testing.T) {
+		// given
+		content := getSizedZip(t, map[string]uint64{"main": 10 * 1024 * 1024, "static/index.html": 1024})
+
+		// when
+		err := checkPackageSize(content)
+
+		// then
+		assert.Nil(t, err)
+	})
+
+	t.Run("checkPackageSize over unzipped limit returns error", func(t *testing.T) {
+		// given
+		content := getSizedZip(t, map[string]uint64{"main": 20 * 1024 * 1024, "static/video.mp4": 240 * 1024 * 1024})
+
+		// when
+		err := checkPackageSize(content)
+
+		// then
+		assert.ErrorContains(t, err, "artifact exceeds the size limits of lambda")
+	})
+
+	t.Run("checkPackageSize over zipped limit returns error", func(t *testing.T) {
+		// given
+		// data before the entries is skipped by zip readers, as in self-extracting archives
+		content := string(make([]byte, maxZipSize)) + getSizedZip(t, map[string]uint64{"main": 10 * 1024 * 1024})
+
+		// when
+		err := checkPackageSize(content)
+
+		// then
+		assert.ErrorContains(t, err, "artifact exceeds the size limits of lambda")
+	})
+
+	t.Run("checkPackageSize with invalid zip returns error", func(t *testing.T) {
+		// when
+		err := checkPackageSize("not a zip")
+
+		// then
+		assert.NotNil(t, err)
+	})
+
+	t.Run("FormatSize uses largest unit", func(t *testing.T) {
+		assert.Equal(t, "512 B", FormatSize(512))
+		assert.Equal(t, "1.5 KB", FormatSize(1536))
+		assert.Equal(t, "50.0 MB", FormatSize(maxZipSize))
+		assert.Equal(t, "2.0 GB", FormatSize(2*1024*1024*1024))
+	})
+}
+
+// getSizedZip returns a zip whose entries declare the given uncompressed sizes without holding their content
+func getSizedZip(t *testing.T, sizes map[string]uint64) string {
+	buffer := &bytes.Buffer{}
+	writer := zip.NewWriter(buffer)
+
+	for name, size := range sizes {
+		_, err := writer.CreateRaw(&zip.FileHeader{Name: name, Method: zip.Deflate, UncompressedSize64: size})
+
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	err := writer.Close()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return buffer.String()
+}
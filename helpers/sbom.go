@@ -0,0 +1,182 @@
+package helpers
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha256"
+	"debug/buildinfo"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"runtime/debug"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	sbomFilename    = "sbom.json"
+	sbomFormat      = "CycloneDX"
+	sbomSpecVersion = "1.4"
+	sbomHashAlg     = "SHA-256"
+)
+
+// cyclonedxBom is the subset of the CycloneDX specification describing an artifact, its go modules and its files
+type cyclonedxBom struct {
+	BomFormat   string               `json:"bomFormat"`
+	SpecVersion string               `json:"specVersion"`
+	Version     int                  `json:"version"`
+	Metadata    cyclonedxMetadata    `json:"metadata"`
+	Components  []cyclonedxComponent `json:"components"`
+}
+
+type cyclonedxMetadata struct {
+	Tools     []cyclonedxTool    `json:"tools"`
+	Component cyclonedxComponent `json:"component"`
+}
+
+type cyclonedxTool struct {
+	Name string `json:"name"`
+}
+
+type cyclonedxComponent struct {
+	Type       string              `json:"type"`
+	BomRef     string              `json:"bom-ref,omitempty"`
+	Name       string              `json:"name"`
+	Version    string              `json:"version,omitempty"`
+	Purl       string              `json:"purl,omitempty"`
+	Hashes     []cyclonedxHash     `json:"hashes,omitempty"`
+	Properties []cyclonedxProperty `json:"properties,omitempty"`
+}
+
+type cyclonedxHash struct {
+	Alg     string `json:"alg"`
+	Content string `json:"content"`
+}
+
+type cyclonedxProperty struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// GenerateSbom returns the CycloneDX software bill of materials of an artifact zip, listing the go modules
+// compiled in the executable and every file of the zip, and stores it in the build directory of the run. The sbom has no
+// timestamp so that identical artifacts have identical sboms.
+func (h *FileHelper) GenerateSbom(content string) (string, error) {
+	reader, err := zip.NewReader(bytes.NewReader([]byte(content)), int64(len(content)))
+
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+
+	bom := cyclonedxBom{
+		BomFormat:   sbomFormat,
+		SpecVersion: sbomSpecVersion,
+		Version:     1,
+		Metadata: cyclonedxMetadata{
+			Tools:     []cyclonedxTool{{Name: "flight"}},
+			Component: cyclonedxComponent{Type: "application", Name: executableFilename, Hashes: sbomHashes([]byte(content))},
+		},
+		Components: []cyclonedxComponent{},
+	}
+
+	for _, file := range reader.File {
+		if file.FileInfo().IsDir() || file.Mode()&os.ModeSymlink != 0 {
+			continue
+		}
+
+		data, err := readZipFile(file)
+
+		if err != nil {
+			return "", errors.WithStack(err)
+		}
+
+		if file.Name == executableFilename {
+			bom.addBuildInfo(data)
+		}
+
+		bom.Components = append(bom.Components, cyclonedxComponent{Type: "file", BomRef: "file:" + file.Name, Name: file.Name, Hashes: sbomHashes(data)})
+	}
+
+	sbom, err := json.MarshalIndent(bom, "", "  ")
+
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+
+	sbomPath, unlock, err := h.lockBuildPath(sbomFilename)
+
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+
+	err = h.FileSystem.WriteFile(sbomPath, sbom, 0644)
+	unlock()
+
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+
+	log.Debugf("sbom lists %d component(s)", len(bom.Components))
+
+	return string(sbom), nil
+}
+
+// addBuildInfo describes the main module of the executable and adds its dependencies, which executables not
+// built by go do not have
+func (b *cyclonedxBom) addBuildInfo(executable []byte) {
+	info, err := buildinfo.Read(bytes.NewReader(executable))
+
+	if err != nil {
+		log.Warnf("executable has no go build info, the sbom only lists the files of the artifact: %v", err)
+
+		return
+	}
+
+	b.Metadata.Component.BomRef = sbomPurl(info.Main.Path, info.Main.Version)
+	b.Metadata.Component.Name = info.Main.Path
+	b.Metadata.Component.Version = info.Main.Version
+	b.Metadata.Component.Purl = b.Metadata.Component.BomRef
+	b.Metadata.Component.Properties = []cyclonedxProperty{{Name: "go:version", Value: info.GoVersion}}
+
+	for _, setting := range info.Settings {
+		b.Metadata.Component.Properties = append(b.Metadata.Component.Properties, cyclonedxProperty{Name: "go:build:" + setting.Key, Value: setting.Value})
+	}
+
+	for _, dependency := range info.Deps {
+		b.Components = append(b.Components, sbomModule(dependency))
+	}
+}
+
+// sbomModule returns the component of a go module, replaced modules being described by their replacement
+func sbomModule(module *debug.Module) cyclonedxComponent {
+	var properties []cyclonedxProperty
+
+	if module.Replace != nil {
+		properties = append(properties, cyclonedxProperty{Name: "go:replaces", Value: sbomPurl(module.Path, module.Version)})
+		module = module.Replace
+	}
+
+	if module.Sum != "" {
+		properties = append(properties, cyclonedxProperty{Name: "go:sum", Value: module.Sum})
+	}
+
+	purl := sbomPurl(module.Path, module.Version)
+
+	return cyclonedxComponent{Type: "library", BomRef: purl, Name: module.Path, Version: module.Version, Purl: purl, Properties: properties}
+}
+
+func sbomPurl(path string, version string) string {
+	if version == "" {
+		return fmt.Sprintf("pkg:golang/%s", path)
+	}
+
+	return fmt.Sprintf("pkg:golang/%s@%s", path, version)
+}
+
+func sbomHashes(data []byte) []cyclonedxHash {
+	sum := sha256.Sum256(data)
+
+	return []cyclonedxHash{{Alg: sbomHashAlg, Content: hex.EncodeToString(sum[:])}}
+}
@@ -0,0 +1,91 @@
+package helpers
+
+import (
+	"debug/elf"
+	"encoding/json"
+	"github.com/getflight/flight/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"testing"
+)
+
+func TestSbom(t *testing.T) {
+	t.Run("GenerateSbom lists files of artifact and stores sbom in build directory", func(t *testing.T) {
+		// given
+		content := getScanZip(t, map[string]string{"main": string(getElfHeader(t, elf.EM_X86_64)), "static/index.html": "index"})
+
+		fileSystemMock := &mocks.FileSystemMock{}
+		fileSystemMock.On("UserHomeDir").Return("home", nil)
+		fileSystemMock.On("MkdirAll", filepath.Join("home", ".flight", "build"), os.ModeDir).Return(nil)
+		fileSystemMock.On("MkdirTemp", filepath.Join("home", ".flight", "build"), mock.Anything).Return(filepath.Join("home", ".flight", "build", "run-1"), nil)
+		fileSystemMock.On("WriteFile", filepath.Join("home", ".flight", "build", "run-1", "sbom.json"), mock.Anything, mock.Anything).Return(nil)
+
+		fileHelper := FileHelper{FileSystem: fileSystemMock}
+
+		// when
+		sbom, err := fileHelper.GenerateSbom(content)
+
+		// then
+		assert.Nil(t, err)
+		fileSystemMock.AssertExpectations(t)
+
+		bom := cyclonedxBom{}
+		assert.Nil(t, json.Unmarshal([]byte(sbom), &bom))
+		assert.Equal(t, "CycloneDX", bom.BomFormat)
+		assert.Equal(t, "1.4", bom.SpecVersion)
+		assert.Equal(t, "application", bom.Metadata.Component.Type)
+		assert.Equal(t, []cyclonedxComponent{
+			{Type: "file", BomRef: "file:main", Name: "main", Hashes: sbomHashes(getElfHeader(t, elf.EM_X86_64))},
+			{Type: "file", BomRef: "file:static/index.html", Name: "static/index.html", Hashes: []cyclonedxHash{{Alg: "SHA-256", Content: "1bc04b5291c26a46d918139138b992d2de976d6851d0893b0476b85bfbdfc6e6"}}},
+		}, bom.Components)
+	})
+
+	t.Run("GenerateSbom is identical for identical artifacts", func(t *testing.T) {
+		// given
+		executable, err := os.ReadFile(os.Args[0])
+		assert.Nil(t, err)
+		content := getScanZip(t, map[string]string{"main": string(executable)})
+
+		fileSystemMock := &mocks.FileSystemMock{}
+		fileSystemMock.On("UserHomeDir").Return("home", nil)
+		fileSystemMock.On("MkdirAll", mock.Anything, mock.Anything).Return(nil)
+		fileSystemMock.On("MkdirTemp", mock.Anything, mock.Anything).Return("run-1", nil)
+		fileSystemMock.On("WriteFile", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+		fileHelper := FileHelper{FileSystem: fileSystemMock}
+
+		// when
+		first, firstErr := fileHelper.GenerateSbom(content)
+		second, secondErr := fileHelper.GenerateSbom(content)
+
+		// then
+		assert.Nil(t, firstErr)
+		assert.Nil(t, secondErr)
+		assert.Equal(t, first, second)
+		assert.Contains(t, first, `"purl": "pkg:golang/github.com/stretchr/testify@v1.8.0"`)
+	})
+
+	t.Run("sbomModule describes replaced module by its replacement", func(t *testing.T) {
+		// given
+		module := &debug.Module{Path: "github.com/a/b", Version: "v1.0.0", Replace: &debug.Module{Path: "github.com/c/b", Version: "v1.0.1", Sum: "h1:abc="}}
+
+		// when
+		component := sbomModule(module)
+
+		// then
+		assert.Equal(t, cyclonedxComponent{
+			Type:    "library",
+			BomRef:  "pkg:golang/github.com/c/b@v1.0.1",
+			Name:    "github.com/c/b",
+			Version: "v1.0.1",
+			Purl:    "pkg:golang/github.com/c/b@v1.0.1",
+			Properties: []cyclonedxProperty{
+				{Name: "go:replaces", Value: "pkg:golang/github.com/a/b@v1.0.0"},
+				{Name: "go:sum", Value: "h1:abc="},
+			},
+		}, component)
+	})
+}
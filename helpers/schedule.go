@@ -0,0 +1,351 @@
+package helpers
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// scheduleSearchYears bounds the search of fire times for expressions that rarely or never fire
+	scheduleSearchYears = 200
+)
+
+var (
+	monthNames   = map[string]int{"JAN": 1, "FEB": 2, "MAR": 3, "APR": 4, "MAY": 5, "JUN": 6, "JUL": 7, "AUG": 8, "SEP": 9, "OCT": 10, "NOV": 11, "DEC": 12}
+	weekdayNames = map[string]int{"SUN": 1, "MON": 2, "TUE": 3, "WED": 4, "THU": 5, "FRI": 6, "SAT": 7}
+	rateUnits    = map[string]time.Duration{"minute": time.Minute, "minutes": time.Minute, "hour": time.Hour, "hours": time.Hour, "day": 24 * time.Hour, "days": 24 * time.Hour}
+)
+
+// Schedule is a parsed schedule expression, using the cron(...) and rate(...) syntax of scheduled lambda triggers.
+// Cron expressions have six fields: minutes, hours, day of month, month, day of week and year, and are evaluated in UTC.
+type Schedule struct {
+	rate    time.Duration
+	minutes []bool
+	hours   []bool
+	months  []bool
+	years   []bool
+	day     func(date time.Time) bool
+}
+
+// ParseSchedule parses a cron(...) or rate(...) expression
+func ParseSchedule(expression string) (*Schedule, error) {
+	expression = strings.TrimSpace(expression)
+
+	switch {
+	case strings.HasPrefix(expression, "rate(") && strings.HasSuffix(expression, ")"):
+		return parseRate(strings.TrimSuffix(strings.TrimPrefix(expression, "rate("), ")"))
+	case strings.HasPrefix(expression, "cron(") && strings.HasSuffix(expression, ")"):
+		return parseCron(strings.TrimSuffix(strings.TrimPrefix(expression, "cron("), ")"))
+	}
+
+	return nil, errors.New("expression must be of the form cron(...) or rate(...)")
+}
+
+// Next returns the next count fire times strictly after the given time. Rate expressions fire at a fixed
+// interval from the time the schedule is deployed, which is assumed to be the given time.
+func (s *Schedule) Next(after time.Time, count int) []time.Time {
+	var times []time.Time
+
+	if s.rate > 0 {
+		for i := 1; i <= count; i++ {
+			times = append(times, after.Add(time.Duration(i)*s.rate))
+		}
+
+		return times
+	}
+
+	after = after.UTC()
+	start := time.Date(after.Year(), after.Month(), after.Day(), 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(scheduleSearchYears, 0, 0)
+
+	for date := start; date.Before(end) && len(times) < count; date = date.AddDate(0, 0, 1) {
+		if date.Year()-1970 >= len(s.years) {
+			break
+		}
+
+		if !s.years[date.Year()-1970] || !s.months[int(date.Month())] || !s.day(date) {
+			continue
+		}
+
+		for hour := 0; hour < 24 && len(times) < count; hour++ {
+			if !s.hours[hour] {
+				continue
+			}
+
+			for minute := 0; minute < 60 && len(times) < count; minute++ {
+				fireTime := date.Add(time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute)
+
+				if s.minutes[minute] && fireTime.After(after) {
+					times = append(times, fireTime)
+				}
+			}
+		}
+	}
+
+	return times
+}
+
+func parseRate(rate string) (*Schedule, error) {
+	fields := strings.Fields(rate)
+
+	if len(fields) != 2 {
+		return nil, errors.New("rate must be of the form rate(value unit), for instance rate(5 minutes)")
+	}
+
+	value, err := strconv.Atoi(fields[0])
+
+	if err != nil || value <= 0 {
+		return nil, errors.New(fmt.Sprintf("rate value %s must be a positive number", fields[0]))
+	}
+
+	unit, found := rateUnits[fields[1]]
+
+	if !found {
+		return nil, errors.New(fmt.Sprintf("rate unit %s must be one of minute(s), hour(s) or day(s)", fields[1]))
+	}
+
+	if value == 1 && strings.HasSuffix(fields[1], "s") || value > 1 && !strings.HasSuffix(fields[1], "s") {
+		return nil, errors.New("rate unit must be singular for a value of 1 and plural otherwise")
+	}
+
+	return &Schedule{rate: time.Duration(value) * unit}, nil
+}
+
+func parseCron(cron string) (*Schedule, error) {
+	fields := strings.Fields(cron)
+
+	if len(fields) != 6 {
+		return nil, errors.New(fmt.Sprintf("cron must have 6 fields (minutes hours day-of-month month day-of-week year), got %d", len(fields)))
+	}
+
+	if (fields[2] == "?") == (fields[4] == "?") {
+		return nil, errors.New("cron must use ? in exactly one of the day-of-month and day-of-week fields")
+	}
+
+	var err error
+	schedule := &Schedule{}
+
+	if schedule.minutes, err = parseCronField(fields[0], 0, 59, nil); err != nil {
+		return nil, errors.Wrap(err, "invalid minutes")
+	}
+
+	if schedule.hours, err = parseCronField(fields[1], 0, 23, nil); err != nil {
+		return nil, errors.Wrap(err, "invalid hours")
+	}
+
+	if schedule.months, err = parseCronField(fields[3], 1, 12, monthNames); err != nil {
+		return nil, errors.Wrap(err, "invalid month")
+	}
+
+	years, err := parseCronField(fields[5], 1970, 2199, nil)
+
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid year")
+	}
+
+	schedule.years = years[1970:]
+
+	if fields[2] != "?" {
+		schedule.day, err = parseDayOfMonth(fields[2])
+
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid day-of-month")
+		}
+	} else {
+		schedule.day, err = parseDayOfWeek(fields[4])
+
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid day-of-week")
+		}
+	}
+
+	return schedule, nil
+}
+
+// parseCronField parses a list of values, ranges and increments, such as 0,30 or 1-5 or */10, into the set
+// of matching values
+func parseCronField(field string, min int, max int, names map[string]int) ([]bool, error) {
+	values := make([]bool, max+1)
+
+	for _, element := range strings.Split(field, ",") {
+		rangeElement, stepElement, hasStep := strings.Cut(element, "/")
+		step := 1
+
+		if hasStep {
+			var err error
+			step, err = strconv.Atoi(stepElement)
+
+			if err != nil || step <= 0 {
+				return nil, errors.New(fmt.Sprintf("increment %s must be a positive number", stepElement))
+			}
+		}
+
+		start, end := min, max
+
+		if rangeElement != "*" && rangeElement != "?" {
+			startElement, endElement, isRange := strings.Cut(rangeElement, "-")
+
+			var err error
+			start, err = parseCronValue(startElement, min, max, names)
+
+			if err != nil {
+				return nil, err
+			}
+
+			end = start
+
+			if isRange {
+				end, err = parseCronValue(endElement, min, max, names)
+
+				if err != nil {
+					return nil, err
+				}
+			} else if hasStep {
+				end = max
+			}
+
+			if end < start {
+				return nil, errors.New(fmt.Sprintf("range %s must go from the lowest to the highest value", rangeElement))
+			}
+		}
+
+		for value := start; value <= end; value += step {
+			values[value] = true
+		}
+	}
+
+	return values, nil
+}
+
+func parseCronValue(element string, min int, max int, names map[string]int) (int, error) {
+	if value, found := names[strings.ToUpper(element)]; found {
+		return value, nil
+	}
+
+	value, err := strconv.Atoi(element)
+
+	if err != nil || value < min || value > max {
+		return 0, errors.New(fmt.Sprintf("value %s must be between %d and %d", element, min, max))
+	}
+
+	return value, nil
+}
+
+// parseDayOfMonth parses the day-of-month field, which supports L for the last day of the month and W for
+// the weekday closest to a day of the month
+func parseDayOfMonth(field string) (func(date time.Time) bool, error) {
+	switch {
+	case field == "L":
+		return func(date time.Time) bool {
+			return date.Day() == lastDayOfMonth(date)
+		}, nil
+	case field == "LW":
+		return func(date time.Time) bool {
+			return date.Day() == closestWeekday(date, lastDayOfMonth(date))
+		}, nil
+	case strings.HasSuffix(field, "W"):
+		day, err := parseCronValue(strings.TrimSuffix(field, "W"), 1, 31, nil)
+
+		if err != nil {
+			return nil, err
+		}
+
+		return func(date time.Time) bool {
+			return date.Day() == closestWeekday(date, day)
+		}, nil
+	}
+
+	days, err := parseCronField(field, 1, 31, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return func(date time.Time) bool {
+		return days[date.Day()]
+	}, nil
+}
+
+// parseDayOfWeek parses the day-of-week field, where 1 is sunday. It supports nL for the last given weekday
+// of the month and n#k for the k-th given weekday of the month.
+func parseDayOfWeek(field string) (func(date time.Time) bool, error) {
+	if weekdayElement, nthElement, isNth := strings.Cut(field, "#"); isNth {
+		weekday, err := parseCronValue(weekdayElement, 1, 7, weekdayNames)
+
+		if err != nil {
+			return nil, err
+		}
+
+		nth, err := parseCronValue(nthElement, 1, 5, nil)
+
+		if err != nil {
+			return nil, err
+		}
+
+		return func(date time.Time) bool {
+			return int(date.Weekday())+1 == weekday && (date.Day()-1)/7+1 == nth
+		}, nil
+	}
+
+	if strings.HasSuffix(field, "L") && field != "L" {
+		weekday, err := parseCronValue(strings.TrimSuffix(field, "L"), 1, 7, weekdayNames)
+
+		if err != nil {
+			return nil, err
+		}
+
+		return func(date time.Time) bool {
+			return int(date.Weekday())+1 == weekday && date.Day()+7 > lastDayOfMonth(date)
+		}, nil
+	}
+
+	if field == "L" {
+		field = "7"
+	}
+
+	weekdays, err := parseCronField(field, 1, 7, weekdayNames)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return func(date time.Time) bool {
+		return weekdays[int(date.Weekday())+1]
+	}, nil
+}
+
+func lastDayOfMonth(date time.Time) int {
+	return time.Date(date.Year(), date.Month()+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+// closestWeekday returns the weekday closest to the given day of the month, without leaving the month
+func closestWeekday(date time.Time, day int) int {
+	last := lastDayOfMonth(date)
+
+	if day > last {
+		day = last
+	}
+
+	target := time.Date(date.Year(), date.Month(), day, 0, 0, 0, 0, time.UTC)
+
+	switch target.Weekday() {
+	case time.Saturday:
+		if day == 1 {
+			return day + 2
+		}
+
+		return day - 1
+	case time.Sunday:
+		if day == last {
+			return day - 2
+		}
+
+		return day + 1
+	}
+
+	return day
+}
@@ -0,0 +1,116 @@
+package helpers
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestSchedule(t *testing.T) {
+	after := time.Date(2024, time.January, 30, 12, 0, 0, 0, time.UTC)
+
+	t.Run("ParseSchedule with rate returns times at interval", func(t *testing.T) {
+		// given
+		expression := "rate(5 minutes)"
+
+		// when
+		schedule, err := ParseSchedule(expression)
+
+		// then
+		assert.Nil(t, err)
+		assert.Equal(t, []time.Time{after.Add(5 * time.Minute), after.Add(10 * time.Minute)}, schedule.Next(after, 2))
+	})
+
+	t.Run("ParseSchedule with nightly cron returns next nights", func(t *testing.T) {
+		// given
+		expression := "cron(30 2 * * ? *)"
+
+		// when
+		schedule, err := ParseSchedule(expression)
+
+		// then
+		assert.Nil(t, err)
+		assert.Equal(t, []time.Time{
+			time.Date(2024, time.January, 31, 2, 30, 0, 0, time.UTC),
+			time.Date(2024, time.February, 1, 2, 30, 0, 0, time.UTC),
+		}, schedule.Next(after, 2))
+	})
+
+	t.Run("ParseSchedule with weekdays and steps returns matching times", func(t *testing.T) {
+		// given
+		expression := "cron(0/20 8-9 ? * MON-FRI *)"
+
+		// when
+		schedule, err := ParseSchedule(expression)
+
+		// then
+		assert.Nil(t, err)
+		assert.Equal(t, []time.Time{
+			time.Date(2024, time.January, 31, 8, 0, 0, 0, time.UTC),
+			time.Date(2024, time.January, 31, 8, 20, 0, 0, time.UTC),
+			time.Date(2024, time.January, 31, 8, 40, 0, 0, time.UTC),
+			time.Date(2024, time.January, 31, 9, 0, 0, 0, time.UTC),
+		}, schedule.Next(after, 4))
+	})
+
+	t.Run("ParseSchedule with last day of month returns last days", func(t *testing.T) {
+		// given
+		expression := "cron(0 0 L * ? 2024)"
+
+		// when
+		schedule, err := ParseSchedule(expression)
+
+		// then
+		assert.Nil(t, err)
+		assert.Equal(t, []time.Time{
+			time.Date(2024, time.January, 31, 0, 0, 0, 0, time.UTC),
+			time.Date(2024, time.February, 29, 0, 0, 0, 0, time.UTC),
+		}, schedule.Next(after, 2))
+	})
+
+	t.Run("ParseSchedule with nth weekday returns matching days", func(t *testing.T) {
+		// given
+		expression := "cron(0 10 ? * 3#2 *)"
+
+		// when
+		schedule, err := ParseSchedule(expression)
+
+		// then
+		assert.Nil(t, err)
+		assert.Equal(t, []time.Time{
+			time.Date(2024, time.February, 13, 10, 0, 0, 0, time.UTC),
+			time.Date(2024, time.March, 12, 10, 0, 0, 0, time.UTC),
+		}, schedule.Next(after, 2))
+	})
+
+	t.Run("ParseSchedule with past year returns no times", func(t *testing.T) {
+		// given
+		expression := "cron(0 0 1 1 ? 2020)"
+
+		// when
+		schedule, err := ParseSchedule(expression)
+
+		// then
+		assert.Nil(t, err)
+		assert.Empty(t, schedule.Next(after, 5))
+	})
+
+	t.Run("ParseSchedule with invalid expressions returns error", func(t *testing.T) {
+		for _, expression := range []string{
+			"0 2 * * *",
+			"rate(1 minutes)",
+			"rate(5 weeks)",
+			"cron(0 2 * * *)",
+			"cron(0 2 * * MON *)",
+			"cron(60 2 * * ? *)",
+			"cron(0 2 ? * 8 *)",
+			"cron(0 5-2 * * ? *)",
+		} {
+			// when
+			_, err := ParseSchedule(expression)
+
+			// then
+			assert.NotNil(t, err, expression)
+		}
+	})
+}
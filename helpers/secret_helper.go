@@ -0,0 +1,407 @@
+package helpers
+
+import (
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"github.com/getflight/flight/formatters"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/crypto/chacha20poly1305"
+	"golang.org/x/crypto/curve25519"
+	"golang.org/x/crypto/hkdf"
+)
+
+const (
+	secretKeyFilename    = "secret.key"
+	oldSecretKeyFilename = "secret.key.old"
+	encryptedPrefix      = "ENC["
+	encryptedSuffix      = "]"
+	secretKeyInfo        = "flight secrets"
+)
+
+type SecretHelperType interface {
+	PublicKey() (string, error)
+	Encrypt(value string, recipients []string) (string, error)
+	Decrypt(value string) (string, error)
+	Recipients(value string) ([]string, error)
+	NewKey() (string, string, error)
+	RotateKey(privateKey string) error
+}
+
+// SecretHelper encrypts values for X25519 public keys, so they can be committed in the manifest and only
+// decrypted by the holders of the matching private keys. The private key is kept in the work path.
+type SecretHelper struct {
+	FileHelper FileHelperType
+}
+
+// secretEnvelope is the content of an encrypted value. The value is encrypted with a random key, which is
+// wrapped for each recipient with a key agreed between an ephemeral key and the public key of the recipient.
+type secretEnvelope struct {
+	Recipients []secretRecipient `json:"r"`
+	Nonce      []byte            `json:"n"`
+	Data       []byte            `json:"d"`
+}
+
+type secretRecipient struct {
+	PublicKey    string `json:"k"`
+	EphemeralKey []byte `json:"e"`
+	WrappedKey   []byte `json:"w"`
+}
+
+// IsEncrypted returns whether a value was encrypted by SecretHelper
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, encryptedPrefix) && strings.HasSuffix(value, encryptedSuffix)
+}
+
+// IsPublicKey returns whether a value is a public key that values can be encrypted for
+func IsPublicKey(value string) bool {
+	key, err := base64.StdEncoding.DecodeString(value)
+
+	return err == nil && len(key) == curve25519.PointSize
+}
+
+// PublicKey returns the public key of the user, generating a key pair on first use
+func (h *SecretHelper) PublicKey() (string, error) {
+	privateKey, err := h.getPrivateKey(secretKeyFilename)
+
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+
+	if privateKey == nil {
+		log.Info("generating secret key")
+		privateKey, err = h.generatePrivateKey()
+
+		if err != nil {
+			return "", errors.WithStack(err)
+		}
+	}
+
+	return publicKey(privateKey)
+}
+
+// Encrypt encrypts a value for the given public keys, or for the public key of the user when there are none
+func (h *SecretHelper) Encrypt(value string, recipients []string) (string, error) {
+	if len(recipients) == 0 {
+		ownPublicKey, err := h.PublicKey()
+
+		if err != nil {
+			return "", errors.WithStack(err)
+		}
+
+		recipients = []string{ownPublicKey}
+	}
+
+	fileKey := make([]byte, chacha20poly1305.KeySize)
+	envelope := secretEnvelope{Nonce: make([]byte, chacha20poly1305.NonceSize)}
+
+	if _, err := io.ReadFull(rand.Reader, fileKey); err != nil {
+		return "", errors.WithStack(err)
+	}
+
+	if _, err := io.ReadFull(rand.Reader, envelope.Nonce); err != nil {
+		return "", errors.WithStack(err)
+	}
+
+	for _, recipient := range recipients {
+		wrapped, err := wrapKey(fileKey, recipient)
+
+		if err != nil {
+			return "", errors.WithStack(err)
+		}
+
+		envelope.Recipients = append(envelope.Recipients, *wrapped)
+	}
+
+	aead, err := chacha20poly1305.New(fileKey)
+
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+
+	envelope.Data = aead.Seal(nil, envelope.Nonce, []byte(value), nil)
+
+	content, err := json.Marshal(envelope)
+
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+
+	return encryptedPrefix + base64.StdEncoding.EncodeToString(content) + encryptedSuffix, nil
+}
+
+// Decrypt decrypts a value encrypted for the public key of the user. Values encrypted for the key replaced by
+// the last rotation can still be decrypted.
+func (h *SecretHelper) Decrypt(value string) (string, error) {
+	envelope, err := parseEnvelope(value)
+
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+
+	for _, filename := range []string{secretKeyFilename, oldSecretKeyFilename} {
+		privateKey, err := h.getPrivateKey(filename)
+
+		if err != nil {
+			return "", errors.WithStack(err)
+		}
+
+		if privateKey == nil {
+			continue
+		}
+
+		plaintext, found, err := openEnvelope(envelope, privateKey)
+
+		if err != nil {
+			return "", errors.WithStack(err)
+		}
+
+		if found {
+			return plaintext, nil
+		}
+	}
+
+	return "", errors.New("value is not encrypted for your key, ask a recipient to encrypt it for your public key")
+}
+
+// Recipients returns the public keys a value was encrypted for
+func (h *SecretHelper) Recipients(value string) ([]string, error) {
+	envelope, err := parseEnvelope(value)
+
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	var recipients []string
+
+	for _, recipient := range envelope.Recipients {
+		recipients = append(recipients, recipient.PublicKey)
+	}
+
+	return recipients, nil
+}
+
+// NewKey generates a key pair without replacing the key of the user, and returns the private key to give to
+// RotateKey along with the public key, so that values can be encrypted for the new key before it is used
+func (h *SecretHelper) NewKey() (string, string, error) {
+	privateKey := make([]byte, curve25519.ScalarSize)
+
+	if _, err := io.ReadFull(rand.Reader, privateKey); err != nil {
+		return "", "", errors.WithStack(err)
+	}
+
+	encodedKey := base64.StdEncoding.EncodeToString(privateKey)
+	formatters.MaskSecret(encodedKey)
+
+	newPublicKey, err := publicKey(privateKey)
+
+	if err != nil {
+		return "", "", errors.WithStack(err)
+	}
+
+	return encodedKey, newPublicKey, nil
+}
+
+// RotateKey replaces the key of the user by a private key returned by NewKey. The previous key is kept to
+// decrypt the values until they are encrypted again.
+func (h *SecretHelper) RotateKey(privateKey string) error {
+	if key, err := base64.StdEncoding.DecodeString(privateKey); err != nil || len(key) != curve25519.ScalarSize {
+		return errors.New("new secret key is malformed")
+	}
+
+	previousKey, err := h.FileHelper.ReadFile(secretKeyFilename)
+
+	if err != nil && !os.IsNotExist(err) {
+		return errors.Wrap(err, fmt.Sprintf("failed to read secret key %s", secretKeyFilename))
+	}
+
+	if err != nil || strings.TrimSpace(previousKey) == "" {
+		return errors.New("secret key not found, encrypt a value to generate one")
+	}
+
+	formatters.MaskSecret(strings.TrimSpace(previousKey))
+
+	err = h.FileHelper.WriteSecretFile(previousKey, oldSecretKeyFilename)
+
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	return errors.WithStack(h.FileHelper.WriteSecretFile(privateKey, secretKeyFilename))
+}
+
+func (h *SecretHelper) generatePrivateKey() ([]byte, error) {
+	encodedKey, _, err := h.NewKey()
+
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	err = h.FileHelper.WriteSecretFile(encodedKey, secretKeyFilename)
+
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	privateKey, _ := base64.StdEncoding.DecodeString(encodedKey)
+
+	return privateKey, nil
+}
+
+// getPrivateKey reads a private key from the work path, which is nil when the key does not exist. Any other
+// error is returned, as a key that cannot be read must not be replaced by a new one.
+func (h *SecretHelper) getPrivateKey(filename string) ([]byte, error) {
+	content, err := h.FileHelper.ReadFile(filename)
+
+	if err != nil && !os.IsNotExist(err) {
+		return nil, errors.Wrap(err, fmt.Sprintf("failed to read secret key %s", filename))
+	}
+
+	if err != nil || strings.TrimSpace(content) == "" {
+		log.Debugf("secret key %s not found", filename)
+
+		return nil, nil
+	}
+
+	privateKey, err := base64.StdEncoding.DecodeString(strings.TrimSpace(content))
+
+	if err != nil || len(privateKey) != curve25519.ScalarSize {
+		return nil, errors.New(fmt.Sprintf("secret key %s is malformed", filename))
+	}
+
+	return privateKey, nil
+}
+
+func parseEnvelope(value string) (*secretEnvelope, error) {
+	if !IsEncrypted(value) {
+		return nil, errors.New("value is not encrypted, encrypted values are of the form ENC[...]")
+	}
+
+	content, err := base64.StdEncoding.DecodeString(strings.TrimSuffix(strings.TrimPrefix(value, encryptedPrefix), encryptedSuffix))
+
+	if err != nil {
+		return nil, errors.Wrap(err, "encrypted value is malformed")
+	}
+
+	envelope := &secretEnvelope{}
+	err = json.Unmarshal(content, envelope)
+
+	if err != nil {
+		return nil, errors.Wrap(err, "encrypted value is malformed")
+	}
+
+	return envelope, nil
+}
+
+func publicKey(privateKey []byte) (string, error) {
+	key, err := curve25519.X25519(privateKey, curve25519.Basepoint)
+
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+
+	return base64.StdEncoding.EncodeToString(key), nil
+}
+
+func wrapKey(fileKey []byte, recipient string) (*secretRecipient, error) {
+	if !IsPublicKey(recipient) {
+		return nil, errors.New(fmt.Sprintf("recipient %s is not a valid public key", recipient))
+	}
+
+	recipientKey, _ := base64.StdEncoding.DecodeString(recipient)
+	ephemeralPrivateKey := make([]byte, curve25519.ScalarSize)
+
+	if _, err := io.ReadFull(rand.Reader, ephemeralPrivateKey); err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	ephemeralKey, err := curve25519.X25519(ephemeralPrivateKey, curve25519.Basepoint)
+
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	wrappingCipher, err := newWrappingCipher(ephemeralPrivateKey, recipientKey, ephemeralKey, recipientKey)
+
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return &secretRecipient{
+		PublicKey:    recipient,
+		EphemeralKey: ephemeralKey,
+		WrappedKey:   wrappingCipher.Seal(nil, make([]byte, chacha20poly1305.NonceSize), fileKey, nil),
+	}, nil
+}
+
+// openEnvelope decrypts the envelope with the given private key, found is false when the envelope was not
+// encrypted for its public key
+func openEnvelope(envelope *secretEnvelope, privateKey []byte) (string, bool, error) {
+	ownPublicKey, err := publicKey(privateKey)
+
+	if err != nil {
+		return "", false, errors.WithStack(err)
+	}
+
+	for _, recipient := range envelope.Recipients {
+		if recipient.PublicKey != ownPublicKey {
+			continue
+		}
+
+		recipientKey, _ := base64.StdEncoding.DecodeString(ownPublicKey)
+		wrappingCipher, err := newWrappingCipher(privateKey, recipient.EphemeralKey, recipient.EphemeralKey, recipientKey)
+
+		if err != nil {
+			return "", false, errors.WithStack(err)
+		}
+
+		fileKey, err := wrappingCipher.Open(nil, make([]byte, chacha20poly1305.NonceSize), recipient.WrappedKey, nil)
+
+		if err != nil {
+			return "", false, errors.Wrap(err, "failed to decrypt value")
+		}
+
+		aead, err := chacha20poly1305.New(fileKey)
+
+		if err != nil {
+			return "", false, errors.WithStack(err)
+		}
+
+		plaintext, err := aead.Open(nil, envelope.Nonce, envelope.Data, nil)
+
+		if err != nil {
+			return "", false, errors.Wrap(err, "failed to decrypt value")
+		}
+
+		return string(plaintext), true, nil
+	}
+
+	return "", false, nil
+}
+
+// newWrappingCipher returns the cipher wrapping the value key, derived from the key agreed between a private
+// key and a public key, and bound to the ephemeral and recipient keys
+func newWrappingCipher(privateKey []byte, publicKey []byte, ephemeralKey []byte, recipientKey []byte) (cipher.AEAD, error) {
+	sharedKey, err := curve25519.X25519(privateKey, publicKey)
+
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	wrappingKey := make([]byte, chacha20poly1305.KeySize)
+	salt := append(append([]byte{}, ephemeralKey...), recipientKey...)
+
+	if _, err := io.ReadFull(hkdf.New(sha256.New, sharedKey, salt, []byte(secretKeyInfo)), wrappingKey); err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return chacha20poly1305.New(wrappingKey)
+}
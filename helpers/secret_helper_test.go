@@ -0,0 +1,179 @@
+package helpers
+
+import (
+	"github.com/getflight/flight/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSecretHelper(t *testing.T) {
+	t.Run("Encrypt without recipients encrypts for own key", func(t *testing.T) {
+		// given
+		secretHelper := getSecretHelper(t)
+
+		// when
+		encrypted, err := secretHelper.Encrypt("secret", nil)
+
+		// then
+		assert.Nil(t, err)
+		assert.True(t, IsEncrypted(encrypted))
+		assert.NotContains(t, encrypted, "secret")
+
+		decrypted, err := secretHelper.Decrypt(encrypted)
+		assert.Nil(t, err)
+		assert.Equal(t, "secret", decrypted)
+	})
+
+	t.Run("Encrypt with recipients encrypts for every recipient", func(t *testing.T) {
+		// given
+		otherSecretHelper := getSecretHelper(t)
+		otherPublicKey, err := otherSecretHelper.PublicKey()
+		assert.Nil(t, err)
+
+		secretHelper := getSecretHelper(t)
+		ownPublicKey, err := secretHelper.PublicKey()
+		assert.Nil(t, err)
+
+		// when
+		encrypted, err := secretHelper.Encrypt("secret", []string{otherPublicKey})
+
+		// then
+		assert.Nil(t, err)
+
+		recipients, err := secretHelper.Recipients(encrypted)
+		assert.Nil(t, err)
+		assert.Equal(t, []string{otherPublicKey}, recipients)
+		assert.NotEqual(t, ownPublicKey, otherPublicKey)
+
+		_, err = secretHelper.Decrypt(encrypted)
+		assert.NotNil(t, err)
+	})
+
+	t.Run("Encrypt with invalid recipient returns error", func(t *testing.T) {
+		// given
+		secretHelper := getSecretHelper(t)
+
+		// when
+		_, err := secretHelper.Encrypt("secret", []string{"invalid"})
+
+		// then
+		assert.NotNil(t, err)
+	})
+
+	t.Run("RotateKey keeps previous key to decrypt values", func(t *testing.T) {
+		// given
+		secretHelper := getSecretHelper(t)
+		previousPublicKey, err := secretHelper.PublicKey()
+		assert.Nil(t, err)
+
+		encrypted, err := secretHelper.Encrypt("secret", nil)
+		assert.Nil(t, err)
+
+		privateKey, newPublicKey, err := secretHelper.NewKey()
+		assert.Nil(t, err)
+
+		// when
+		err = secretHelper.RotateKey(privateKey)
+
+		// then
+		assert.Nil(t, err)
+		assert.NotEqual(t, previousPublicKey, newPublicKey)
+
+		publicKey, err := secretHelper.PublicKey()
+		assert.Nil(t, err)
+		assert.Equal(t, newPublicKey, publicKey)
+
+		decrypted, err := secretHelper.Decrypt(encrypted)
+		assert.Nil(t, err)
+		assert.Equal(t, "secret", decrypted)
+	})
+
+	t.Run("PublicKey writes private key only readable by user", func(t *testing.T) {
+		// given
+		secretHelper := getSecretHelper(t)
+		keyPath := filepath.Join(UserWorkPath, ".flight", secretKeyFilename)
+		assert.Nil(t, os.MkdirAll(filepath.Dir(keyPath), 0755))
+		assert.Nil(t, os.WriteFile(keyPath, []byte{}, 0644))
+
+		// when
+		_, err := secretHelper.PublicKey()
+
+		// then
+		assert.Nil(t, err)
+		info, err := os.Stat(keyPath)
+		assert.Nil(t, err)
+		assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+	})
+
+	t.Run("RotateKey without key returns error", func(t *testing.T) {
+		// given
+		secretHelper := getSecretHelper(t)
+
+		privateKey, _, _ := secretHelper.NewKey()
+
+		// when
+		err := secretHelper.RotateKey(privateKey)
+
+		// then
+		assert.NotNil(t, err)
+	})
+
+	t.Run("PublicKey with unreadable key returns error without replacing key", func(t *testing.T) {
+		// given
+		fileHelperMock := &mocks.FileHelperMock{}
+		fileHelperMock.On("ReadFile", secretKeyFilename).Return("", os.ErrPermission)
+
+		secretHelper := &SecretHelper{FileHelper: fileHelperMock}
+
+		// when
+		_, publicKeyErr := secretHelper.PublicKey()
+		_, encryptErr := secretHelper.Encrypt("secret", nil)
+
+		// then
+		assert.ErrorContains(t, publicKeyErr, "failed to read secret key secret.key")
+		assert.ErrorContains(t, encryptErr, "failed to read secret key secret.key")
+		fileHelperMock.AssertNotCalled(t, "WriteSecretFile", mock.Anything, mock.Anything)
+	})
+
+	t.Run("RotateKey with unreadable key returns read error", func(t *testing.T) {
+		// given
+		fileHelperMock := &mocks.FileHelperMock{}
+		fileHelperMock.On("ReadFile", secretKeyFilename).Return("", os.ErrPermission)
+
+		secretHelper := &SecretHelper{FileHelper: fileHelperMock}
+		privateKey, _, _ := secretHelper.NewKey()
+
+		// when
+		err := secretHelper.RotateKey(privateKey)
+
+		// then
+		assert.ErrorContains(t, err, "failed to read secret key secret.key")
+		fileHelperMock.AssertNotCalled(t, "WriteSecretFile", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Decrypt with malformed value returns error", func(t *testing.T) {
+		// given
+		secretHelper := getSecretHelper(t)
+
+		// when
+		_, err := secretHelper.Decrypt("ENC[invalid]")
+
+		// then
+		assert.NotNil(t, err)
+	})
+}
+
+// getSecretHelper returns a secret helper storing its keys in a temporary work path
+func getSecretHelper(t *testing.T) *SecretHelper {
+	previousWorkPath := UserWorkPath
+	UserWorkPath = t.TempDir()
+
+	t.Cleanup(func() {
+		UserWorkPath = previousWorkPath
+	})
+
+	return &SecretHelper{FileHelper: &FileHelper{FileSystem: &FileSystem{}}}
+}
@@ -0,0 +1,223 @@
+package helpers
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"github.com/getflight/flight/models"
+	"io"
+	"math"
+	"os"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// SecretAllowlistFilename lists the files, or the rules for some files, the secret scan does not report
+	SecretAllowlistFilename = ".flight-secrets-allowlist"
+	// binaryScanSize is the size of the start of a file searched for null bytes to skip binary files
+	binaryScanSize = 8000
+	// minTokenEntropy is the shannon entropy in bits per character above which a token is considered random
+	minTokenEntropy = 4.0
+	minTokenLength  = 20
+	dotenvRule      = "dotenv-file"
+	entropyRule     = "high-entropy-token"
+)
+
+// secretRule matches a secret on a single line
+type secretRule struct {
+	name        string
+	description string
+	pattern     *regexp.Regexp
+}
+
+var (
+	secretRules = []secretRule{
+		{name: "private-key", description: "private key", pattern: regexp.MustCompile(`-----BEGIN ((RSA|DSA|EC|OPENSSH|PGP|ENCRYPTED) )?PRIVATE KEY( BLOCK)?-----`)},
+		{name: "aws-access-key-id", description: "aws access key id", pattern: regexp.MustCompile(`\b(AKIA|ASIA)[0-9A-Z]{16}\b`)},
+		{name: "aws-secret-access-key", description: "aws secret access key", pattern: regexp.MustCompile(`(?i)aws.{0,20}secret.{0,20}[=:]\s*['"]?[A-Za-z0-9/+]{40}\b`)},
+		{name: "github-token", description: "github token", pattern: regexp.MustCompile(`\bgh[pousr]_[A-Za-z0-9]{36,}\b`)},
+		{name: "slack-token", description: "slack token", pattern: regexp.MustCompile(`\bxox[abposr]-[A-Za-z0-9-]{10,}`)},
+		{name: "stripe-key", description: "stripe secret key", pattern: regexp.MustCompile(`\b[sr]k_live_[A-Za-z0-9]{20,}\b`)},
+	}
+
+	// secretAssignment matches the value assigned to a name suggesting a secret, which is reported when random
+	secretAssignment = regexp.MustCompile(`(?i)(secret|token|passw(or)?d|api_?key|private_?key|credential|auth)[\w.-]*['"]?\s*[:=]\s*['"]?([A-Za-z0-9+/=_.~-]+)`)
+	dotenvFile       = regexp.MustCompile(`^\.env(\..+)?$`)
+	// dotenvExamples are the .env files meant to be shared, holding placeholders instead of values
+	dotenvExamples = []string{".env.example", ".env.sample", ".env.template", ".env.dist"}
+)
+
+// ScanSecrets returns the possible secrets found in the files of an artifact zip, other than the ones allowed
+// by the allowlist file of the project. Binary files, such as the executable, are not scanned.
+func (h *FileHelper) ScanSecrets(content string) ([]models.SecretFinding, error) {
+	reader, err := zip.NewReader(bytes.NewReader([]byte(content)), int64(len(content)))
+
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	allowlist := h.getSecretAllowlist()
+	var findings []models.SecretFinding
+
+	for _, file := range reader.File {
+		if file.FileInfo().IsDir() || file.Mode()&os.ModeSymlink != 0 {
+			continue
+		}
+
+		data, err := readZipFile(file)
+
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+
+		for _, finding := range scanFile(file.Name, data) {
+			if isSecretAllowed(allowlist, finding) {
+				log.Debugf("allowed %s in %s", finding.Rule, finding.File)
+
+				continue
+			}
+
+			findings = append(findings, finding)
+		}
+	}
+
+	return findings, nil
+}
+
+// getSecretAllowlist returns the entries of the allowlist file, which is optional and uses the syntax of
+// .flightignore. An entry allows every finding of the files it matches, or only the findings of a rule when
+// followed by :rule.
+func (h *FileHelper) getSecretAllowlist() []string {
+	content, err := h.FileSystem.ReadFile(SecretAllowlistFilename)
+
+	if err != nil {
+		log.Debugf("no %s file: %v", SecretAllowlistFilename, err)
+
+		return nil
+	}
+
+	return parseIgnore(string(content))
+}
+
+func isSecretAllowed(allowlist []string, finding models.SecretFinding) bool {
+	for _, entry := range allowlist {
+		pattern, rule, scoped := strings.Cut(entry, ":")
+
+		if scoped && rule != finding.Rule {
+			continue
+		}
+
+		if isIgnored([]string{pattern}, finding.File, false) {
+			return true
+		}
+
+		// entries matching a directory allow the files under it, as .flightignore entries do
+		for directory := path.Dir(finding.File); directory != "."; directory = path.Dir(directory) {
+			if isIgnored([]string{pattern}, directory, true) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func readZipFile(file *zip.File) ([]byte, error) {
+	reader, err := file.Open()
+
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	defer reader.Close()
+
+	data, err := io.ReadAll(reader)
+
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return data, nil
+}
+
+// scanFile returns the secrets found in a file, at most one per line
+func scanFile(name string, data []byte) []models.SecretFinding {
+	var findings []models.SecretFinding
+	base := path.Base(name)
+
+	if dotenvFile.MatchString(base) && !isDotenvExample(base) {
+		findings = append(findings, models.SecretFinding{File: name, Rule: dotenvRule, Description: "environment file, use variables of the manifest instead"})
+	}
+
+	head := data
+
+	if len(head) > binaryScanSize {
+		head = head[:binaryScanSize]
+	}
+
+	if bytes.IndexByte(head, 0) >= 0 {
+		log.Debugf("not scanning binary file %s", name)
+
+		return findings
+	}
+
+	for i, line := range strings.Split(string(data), "\n") {
+		if rule, found := matchSecret(line); found {
+			findings = append(findings, models.SecretFinding{File: name, Line: i + 1, Rule: rule.name, Description: rule.description})
+		}
+	}
+
+	return findings
+}
+
+func isDotenvExample(name string) bool {
+	for _, example := range dotenvExamples {
+		if name == example {
+			return true
+		}
+	}
+
+	return false
+}
+
+func matchSecret(line string) (secretRule, bool) {
+	for _, rule := range secretRules {
+		if rule.pattern.MatchString(line) {
+			return rule, true
+		}
+	}
+
+	for _, match := range secretAssignment.FindAllStringSubmatch(line, -1) {
+		value := match[len(match)-1]
+
+		if len(value) >= minTokenLength && strings.ContainsAny(value, "0123456789") && shannonEntropy(value) >= minTokenEntropy {
+			return secretRule{name: entropyRule, description: fmt.Sprintf("random value assigned to %s", strings.ToLower(match[1]))}, true
+		}
+	}
+
+	return secretRule{}, false
+}
+
+// shannonEntropy returns the average number of bits needed to encode a character of the value
+func shannonEntropy(value string) float64 {
+	counts := map[rune]int{}
+
+	for _, character := range value {
+		counts[character]++
+	}
+
+	entropy := 0.0
+	length := float64(len([]rune(value)))
+
+	for _, count := range counts {
+		frequency := float64(count) / length
+		entropy -= frequency * math.Log2(frequency)
+	}
+
+	return entropy
+}
@@ -0,0 +1,173 @@
+package helpers
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"github.com/getflight/flight/formatters"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	signingKeyFilename = "signing.key"
+	// SigningKeyVariable holds the private signing key in ci, where the work path is not kept between builds
+	SigningKeyVariable = "FLIGHT_SIGNING_KEY"
+	signingKeyPrefix   = "ed25519:"
+	// signingKeyIDLength is the number of hexadecimal characters of the key hash identifying a key
+	signingKeyIDLength = 16
+)
+
+type SigningHelperType interface {
+	GenerateKey(force bool) (string, error)
+	Sign(digest string) (string, string, error)
+}
+
+// SigningHelper signs the digest of artifacts with an ed25519 key, so that environments can only run artifacts
+// built by the holders of trusted keys. The private key is kept in the work path or given through
+// FLIGHT_SIGNING_KEY.
+type SigningHelper struct {
+	FileHelper FileHelperType
+}
+
+// IsSigningKey returns whether a value is a public key that signatures can be verified with
+func IsSigningKey(value string) bool {
+	_, err := decodeSigningKey(value)
+
+	return err == nil
+}
+
+// SigningKeyID returns the identifier of a public key, sent with the signatures it verifies
+func SigningKeyID(publicKey string) (string, error) {
+	key, err := decodeSigningKey(publicKey)
+
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+
+	sum := sha256.Sum256(key)
+
+	return hex.EncodeToString(sum[:])[:signingKeyIDLength], nil
+}
+
+// VerifySignature verifies that the signature of the digest was made by the private key of the public key
+func VerifySignature(digest string, signature string, publicKey string) error {
+	key, err := decodeSigningKey(publicKey)
+
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(signature)
+
+	if err != nil || !ed25519.Verify(key, []byte(digest), decoded) {
+		return errors.New(fmt.Sprintf("signature of %s is not valid for key %s", digest, publicKey))
+	}
+
+	return nil
+}
+
+// GenerateKey generates the signing key pair of the user and returns the public key. An existing key is only
+// replaced when forced, as artifacts it signed can no longer be deployed once it is removed from environments.
+func (h *SigningHelper) GenerateKey(force bool) (string, error) {
+	privateKey, err := h.getPrivateKey()
+
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+
+	if privateKey != nil && !force {
+		return "", errors.New("signing key already exists, use --force to replace it")
+	}
+
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+
+	encodedKey := base64.StdEncoding.EncodeToString(privateKey.Seed())
+	formatters.MaskSecret(encodedKey)
+
+	err = h.FileHelper.WriteSecretFile(encodedKey, signingKeyFilename)
+
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+
+	return encodeSigningKey(publicKey), nil
+}
+
+// Sign returns the signature of the digest and the public key verifying it, which are empty when the user
+// has no signing key
+func (h *SigningHelper) Sign(digest string) (string, string, error) {
+	privateKey, err := h.getPrivateKey()
+
+	if err != nil {
+		return "", "", errors.WithStack(err)
+	}
+
+	if privateKey == nil {
+		return "", "", nil
+	}
+
+	signature := ed25519.Sign(privateKey, []byte(digest))
+
+	return base64.StdEncoding.EncodeToString(signature), encodeSigningKey(privateKey.Public().(ed25519.PublicKey)), nil
+}
+
+// getPrivateKey reads the private key from FLIGHT_SIGNING_KEY or the work path, which is nil when there is none.
+// A key that cannot be read returns an error, so that artifacts are not silently left unsigned.
+func (h *SigningHelper) getPrivateKey() (ed25519.PrivateKey, error) {
+	content, found := os.LookupEnv(SigningKeyVariable)
+	source := SigningKeyVariable
+
+	if !found {
+		var err error
+		content, err = h.FileHelper.ReadFile(signingKeyFilename)
+		source = signingKeyFilename
+
+		if err != nil && !os.IsNotExist(err) {
+			return nil, errors.Wrap(err, fmt.Sprintf("failed to read signing key %s", signingKeyFilename))
+		}
+
+		if err != nil || strings.TrimSpace(content) == "" {
+			log.Debug("signing key not found")
+
+			return nil, nil
+		}
+	}
+
+	formatters.MaskSecret(strings.TrimSpace(content))
+	seed, err := base64.StdEncoding.DecodeString(strings.TrimSpace(content))
+
+	if err != nil || len(seed) != ed25519.SeedSize {
+		return nil, errors.New(fmt.Sprintf("signing key %s is malformed", source))
+	}
+
+	return ed25519.NewKeyFromSeed(seed), nil
+}
+
+func encodeSigningKey(publicKey ed25519.PublicKey) string {
+	return signingKeyPrefix + base64.StdEncoding.EncodeToString(publicKey)
+}
+
+func decodeSigningKey(publicKey string) (ed25519.PublicKey, error) {
+	if !strings.HasPrefix(publicKey, signingKeyPrefix) {
+		return nil, errors.New(fmt.Sprintf("signing key %s must start with %s", publicKey, signingKeyPrefix))
+	}
+
+	key, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(publicKey, signingKeyPrefix))
+
+	if err != nil || len(key) != ed25519.PublicKeySize {
+		return nil, errors.New(fmt.Sprintf("signing key %s is malformed", publicKey))
+	}
+
+	return key, nil
+}
@@ -0,0 +1,130 @@
+package helpers
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"github.com/getflight/flight/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSigningHelper(t *testing.T) {
+	t.Run("Sign signs digest with generated key", func(t *testing.T) {
+		// given
+		signingHelper := getSigningHelper(t)
+		publicKey, err := signingHelper.GenerateKey(false)
+		assert.Nil(t, err)
+
+		// when
+		signature, signingKey, err := signingHelper.Sign("sha256:digest")
+
+		// then
+		assert.Nil(t, err)
+		assert.Equal(t, publicKey, signingKey)
+		assert.Nil(t, VerifySignature("sha256:digest", signature, publicKey))
+		assert.NotNil(t, VerifySignature("sha256:other", signature, publicKey))
+	})
+
+	t.Run("Sign without key returns empty signature", func(t *testing.T) {
+		// given
+		signingHelper := getSigningHelper(t)
+
+		// when
+		signature, publicKey, err := signingHelper.Sign("sha256:digest")
+
+		// then
+		assert.Nil(t, err)
+		assert.Equal(t, "", signature)
+		assert.Equal(t, "", publicKey)
+	})
+
+	t.Run("Sign uses key of environment variable", func(t *testing.T) {
+		// given
+		publicKey, privateKey, _ := ed25519.GenerateKey(rand.Reader)
+		t.Setenv(SigningKeyVariable, base64.StdEncoding.EncodeToString(privateKey.Seed()))
+		signingHelper := getSigningHelper(t)
+
+		// when
+		signature, signingKey, err := signingHelper.Sign("sha256:digest")
+
+		// then
+		assert.Nil(t, err)
+		assert.Equal(t, "ed25519:"+base64.StdEncoding.EncodeToString(publicKey), signingKey)
+		assert.Nil(t, VerifySignature("sha256:digest", signature, signingKey))
+	})
+
+	t.Run("GenerateKey with existing key returns error unless forced", func(t *testing.T) {
+		// given
+		signingHelper := getSigningHelper(t)
+		firstKey, _ := signingHelper.GenerateKey(false)
+
+		// when
+		_, err := signingHelper.GenerateKey(false)
+		secondKey, forcedErr := signingHelper.GenerateKey(true)
+
+		// then
+		assert.EqualError(t, err, "signing key already exists, use --force to replace it")
+		assert.Nil(t, forcedErr)
+		assert.NotEqual(t, firstKey, secondKey)
+	})
+
+	t.Run("GenerateKey writes private key only readable by user", func(t *testing.T) {
+		// given
+		signingHelper := getSigningHelper(t)
+
+		// when
+		_, err := signingHelper.GenerateKey(false)
+
+		// then
+		assert.Nil(t, err)
+		info, err := os.Stat(filepath.Join(UserWorkPath, ".flight", signingKeyFilename))
+		assert.Nil(t, err)
+		assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+	})
+
+	t.Run("Sign and GenerateKey with unreadable key return error without replacing key", func(t *testing.T) {
+		// given
+		fileHelperMock := &mocks.FileHelperMock{}
+		fileHelperMock.On("ReadFile", signingKeyFilename).Return("", os.ErrPermission)
+
+		signingHelper := &SigningHelper{FileHelper: fileHelperMock}
+
+		// when
+		_, _, signErr := signingHelper.Sign("sha256:digest")
+		_, generateErr := signingHelper.GenerateKey(false)
+
+		// then
+		assert.ErrorContains(t, signErr, "failed to read signing key signing.key")
+		assert.ErrorContains(t, generateErr, "failed to read signing key signing.key")
+		fileHelperMock.AssertNotCalled(t, "WriteSecretFile", mock.Anything, mock.Anything)
+	})
+
+	t.Run("SigningKeyID returns hash prefix of key", func(t *testing.T) {
+		// when
+		keyID, err := SigningKeyID("ed25519:" + base64.StdEncoding.EncodeToString(make([]byte, 32)))
+
+		// then
+		assert.Nil(t, err)
+		assert.Equal(t, "66687aadf862bd77", keyID)
+	})
+
+	t.Run("IsSigningKey rejects keys of other types", func(t *testing.T) {
+		assert.False(t, IsSigningKey(base64.StdEncoding.EncodeToString(make([]byte, 32))))
+		assert.False(t, IsSigningKey("ed25519:short"))
+	})
+}
+
+func getSigningHelper(t *testing.T) *SigningHelper {
+	previousWorkPath := UserWorkPath
+	UserWorkPath = t.TempDir()
+
+	t.Cleanup(func() {
+		UserWorkPath = previousWorkPath
+	})
+
+	return &SigningHelper{FileHelper: &FileHelper{FileSystem: &FileSystem{}}}
+}
@@ -0,0 +1,55 @@
+package helpers
+
+import (
+	"fmt"
+	"github.com/getflight/flight/models"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+type VariableHelperType interface {
+	Resolve(source models.ManifestVariableSource) (string, error)
+}
+
+type VariableHelper struct {
+	FileSystem FileSystemType
+}
+
+// Resolve returns the value of a variable read from an environment variable, a file or the output of a command.
+// The trailing newline of files and commands is removed.
+func (h *VariableHelper) Resolve(source models.ManifestVariableSource) (string, error) {
+	switch {
+	case source.Env != "":
+		value, found := os.LookupEnv(source.Env)
+
+		if !found {
+			return "", errors.New(fmt.Sprintf("environment variable %s is not set", source.Env))
+		}
+
+		return value, nil
+	case source.File != "":
+		content, err := h.FileSystem.ReadFile(source.File)
+
+		if err != nil {
+			return "", errors.WithStack(err)
+		}
+
+		return strings.TrimSuffix(string(content), "\n"), nil
+	case source.Command != "":
+		command := exec.Command("sh", "-c", source.Command)
+		command.Stderr = os.Stderr
+
+		output, err := command.Output()
+
+		if err != nil {
+			return "", errors.Wrap(err, fmt.Sprintf("command %q failed", source.Command))
+		}
+
+		return strings.TrimSuffix(string(output), "\n"), nil
+	}
+
+	return "", errors.New("value_from must set one of env, file or command")
+}
@@ -0,0 +1,73 @@
+package helpers
+
+import (
+	"github.com/getflight/flight/mocks"
+	"github.com/getflight/flight/models"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestVariableHelper(t *testing.T) {
+	t.Run("Resolve with env returns environment variable", func(t *testing.T) {
+		// given
+		t.Setenv("FLIGHT_TEST_SECRET", "secret")
+		variableHelper := VariableHelper{}
+
+		// when
+		value, err := variableHelper.Resolve(models.ManifestVariableSource{Env: "FLIGHT_TEST_SECRET"})
+
+		// then
+		assert.Nil(t, err)
+		assert.Equal(t, "secret", value)
+	})
+
+	t.Run("Resolve with unset env returns error", func(t *testing.T) {
+		// given
+		variableHelper := VariableHelper{}
+
+		// when
+		_, err := variableHelper.Resolve(models.ManifestVariableSource{Env: "FLIGHT_TEST_UNSET"})
+
+		// then
+		assert.NotNil(t, err)
+	})
+
+	t.Run("Resolve with file returns file content without trailing newline", func(t *testing.T) {
+		// given
+		fileSystemMock := &mocks.FileSystemMock{}
+		fileSystemMock.On("ReadFile", "secret.txt").Return([]byte("secret\n"), nil)
+
+		variableHelper := VariableHelper{FileSystem: fileSystemMock}
+
+		// when
+		value, err := variableHelper.Resolve(models.ManifestVariableSource{File: "secret.txt"})
+
+		// then
+		assert.Nil(t, err)
+		assert.Equal(t, "secret", value)
+		fileSystemMock.AssertExpectations(t)
+	})
+
+	t.Run("Resolve with command returns command output", func(t *testing.T) {
+		// given
+		variableHelper := VariableHelper{}
+
+		// when
+		value, err := variableHelper.Resolve(models.ManifestVariableSource{Command: "echo secret"})
+
+		// then
+		assert.Nil(t, err)
+		assert.Equal(t, "secret", value)
+	})
+
+	t.Run("Resolve with failing command returns error", func(t *testing.T) {
+		// given
+		variableHelper := VariableHelper{}
+
+		// when
+		_, err := variableHelper.Resolve(models.ManifestVariableSource{Command: "exit 1"})
+
+		// then
+		assert.NotNil(t, err)
+	})
+}
@@ -23,8 +23,10 @@ const (
 
 type ClientType interface {
 	GetArtifact(artifactID string) (models.Artifact, error)
+	GetArtifacts() ([]models.Artifact, error)
 	SaveArtifact(artifact models.Artifact) (models.Artifact, error)
 	UploadArtifact(artifact models.Artifact, content string) error
+	DownloadArtifact(artifact models.Artifact) (string, error)
 	SaveDeployment(deployment models.Deployment) (models.Deployment, error)
 	GetDeployment(deploymentID string) (models.Deployment, error)
 	Login(login models.Login) (models.Token, error)
@@ -32,6 +34,12 @@ type ClientType interface {
 	GetOrganisation(organisationId string) (models.Organisation, error)
 	GetEnvironment(environmentId string) (models.Environment, error)
 	GetProject(projectId string) (models.Project, error)
+	GetQueue(projectId string) (models.Queue, error)
+	RedriveQueue(projectId string) (models.QueueRedrive, error)
+	GetDomains(projectId string) ([]models.Domain, error)
+	GetDomain(domainId string) (models.Domain, error)
+	SaveDomain(projectId string, domain models.Domain) (models.Domain, error)
+	DeleteDomain(domainId string) error
 }
 
 type Client struct {
@@ -69,6 +77,38 @@ func (c *Client) GetArtifact(artifactID string) (models.Artifact, error) {
 	return *artifact, nil
 }
 
+// GetArtifacts returns the artifacts uploaded to the organisation
+func (c *Client) GetArtifacts() ([]models.Artifact, error) {
+	var artifacts []models.Artifact
+	headers, err := c.getHeaders()
+
+	if err != nil {
+		return artifacts, errors.WithStack(err)
+	}
+
+	r, err := req.Get(c.getUrl("/artifacts"), headers)
+
+	log.Debugf("%+v", r)
+
+	if err != nil {
+		return artifacts, errors.WithStack(err)
+	}
+
+	if !c.isOk(r) {
+		err = c.handleError(r)
+
+		return artifacts, errors.WithStack(err)
+	}
+
+	err = r.ToJSON(&artifacts)
+
+	if err != nil {
+		return artifacts, errors.WithStack(err)
+	}
+
+	return artifacts, nil
+}
+
 func (c *Client) SaveArtifact(artifact models.Artifact) (models.Artifact, error) {
 	headers, err := c.getHeaders()
 
@@ -117,6 +157,24 @@ func (c *Client) UploadArtifact(artifact models.Artifact, content string) error
 	return nil
 }
 
+// DownloadArtifact downloads from the storage provider. The download URL is
+// provided by the api and returned by the GetArtifact call.
+func (c *Client) DownloadArtifact(artifact models.Artifact) (string, error) {
+	r, err := req.Get(artifact.DownloadURL)
+
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+
+	if !c.isOk(r) {
+		err = c.handleError(r)
+
+		return "", errors.WithStack(err)
+	}
+
+	return r.String(), nil
+}
+
 // SaveDeployment provisions the artifact and deploys it on the serverless infrastructure.
 // The artifact must be uploaded on the cloud storage before deploying
 func (c *Client) SaveDeployment(deployment models.Deployment) (models.Deployment, error) {
@@ -336,6 +394,185 @@ func (c *Client) GetProject(projectId string) (models.Project, error) {
 	return *project, nil
 }
 
+func (c *Client) GetQueue(projectId string) (models.Queue, error) {
+	queue := &models.Queue{}
+	headers, err := c.getHeaders()
+
+	if err != nil {
+		return *queue, errors.WithStack(err)
+	}
+
+	r, err := req.Get(c.getUrl("/projects/%s/queue", projectId), headers)
+
+	log.Debugf("%+v", r)
+
+	if err != nil {
+		return *queue, errors.WithStack(err)
+	}
+
+	if !c.isOk(r) {
+		err = c.handleError(r)
+
+		return *queue, errors.WithStack(err)
+	}
+
+	err = r.ToJSON(queue)
+
+	if err != nil {
+		return *queue, errors.WithStack(err)
+	}
+
+	return *queue, nil
+}
+
+// RedriveQueue moves the messages of the dead letter queue of the project back to its queue
+func (c *Client) RedriveQueue(projectId string) (models.QueueRedrive, error) {
+	redrive := &models.QueueRedrive{}
+	headers, err := c.getHeaders()
+
+	if err != nil {
+		return *redrive, errors.WithStack(err)
+	}
+
+	r, err := req.Post(c.getUrl("/projects/%s/queue/redrive", projectId), headers)
+
+	log.Debugf("%+v", r)
+
+	if err != nil {
+		return *redrive, errors.WithStack(err)
+	}
+
+	if !c.isOk(r) {
+		err = c.handleError(r)
+
+		return *redrive, errors.WithStack(err)
+	}
+
+	err = r.ToJSON(redrive)
+
+	if err != nil {
+		return *redrive, errors.WithStack(err)
+	}
+
+	return *redrive, nil
+}
+
+func (c *Client) GetDomains(projectId string) ([]models.Domain, error) {
+	var domains []models.Domain
+	headers, err := c.getHeaders()
+
+	if err != nil {
+		return domains, errors.WithStack(err)
+	}
+
+	r, err := req.Get(c.getUrl("/projects/%s/domains", projectId), headers)
+
+	log.Debugf("%+v", r)
+
+	if err != nil {
+		return domains, errors.WithStack(err)
+	}
+
+	if !c.isOk(r) {
+		err = c.handleError(r)
+
+		return domains, errors.WithStack(err)
+	}
+
+	err = r.ToJSON(&domains)
+
+	if err != nil {
+		return domains, errors.WithStack(err)
+	}
+
+	return domains, nil
+}
+
+func (c *Client) GetDomain(domainId string) (models.Domain, error) {
+	domain := &models.Domain{}
+	headers, err := c.getHeaders()
+
+	if err != nil {
+		return *domain, errors.WithStack(err)
+	}
+
+	r, err := req.Get(c.getUrl("/domains/%s", domainId), headers)
+
+	log.Debugf("%+v", r)
+
+	if err != nil {
+		return *domain, errors.WithStack(err)
+	}
+
+	if !c.isOk(r) {
+		err = c.handleError(r)
+
+		return *domain, errors.WithStack(err)
+	}
+
+	err = r.ToJSON(domain)
+
+	if err != nil {
+		return *domain, errors.WithStack(err)
+	}
+
+	return *domain, nil
+}
+
+func (c *Client) SaveDomain(projectId string, domain models.Domain) (models.Domain, error) {
+	headers, err := c.getHeaders()
+
+	if err != nil {
+		return domain, errors.WithStack(err)
+	}
+
+	r, err := req.Post(c.getUrl("/projects/%s/domains", projectId), headers, req.BodyJSON(&domain))
+
+	log.Debugf("%+v", r)
+
+	if err != nil {
+		return domain, errors.WithStack(err)
+	}
+
+	if !c.isOk(r) {
+		err = c.handleError(r)
+
+		return domain, errors.WithStack(err)
+	}
+
+	err = r.ToJSON(&domain)
+
+	if err != nil {
+		return domain, errors.WithStack(err)
+	}
+
+	return domain, nil
+}
+
+func (c *Client) DeleteDomain(domainId string) error {
+	headers, err := c.getHeaders()
+
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	r, err := req.Delete(c.getUrl("/domains/%s", domainId), headers)
+
+	log.Debugf("%+v", r)
+
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	if !c.isOk(r) {
+		err = c.handleError(r)
+
+		return errors.WithStack(err)
+	}
+
+	return nil
+}
+
 func (c *Client) getUrl(path string, args ...any) string {
 	url := defaultApiUrl
 
@@ -2,11 +2,14 @@ package main
 
 import (
 	"github.com/getflight/flight/commands"
+	"github.com/getflight/flight/context"
 	"github.com/getflight/flight/formatters"
 	"github.com/getflight/flight/helpers"
 	"github.com/getflight/flight/http"
 	"github.com/getflight/flight/service"
 	"os"
+	"os/signal"
+	"syscall"
 
 	log "github.com/sirupsen/logrus"
 )
@@ -18,18 +21,105 @@ func main() {
 
 	fileSystem := &helpers.FileSystem{}
 	fileHelper := &helpers.FileHelper{FileSystem: fileSystem}
+	cleanupOnExit(fileHelper)
 	tokenHelper := &helpers.TokenHelper{FileHelper: fileHelper}
+	variableHelper := &helpers.VariableHelper{FileSystem: fileSystem}
+	secretHelper := &helpers.SecretHelper{FileHelper: fileHelper}
+	signingHelper := &helpers.SigningHelper{FileHelper: fileHelper}
 
 	client := &http.Client{
 		TokenHelper: tokenHelper,
 	}
 
+	configuration := &context.Configuration{}
+
 	versionService := &service.VersionService{}
 
+	validationService := &service.ValidationService{
+		Configuration: configuration,
+	}
+
+	artifactService := &service.ArtifactService{
+		Client:        client,
+		Configuration: configuration,
+		FileHelper:    fileHelper,
+		FileSystem:    fileSystem,
+		TokenHelper:   tokenHelper,
+	}
+
+	domainService := &service.DomainService{
+		Client:        client,
+		Configuration: configuration,
+		TokenHelper:   tokenHelper,
+	}
+
+	exportService := &service.ExportService{
+		Configuration:     configuration,
+		ValidationService: validationService,
+	}
+
+	importService := &service.ImportService{
+		FileSystem:        fileSystem,
+		ValidationService: validationService,
+	}
+
+	initService := &service.InitService{
+		FileSystem:        fileSystem,
+		ValidationService: validationService,
+	}
+
+	keyService := &service.KeyService{
+		SigningHelper: signingHelper,
+	}
+
+	lintService := &service.LintService{
+		Configuration: configuration,
+		FileSystem:    fileSystem,
+	}
+
+	manifestService := &service.ManifestService{
+		Configuration: configuration,
+		FileSystem:    fileSystem,
+	}
+
+	packageService := &service.PackageService{
+		Configuration:     configuration,
+		FileHelper:        fileHelper,
+		FileSystem:        fileSystem,
+		SigningHelper:     signingHelper,
+		ValidationService: validationService,
+	}
+
+	planService := &service.PlanService{
+		Client:            client,
+		Configuration:     configuration,
+		TokenHelper:       tokenHelper,
+		ValidationService: validationService,
+	}
+
+	queueService := &service.QueueService{
+		Client:        client,
+		Configuration: configuration,
+		TokenHelper:   tokenHelper,
+	}
+
+	schemaService := &service.SchemaService{}
+
+	secretService := &service.SecretService{
+		Configuration: configuration,
+		FileSystem:    fileSystem,
+		SecretHelper:  secretHelper,
+	}
+
 	deploymentService := &service.DeploymentService{
-		Client:      client,
-		FileHelper:  fileHelper,
-		TokenHelper: tokenHelper,
+		Client:            client,
+		FileHelper:        fileHelper,
+		LintService:       lintService,
+		SecretHelper:      secretHelper,
+		SigningHelper:     signingHelper,
+		TokenHelper:       tokenHelper,
+		ValidationService: validationService,
+		VariableHelper:    variableHelper,
 	}
 
 	loginService := &service.LoginService{
@@ -38,10 +128,39 @@ func main() {
 	}
 
 	root := &commands.Root{
+		ArtifactService:   artifactService,
 		DeploymentService: deploymentService,
+		DomainService:     domainService,
+		ExportService:     exportService,
+		ImportService:     importService,
+		InitService:       initService,
+		KeyService:        keyService,
+		LintService:       lintService,
 		LoginService:      loginService,
+		ManifestService:   manifestService,
+		PackageService:    packageService,
+		PlanService:       planService,
+		QueueService:      queueService,
+		SchemaService:     schemaService,
+		SecretService:     secretService,
+		ValidationService: validationService,
 		VersionService:    versionService,
 	}
 
 	root.Execute()
+	fileHelper.Cleanup()
+}
+
+// cleanupOnExit removes the build directory of the run when a command fails or flight is interrupted
+func cleanupOnExit(fileHelper *helpers.FileHelper) {
+	log.RegisterExitHandler(fileHelper.Cleanup)
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+
+	go func() {
+		<-signals
+		fileHelper.Cleanup()
+		os.Exit(1)
+	}()
 }
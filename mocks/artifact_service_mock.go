@@ -0,0 +1,45 @@
+package mocks
+
+import (
+	"github.com/stretchr/testify/mock"
+)
+
+type ArtifactServiceMock struct {
+	mock.Mock
+}
+
+func (m *ArtifactServiceMock) Download(reference string, output string) error {
+	args := m.Called(reference, output)
+
+	return args.Error(0)
+}
+
+func (m *ArtifactServiceMock) Inspect(reference string) error {
+	args := m.Called(reference)
+
+	return args.Error(0)
+}
+
+func (m *ArtifactServiceMock) List() error {
+	args := m.Called()
+
+	return args.Error(0)
+}
+
+func (m *ArtifactServiceMock) Prune(olderThan string) error {
+	args := m.Called(olderThan)
+
+	return args.Error(0)
+}
+
+func (m *ArtifactServiceMock) Sbom(artifactID string) (string, error) {
+	args := m.Called(artifactID)
+
+	return args.String(0), args.Error(1)
+}
+
+func (m *ArtifactServiceMock) Verify(artifactID string, environment string, keys []string) error {
+	args := m.Called(artifactID, environment, keys)
+
+	return args.Error(0)
+}
@@ -12,13 +12,25 @@ type ClientMock struct {
 func (m *ClientMock) GetArtifact(artifactID string) (models.Artifact, error) {
 	args := m.Called(artifactID)
 
-	return args.Get(0).(models.Artifact), args.Error(1)
+	artifact, _ := args.Get(0).(models.Artifact)
+
+	return artifact, args.Error(1)
+}
+
+func (m *ClientMock) GetArtifacts() ([]models.Artifact, error) {
+	args := m.Called()
+
+	artifacts, _ := args.Get(0).([]models.Artifact)
+
+	return artifacts, args.Error(1)
 }
 
 func (m *ClientMock) SaveArtifact(artifact models.Artifact) (models.Artifact, error) {
 	args := m.Called(artifact)
 
-	return args.Get(0).(models.Artifact), args.Error(1)
+	saved, _ := args.Get(0).(models.Artifact)
+
+	return saved, args.Error(1)
 }
 
 func (m *ClientMock) UploadArtifact(artifact models.Artifact, content string) error {
@@ -27,44 +39,110 @@ func (m *ClientMock) UploadArtifact(artifact models.Artifact, content string) er
 	return args.Error(0)
 }
 
+func (m *ClientMock) DownloadArtifact(artifact models.Artifact) (string, error) {
+	args := m.Called(artifact)
+
+	return args.String(0), args.Error(1)
+}
+
 func (m *ClientMock) SaveDeployment(deployment models.Deployment) (models.Deployment, error) {
 	args := m.Called(deployment)
 
-	return args.Get(0).(models.Deployment), args.Error(1)
+	saved, _ := args.Get(0).(models.Deployment)
+
+	return saved, args.Error(1)
 }
 
 func (m *ClientMock) GetDeployment(deploymentID string) (models.Deployment, error) {
 	args := m.Called(deploymentID)
 
-	return args.Get(0).(models.Deployment), args.Error(1)
+	deployment, _ := args.Get(0).(models.Deployment)
+
+	return deployment, args.Error(1)
 }
 
 func (m *ClientMock) Login(login models.Login) (models.Token, error) {
 	args := m.Called(login)
 
-	return args.Get(0).(models.Token), args.Error(1)
+	token, _ := args.Get(0).(models.Token)
+
+	return token, args.Error(1)
 }
 
 func (m *ClientMock) GetUser() (models.User, error) {
 	args := m.Called()
 
-	return args.Get(0).(models.User), args.Error(1)
+	user, _ := args.Get(0).(models.User)
+
+	return user, args.Error(1)
 }
 
 func (m *ClientMock) GetOrganisation(organisationId string) (models.Organisation, error) {
 	args := m.Called(organisationId)
 
-	return args.Get(0).(models.Organisation), args.Error(1)
+	organisation, _ := args.Get(0).(models.Organisation)
+
+	return organisation, args.Error(1)
 }
 
 func (m *ClientMock) GetEnvironment(environmentId string) (models.Environment, error) {
 	args := m.Called(environmentId)
 
-	return args.Get(0).(models.Environment), args.Error(1)
+	environment, _ := args.Get(0).(models.Environment)
+
+	return environment, args.Error(1)
 }
 
 func (m *ClientMock) GetProject(projectId string) (models.Project, error) {
 	args := m.Called(projectId)
 
-	return args.Get(0).(models.Project), args.Error(1)
+	project, _ := args.Get(0).(models.Project)
+
+	return project, args.Error(1)
+}
+
+func (m *ClientMock) GetQueue(projectId string) (models.Queue, error) {
+	args := m.Called(projectId)
+
+	queue, _ := args.Get(0).(models.Queue)
+
+	return queue, args.Error(1)
+}
+
+func (m *ClientMock) RedriveQueue(projectId string) (models.QueueRedrive, error) {
+	args := m.Called(projectId)
+
+	redrive, _ := args.Get(0).(models.QueueRedrive)
+
+	return redrive, args.Error(1)
+}
+
+func (m *ClientMock) GetDomains(projectId string) ([]models.Domain, error) {
+	args := m.Called(projectId)
+
+	domains, _ := args.Get(0).([]models.Domain)
+
+	return domains, args.Error(1)
+}
+
+func (m *ClientMock) GetDomain(domainId string) (models.Domain, error) {
+	args := m.Called(domainId)
+
+	domain, _ := args.Get(0).(models.Domain)
+
+	return domain, args.Error(1)
+}
+
+func (m *ClientMock) SaveDomain(projectId string, domain models.Domain) (models.Domain, error) {
+	args := m.Called(projectId, domain)
+
+	saved, _ := args.Get(0).(models.Domain)
+
+	return saved, args.Error(1)
+}
+
+func (m *ClientMock) DeleteDomain(domainId string) error {
+	args := m.Called(domainId)
+
+	return args.Error(0)
 }
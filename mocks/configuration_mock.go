@@ -3,6 +3,7 @@ package mocks
 import (
 	"github.com/getflight/flight/models"
 	"github.com/stretchr/testify/mock"
+	"gopkg.in/yaml.v3"
 )
 
 type ConfigurationMock struct {
@@ -18,5 +19,21 @@ func (m *ConfigurationMock) Init() error {
 func (m *ConfigurationMock) GetManifest() (models.Manifest, error) {
 	args := m.Called()
 
-	return args.Get(0).(models.Manifest), args.Error(1)
+	manifest, _ := args.Get(0).(models.Manifest)
+
+	return manifest, args.Error(1)
+}
+
+func (m *ConfigurationMock) GetManifestFile() string {
+	args := m.Called()
+
+	return args.String(0)
+}
+
+func (m *ConfigurationMock) GetManifestNode() (*yaml.Node, error) {
+	args := m.Called()
+
+	node, _ := args.Get(0).(*yaml.Node)
+
+	return node, args.Error(1)
 }
@@ -1,13 +1,16 @@
 package mocks
 
-import "github.com/stretchr/testify/mock"
+import (
+	"github.com/getflight/flight/models"
+	"github.com/stretchr/testify/mock"
+)
 
 type DeploymentServiceMock struct {
 	mock.Mock
 }
 
-func (m *DeploymentServiceMock) Deploy(environment string) error {
-	args := m.Called(environment)
+func (m *DeploymentServiceMock) Deploy(options models.DeployOptions) error {
+	args := m.Called(options)
 
 	return args.Error(0)
 }
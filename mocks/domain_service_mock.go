@@ -0,0 +1,31 @@
+package mocks
+
+import "github.com/stretchr/testify/mock"
+
+type DomainServiceMock struct {
+	mock.Mock
+}
+
+func (m *DomainServiceMock) Add(environment string, name string) error {
+	args := m.Called(environment, name)
+
+	return args.Error(0)
+}
+
+func (m *DomainServiceMock) List(environment string) error {
+	args := m.Called(environment)
+
+	return args.Error(0)
+}
+
+func (m *DomainServiceMock) Verify(environment string, name string) error {
+	args := m.Called(environment, name)
+
+	return args.Error(0)
+}
+
+func (m *DomainServiceMock) Remove(environment string, name string) error {
+	args := m.Called(environment, name)
+
+	return args.Error(0)
+}
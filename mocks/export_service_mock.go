@@ -0,0 +1,13 @@
+package mocks
+
+import "github.com/stretchr/testify/mock"
+
+type ExportServiceMock struct {
+	mock.Mock
+}
+
+func (m *ExportServiceMock) Cloudformation(environment string) (string, error) {
+	args := m.Called(environment)
+
+	return args.String(0), args.Error(1)
+}
@@ -3,26 +3,83 @@ package mocks
 import (
 	"github.com/getflight/flight/models"
 	"github.com/stretchr/testify/mock"
+	"time"
 )
 
 type FileHelperMock struct {
 	mock.Mock
 }
 
+func (m *FileHelperMock) CacheArtifact(artifact models.CachedArtifact, content string) error {
+	args := m.Called(artifact, content)
+
+	return args.Error(0)
+}
+
+func (m *FileHelperMock) CachedArtifact(reference string) (*models.CachedArtifact, error) {
+	args := m.Called(reference)
+
+	artifact, _ := args.Get(0).(*models.CachedArtifact)
+
+	return artifact, args.Error(1)
+}
+
+func (m *FileHelperMock) CachedArtifacts() ([]models.CachedArtifact, error) {
+	args := m.Called()
+
+	artifacts, _ := args.Get(0).([]models.CachedArtifact)
+
+	return artifacts, args.Error(1)
+}
+
+func (m *FileHelperMock) GenerateSbom(content string) (string, error) {
+	args := m.Called(content)
+
+	return args.String(0), args.Error(1)
+}
+
 func (m *FileHelperMock) Package(manifest models.Manifest) (string, error) {
 	args := m.Called(manifest)
 
 	return args.String(0), args.Error(1)
 }
 
+func (m *FileHelperMock) PruneCachedArtifacts(olderThan time.Duration) ([]models.CachedArtifact, error) {
+	args := m.Called(olderThan)
+
+	artifacts, _ := args.Get(0).([]models.CachedArtifact)
+
+	return artifacts, args.Error(1)
+}
+
+func (m *FileHelperMock) ReadCachedArtifact(digest string) (string, error) {
+	args := m.Called(digest)
+
+	return args.String(0), args.Error(1)
+}
+
 func (m *FileHelperMock) ReadFile(filename string) (string, error) {
 	args := m.Called(filename)
 
 	return args.String(0), args.Error(1)
 }
 
+func (m *FileHelperMock) ScanSecrets(content string) ([]models.SecretFinding, error) {
+	args := m.Called(content)
+
+	findings, _ := args.Get(0).([]models.SecretFinding)
+
+	return findings, args.Error(1)
+}
+
 func (m *FileHelperMock) WriteFile(value string, filename string) error {
 	args := m.Called(value, filename)
 
 	return args.Error(0)
 }
+
+func (m *FileHelperMock) WriteSecretFile(value string, filename string) error {
+	args := m.Called(value, filename)
+
+	return args.Error(0)
+}
@@ -12,10 +12,18 @@ type FileSystemMock struct {
 	mock.Mock
 }
 
+func (m *FileSystemMock) Chmod(name string, mode fs.FileMode) error {
+	args := m.Called(name, mode)
+
+	return args.Error(0)
+}
+
 func (m *FileSystemMock) Create(name string) (afero.File, error) {
 	args := m.Called(name)
 
-	return args.Get(0).(afero.File), args.Error(1)
+	file, _ := args.Get(0).(afero.File)
+
+	return file, args.Error(1)
 }
 
 func (m *FileSystemMock) MkdirAll(path string, perm fs.FileMode) error {
@@ -24,22 +32,76 @@ func (m *FileSystemMock) MkdirAll(path string, perm fs.FileMode) error {
 	return args.Error(0)
 }
 
+func (m *FileSystemMock) MkdirTemp(dir string, pattern string) (string, error) {
+	args := m.Called(dir, pattern)
+
+	return args.String(0), args.Error(1)
+}
+
 func (m *FileSystemMock) NewWriter(w io.Writer) *zip.Writer {
 	args := m.Called(w)
 
-	return args.Get(0).(*zip.Writer)
+	writer, _ := args.Get(0).(*zip.Writer)
+
+	return writer
 }
 
 func (m *FileSystemMock) Open(name string) (afero.File, error) {
 	args := m.Called(name)
 
-	return args.Get(0).(afero.File), args.Error(1)
+	file, _ := args.Get(0).(afero.File)
+
+	return file, args.Error(1)
+}
+
+func (m *FileSystemMock) OpenFile(name string, flag int, perm fs.FileMode) (afero.File, error) {
+	args := m.Called(name, flag, perm)
+
+	file, _ := args.Get(0).(afero.File)
+
+	return file, args.Error(1)
+}
+
+func (m *FileSystemMock) ReadDir(name string) ([]fs.DirEntry, error) {
+	args := m.Called(name)
+
+	entries, _ := args.Get(0).([]fs.DirEntry)
+
+	return entries, args.Error(1)
 }
 
 func (m *FileSystemMock) ReadFile(filename string) ([]byte, error) {
 	args := m.Called(filename)
 
-	return args.Get(0).([]byte), args.Error(1)
+	content, _ := args.Get(0).([]byte)
+
+	return content, args.Error(1)
+}
+
+func (m *FileSystemMock) Remove(name string) error {
+	args := m.Called(name)
+
+	return args.Error(0)
+}
+
+func (m *FileSystemMock) RemoveAll(path string) error {
+	args := m.Called(path)
+
+	return args.Error(0)
+}
+
+func (m *FileSystemMock) Rename(oldpath string, newpath string) error {
+	args := m.Called(oldpath, newpath)
+
+	return args.Error(0)
+}
+
+func (m *FileSystemMock) Stat(name string) (fs.FileInfo, error) {
+	args := m.Called(name)
+
+	info, _ := args.Get(0).(fs.FileInfo)
+
+	return info, args.Error(1)
 }
 
 func (m *FileSystemMock) UserHomeDir() (string, error) {
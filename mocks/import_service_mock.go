@@ -0,0 +1,13 @@
+package mocks
+
+import "github.com/stretchr/testify/mock"
+
+type ImportServiceMock struct {
+	mock.Mock
+}
+
+func (m *ImportServiceMock) Import(file string, function string, force bool) error {
+	args := m.Called(file, function, force)
+
+	return args.Error(0)
+}
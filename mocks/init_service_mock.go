@@ -0,0 +1,16 @@
+package mocks
+
+import (
+	"github.com/getflight/flight/models"
+	"github.com/stretchr/testify/mock"
+)
+
+type InitServiceMock struct {
+	mock.Mock
+}
+
+func (m *InitServiceMock) Init(options models.InitOptions) error {
+	args := m.Called(options)
+
+	return args.Error(0)
+}
@@ -0,0 +1,15 @@
+package mocks
+
+import (
+	"github.com/stretchr/testify/mock"
+)
+
+type KeyServiceMock struct {
+	mock.Mock
+}
+
+func (m *KeyServiceMock) Generate(force bool) (string, error) {
+	args := m.Called(force)
+
+	return args.String(0), args.Error(1)
+}
@@ -0,0 +1,22 @@
+package mocks
+
+import (
+	"github.com/getflight/flight/models"
+	"github.com/stretchr/testify/mock"
+)
+
+type LintServiceMock struct {
+	mock.Mock
+}
+
+func (m *LintServiceMock) Lint(environment string) error {
+	args := m.Called(environment)
+
+	return args.Error(0)
+}
+
+func (m *LintServiceMock) CheckManifest(manifest models.Manifest, environment string) error {
+	args := m.Called(manifest, environment)
+
+	return args.Error(0)
+}
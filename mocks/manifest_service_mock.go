@@ -0,0 +1,13 @@
+package mocks
+
+import "github.com/stretchr/testify/mock"
+
+type ManifestServiceMock struct {
+	mock.Mock
+}
+
+func (m *ManifestServiceMock) Migrate() error {
+	args := m.Called()
+
+	return args.Error(0)
+}
@@ -0,0 +1,15 @@
+package mocks
+
+import (
+	"github.com/stretchr/testify/mock"
+)
+
+type PackageServiceMock struct {
+	mock.Mock
+}
+
+func (m *PackageServiceMock) Package(environment string, output string) error {
+	args := m.Called(environment, output)
+
+	return args.Error(0)
+}
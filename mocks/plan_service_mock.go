@@ -0,0 +1,13 @@
+package mocks
+
+import "github.com/stretchr/testify/mock"
+
+type PlanServiceMock struct {
+	mock.Mock
+}
+
+func (m *PlanServiceMock) Plan(environment string) error {
+	args := m.Called(environment)
+
+	return args.Error(0)
+}
@@ -0,0 +1,19 @@
+package mocks
+
+import "github.com/stretchr/testify/mock"
+
+type QueueServiceMock struct {
+	mock.Mock
+}
+
+func (m *QueueServiceMock) Status(environment string) error {
+	args := m.Called(environment)
+
+	return args.Error(0)
+}
+
+func (m *QueueServiceMock) Redrive(environment string) error {
+	args := m.Called(environment)
+
+	return args.Error(0)
+}
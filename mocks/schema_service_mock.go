@@ -0,0 +1,13 @@
+package mocks
+
+import "github.com/stretchr/testify/mock"
+
+type SchemaServiceMock struct {
+	mock.Mock
+}
+
+func (m *SchemaServiceMock) GetSchema() (string, error) {
+	args := m.Called()
+
+	return args.String(0), args.Error(1)
+}
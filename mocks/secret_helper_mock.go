@@ -0,0 +1,45 @@
+package mocks
+
+import "github.com/stretchr/testify/mock"
+
+type SecretHelperMock struct {
+	mock.Mock
+}
+
+func (m *SecretHelperMock) PublicKey() (string, error) {
+	args := m.Called()
+
+	return args.String(0), args.Error(1)
+}
+
+func (m *SecretHelperMock) Encrypt(value string, recipients []string) (string, error) {
+	args := m.Called(value, recipients)
+
+	return args.String(0), args.Error(1)
+}
+
+func (m *SecretHelperMock) Decrypt(value string) (string, error) {
+	args := m.Called(value)
+
+	return args.String(0), args.Error(1)
+}
+
+func (m *SecretHelperMock) Recipients(value string) ([]string, error) {
+	args := m.Called(value)
+
+	recipients, _ := args.Get(0).([]string)
+
+	return recipients, args.Error(1)
+}
+
+func (m *SecretHelperMock) NewKey() (string, string, error) {
+	args := m.Called()
+
+	return args.String(0), args.String(1), args.Error(2)
+}
+
+func (m *SecretHelperMock) RotateKey(privateKey string) error {
+	args := m.Called(privateKey)
+
+	return args.Error(0)
+}
@@ -0,0 +1,31 @@
+package mocks
+
+import "github.com/stretchr/testify/mock"
+
+type SecretServiceMock struct {
+	mock.Mock
+}
+
+func (m *SecretServiceMock) Encrypt(environment string, value string) (string, error) {
+	args := m.Called(environment, value)
+
+	return args.String(0), args.Error(1)
+}
+
+func (m *SecretServiceMock) Decrypt(value string) (string, error) {
+	args := m.Called(value)
+
+	return args.String(0), args.Error(1)
+}
+
+func (m *SecretServiceMock) PublicKey() (string, error) {
+	args := m.Called()
+
+	return args.String(0), args.Error(1)
+}
+
+func (m *SecretServiceMock) RotateKey() error {
+	args := m.Called()
+
+	return args.Error(0)
+}
@@ -0,0 +1,19 @@
+package mocks
+
+import "github.com/stretchr/testify/mock"
+
+type SigningHelperMock struct {
+	mock.Mock
+}
+
+func (m *SigningHelperMock) GenerateKey(force bool) (string, error) {
+	args := m.Called(force)
+
+	return args.String(0), args.Error(1)
+}
+
+func (m *SigningHelperMock) Sign(digest string) (string, string, error) {
+	args := m.Called(digest)
+
+	return args.String(0), args.String(1), args.Error(2)
+}
@@ -0,0 +1,22 @@
+package mocks
+
+import (
+	"github.com/getflight/flight/models"
+	"github.com/stretchr/testify/mock"
+)
+
+type ValidationServiceMock struct {
+	mock.Mock
+}
+
+func (m *ValidationServiceMock) Validate(environment string) error {
+	args := m.Called(environment)
+
+	return args.Error(0)
+}
+
+func (m *ValidationServiceMock) ValidateManifest(manifest models.Manifest, environment string) error {
+	args := m.Called(manifest, environment)
+
+	return args.Error(0)
+}
@@ -0,0 +1,16 @@
+package mocks
+
+import (
+	"github.com/getflight/flight/models"
+	"github.com/stretchr/testify/mock"
+)
+
+type VariableHelperMock struct {
+	mock.Mock
+}
+
+func (m *VariableHelperMock) Resolve(source models.ManifestVariableSource) (string, error) {
+	args := m.Called(source)
+
+	return args.String(0), args.Error(1)
+}
@@ -1,8 +1,16 @@
 package models
 
+import "time"
+
 type Artifact struct {
-	ID            string `json:"id"`
-	CommitMessage string `json:"commit_message"`
-	CommitHash    string `json:"commit_hash"`
-	UploadURL     string `json:"upload_url"`
+	ID            string     `json:"id"`
+	CommitMessage string     `json:"commit_message"`
+	CommitHash    string     `json:"commit_hash"`
+	Digest        string     `json:"digest"`
+	Sbom          string     `json:"sbom"`
+	Signature     string     `json:"signature"`
+	KeyID         string     `json:"key_id"`
+	UploadURL     string     `json:"upload_url"`
+	DownloadURL   string     `json:"download_url"`
+	CreatedAt     *time.Time `json:"created_at,omitempty"`
 }
@@ -0,0 +1,11 @@
+package models
+
+import "os"
+
+// ArtifactFile is a file of an artifact zip, with its size unzipped and zipped
+type ArtifactFile struct {
+	Name           string
+	Mode           os.FileMode
+	Size           uint64
+	CompressedSize uint64
+}
@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// CachedArtifact describes an artifact zip kept in the work path under its digest. ID is empty until the
+// artifact is uploaded by flight deploy. CachedAt is updated every time the artifact is packaged or deployed.
+type CachedArtifact struct {
+	ID            string    `json:"id,omitempty"`
+	Digest        string    `json:"digest"`
+	Project       string    `json:"project,omitempty"`
+	CommitHash    string    `json:"commit_hash,omitempty"`
+	CommitMessage string    `json:"commit_message,omitempty"`
+	Signature     string    `json:"signature,omitempty"`
+	KeyID         string    `json:"key_id,omitempty"`
+	Size          int64     `json:"size"`
+	CachedAt      time.Time `json:"cached_at"`
+}
@@ -1,8 +1,11 @@
 package models
 
 type Database struct {
-	Name        string `json:"name"`
-	Driver      string `json:"driver"`
-	MinCapacity *int64 `json:"min_capacity"`
-	MaxCapacity *int64 `json:"max_capacity"`
+	Name            string `json:"name"`
+	Driver          string `json:"driver"`
+	MinCapacity     *int64 `json:"min_capacity"`
+	MaxCapacity     *int64 `json:"max_capacity"`
+	EngineVersion   string `json:"engine_version"`
+	AutoPause       *bool  `json:"auto_pause"`
+	BackupRetention *int64 `json:"backup_retention"`
 }
@@ -0,0 +1,8 @@
+package models
+
+// DeployOptions are the settings of a deployment. SecretScan is only disabled on request, to deploy files
+// the scan reports by mistake.
+type DeployOptions struct {
+	Environment string
+	SecretScan  bool
+}
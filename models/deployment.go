@@ -1,11 +1,14 @@
 package models
 
 type Deployment struct {
-	ID          string           `json:"id"`
-	State       string           `json:"state"`
-	Artifact    string           `json:"artifact"`
-	Environment string           `json:"environment"`
-	Count       string           `json:"count"`
-	Manifest    Manifest         `json:"manifest"`
-	Steps       []DeploymentStep `json:"steps"`
+	ID          string            `json:"id"`
+	State       string            `json:"state"`
+	Artifact    string            `json:"artifact"`
+	Environment string            `json:"environment"`
+	Count       string            `json:"count"`
+	Manifest    Manifest          `json:"manifest"`
+	Runtime     Runtime           `json:"runtime"`
+	Schedule    *ManifestSchedule `json:"schedule,omitempty"`
+	Queue       *ManifestQueue    `json:"queue,omitempty"`
+	Steps       []DeploymentStep  `json:"steps"`
 }
@@ -0,0 +1,11 @@
+package models
+
+import "time"
+
+type Domain struct {
+	ID        string         `json:"id"`
+	Name      string         `json:"name"`
+	Status    string         `json:"status"`
+	Records   []DomainRecord `json:"records"`
+	CreatedAt time.Time      `json:"created_at"`
+}
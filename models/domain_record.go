@@ -0,0 +1,7 @@
+package models
+
+type DomainRecord struct {
+	Name  string `json:"name"`
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
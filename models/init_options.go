@@ -0,0 +1,14 @@
+package models
+
+// InitOptions are the answers used to scaffold the manifest. Empty answers are asked for when interactive,
+// and take their detected or default value otherwise.
+type InitOptions struct {
+	Name         string
+	Template     string
+	Main         string
+	Environments []string
+	Databases    []string
+	Ignore       bool
+	Interactive  bool
+	Force        bool
+}
@@ -1,8 +1,18 @@
 package models
 
 type Manifest struct {
-	Name         string                `json:"name" validate:"required,max=256"`
-	Files        *[]string             `json:"files"`
-	Trigger      string                `json:"trigger" validate:"required,oneof=gateway queue"`
-	Environments []ManifestEnvironment `json:"environments" validate:"required,gt=0,dive"`
+	Version int              `json:"version" validate:"omitempty,min=1"`
+	Name    string           `json:"name" validate:"required,max=256"`
+	Package *ManifestPackage `json:"package"`
+	// Files is the package includes of the manifest as sent to the api, which still reads them from files since
+	// manifest version 2 moved them to package.includes
+	Files            *[]string             `json:"files"`
+	Trigger          string                `json:"trigger" validate:"required,oneof=gateway queue schedule"`
+	Schedule         *ManifestSchedule     `json:"schedule" validate:"required_if=Trigger schedule,omitempty"`
+	Queue            *ManifestQueue        `json:"queue"`
+	Memory           *int64                `json:"memory" validate:"omitempty,min=128,max=10240"`
+	Timeout          *int64                `json:"timeout" validate:"omitempty,min=1,max=900"`
+	EphemeralStorage *int64                `json:"ephemeral_storage" validate:"omitempty,min=512,max=10240"`
+	Architecture     string                `json:"architecture" validate:"omitempty,oneof=x86_64 arm64"`
+	Environments     []ManifestEnvironment `json:"environments" validate:"required,gt=0,dive"`
 }
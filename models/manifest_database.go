@@ -1,6 +1,11 @@
 package models
 
 type ManifestDatabase struct {
-	Name   string `json:"name" validate:"required,max=256"`
-	Driver string `json:"driver" validate:"required,oneof=mysql postgresql"`
+	Name            string `json:"name" validate:"required,max=256"`
+	Driver          string `json:"driver" validate:"required,oneof=mysql postgresql"`
+	MinCapacity     *int64 `json:"min_capacity" validate:"omitempty,capacity"`
+	MaxCapacity     *int64 `json:"max_capacity" validate:"omitempty,capacity"`
+	EngineVersion   string `json:"engine_version" validate:"omitempty,max=32"`
+	AutoPause       *bool  `json:"auto_pause"`
+	BackupRetention *int64 `json:"backup_retention" validate:"omitempty,min=1,max=35"`
 }
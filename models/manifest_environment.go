@@ -1,7 +1,16 @@
 package models
 
 type ManifestEnvironment struct {
-	Name      string             `json:"name" validate:"required,max=256"`
-	Databases []ManifestDatabase `json:"databases" validate:"dive"`
-	Variables []ManifestVariable `json:"variables" validate:"dive"`
+	Name             string                       `json:"name" validate:"required,max=256"`
+	Memory           *int64                       `json:"memory" validate:"omitempty,min=128,max=10240"`
+	Timeout          *int64                       `json:"timeout" validate:"omitempty,min=1,max=900"`
+	EphemeralStorage *int64                       `json:"ephemeral_storage" validate:"omitempty,min=512,max=10240"`
+	Architecture     string                       `json:"architecture" validate:"omitempty,oneof=x86_64 arm64"`
+	Schedule         *ManifestEnvironmentSchedule `json:"schedule"`
+	Package          *ManifestPackage             `json:"package"`
+	Domains          []string                     `json:"domains" validate:"dive,fqdn"`
+	Recipients       []string                     `json:"recipients" validate:"dive,recipient"`
+	SigningKeys      []string                     `json:"signing_keys" validate:"dive,signing_key"`
+	Databases        []ManifestDatabase           `json:"databases" validate:"dive"`
+	Variables        []ManifestVariable           `json:"variables" validate:"dive"`
 }
@@ -0,0 +1,7 @@
+package models
+
+type ManifestEnvironmentSchedule struct {
+	Expression string `json:"expression" validate:"omitempty,schedule"`
+	Enabled    *bool  `json:"enabled"`
+	Payload    string `json:"payload" validate:"omitempty,json"`
+}
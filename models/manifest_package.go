@@ -1,5 +1,7 @@
 package models
 
 type ManifestPackage struct {
-	Includes *[]string `json:"includes"`
+	Includes  *[]string `json:"includes" validate:"omitempty,dive,include"`
+	Symlinks  string    `json:"symlinks" validate:"omitempty,oneof=follow preserve"`
+	Bootstrap string    `json:"bootstrap"`
 }
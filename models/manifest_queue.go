@@ -0,0 +1,9 @@
+package models
+
+type ManifestQueue struct {
+	BatchSize         *int64 `json:"batch_size" validate:"omitempty,min=1,max=10000"`
+	BatchWindow       *int64 `json:"batch_window" validate:"omitempty,min=0,max=300"`
+	VisibilityTimeout *int64 `json:"visibility_timeout" validate:"omitempty,min=0,max=43200"`
+	DeadLetterQueue   *bool  `json:"dead_letter_queue"`
+	MaxReceiveCount   *int64 `json:"max_receive_count" validate:"omitempty,min=1,max=1000"`
+}
@@ -0,0 +1,7 @@
+package models
+
+type ManifestSchedule struct {
+	Expression string `json:"expression" validate:"required,schedule"`
+	Enabled    *bool  `json:"enabled"`
+	Payload    string `json:"payload" validate:"omitempty,json"`
+}
@@ -1,6 +1,8 @@
 package models
 
 type ManifestVariable struct {
-	Key   string `json:"key" validate:"required,max=256"`
-	Value string `json:"value" validate:"required,max=256"`
+	Key       string                  `json:"key" validate:"required,max=256"`
+	Value     string                  `json:"value" validate:"max=4096"`
+	Secret    bool                    `json:"secret"`
+	ValueFrom *ManifestVariableSource `json:"value_from"`
 }
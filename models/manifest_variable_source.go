@@ -0,0 +1,7 @@
+package models
+
+type ManifestVariableSource struct {
+	Env     string `json:"env"`
+	File    string `json:"file"`
+	Command string `json:"command"`
+}
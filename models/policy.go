@@ -0,0 +1,5 @@
+package models
+
+type Policy struct {
+	Rules []PolicyRule `json:"rules"`
+}
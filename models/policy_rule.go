@@ -0,0 +1,9 @@
+package models
+
+type PolicyRule struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Path        string `json:"path"`
+	Condition   string `json:"condition"`
+	Severity    string `json:"severity"`
+}
@@ -0,0 +1,10 @@
+package models
+
+type Queue struct {
+	Name                    string `json:"name"`
+	Messages                int64  `json:"messages"`
+	MessagesInFlight        int64  `json:"messages_in_flight"`
+	MessagesDelayed         int64  `json:"messages_delayed"`
+	DeadLetterQueueName     string `json:"dead_letter_queue_name"`
+	DeadLetterQueueMessages int64  `json:"dead_letter_queue_messages"`
+}
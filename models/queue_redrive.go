@@ -0,0 +1,7 @@
+package models
+
+type QueueRedrive struct {
+	ID       string `json:"id"`
+	State    string `json:"state"`
+	Messages int64  `json:"messages"`
+}
@@ -0,0 +1,8 @@
+package models
+
+type Runtime struct {
+	Memory           *int64 `json:"memory,omitempty"`
+	Timeout          *int64 `json:"timeout,omitempty"`
+	EphemeralStorage *int64 `json:"ephemeral_storage,omitempty"`
+	Architecture     string `json:"architecture,omitempty"`
+}
@@ -0,0 +1,10 @@
+package models
+
+// SecretFinding is a possible secret found in a file of the artifact. Line is 0 when the whole file is a
+// secret, such as a .env file.
+type SecretFinding struct {
+	File        string
+	Line        int
+	Rule        string
+	Description string
+}
@@ -0,0 +1,391 @@
+package service
+
+import (
+	"fmt"
+	"github.com/getflight/flight/context"
+	"github.com/getflight/flight/helpers"
+	"github.com/getflight/flight/http"
+	"github.com/getflight/flight/models"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/samber/lo"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// shortDigestLength is the number of hexadecimal characters of the digests listed
+	shortDigestLength  = 12
+	shortCommitLength  = 7
+	artifactTimeFormat = "2006-01-02 15:04"
+)
+
+type ArtifactServiceType interface {
+	Download(reference string, output string) error
+	Inspect(reference string) error
+	List() error
+	Prune(olderThan string) error
+	Sbom(artifactID string) (string, error)
+	Verify(artifactID string, environment string, keys []string) error
+}
+
+// ArtifactService inspects the artifacts uploaded by flight deploy and the artifacts kept in the local cache,
+// referenced by their id, their digest or a prefix of their digest
+type ArtifactService struct {
+	Client        http.ClientType
+	Configuration context.ConfigurationType
+	FileHelper    helpers.FileHelperType
+	FileSystem    helpers.FileSystemType
+	TokenHelper   helpers.TokenHelperType
+}
+
+// List prints the cached artifacts, then the artifacts uploaded to the organisation when logged in
+func (s *ArtifactService) List() error {
+	cachedArtifacts, err := s.FileHelper.CachedArtifacts()
+
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	log.Info("cached artifacts:")
+
+	if len(cachedArtifacts) == 0 {
+		log.Info("  none, artifacts are cached by flight package and flight deploy")
+	}
+
+	for _, artifact := range cachedArtifacts {
+		log.Infof("  %s  %s  %s  %s  %s", shortDigest(artifact.Digest), lo.Ternary[string](artifact.ID != "", artifact.ID, "not uploaded"), helpers.FormatSize(uint64(artifact.Size)), artifact.CachedAt.Local().Format(artifactTimeFormat), formatCommit(artifact.CommitHash, artifact.CommitMessage))
+	}
+
+	if !s.TokenHelper.TokenExists() {
+		log.Info("login to list the uploaded artifacts")
+
+		return nil
+	}
+
+	artifacts, err := s.Client.GetArtifacts()
+
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	log.Info("uploaded artifacts:")
+
+	if len(artifacts) == 0 {
+		log.Info("  none")
+	}
+
+	for _, artifact := range artifacts {
+		cached := lo.ContainsBy[models.CachedArtifact](cachedArtifacts, func(cachedArtifact models.CachedArtifact) bool {
+			return cachedArtifact.Digest == artifact.Digest
+		})
+
+		log.Infof("  %s  %s  %s  %s%s", artifact.ID, shortDigest(artifact.Digest), formatTime(artifact.CreatedAt), formatCommit(artifact.CommitHash, artifact.CommitMessage), lo.Ternary[string](cached, "  (cached)", ""))
+	}
+
+	return nil
+}
+
+// Inspect prints the metadata of an artifact and the files of its zip, downloading the artifact when it is not
+// cached
+func (s *ArtifactService) Inspect(reference string) error {
+	artifact, content, err := s.getArtifactContent(reference)
+
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	files, err := helpers.ArtifactFiles(content)
+
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	log.Infof("artifact: %s", lo.Ternary[string](artifact.ID != "", artifact.ID, "not uploaded"))
+	log.Infof("digest: %s", artifact.Digest)
+	log.Infof("size: %s", helpers.FormatSize(uint64(len(content))))
+	log.Infof("commit: %s", formatCommit(artifact.CommitHash, artifact.CommitMessage))
+	log.Infof("signed by: %s", lo.Ternary[string](artifact.KeyID != "", artifact.KeyID, "not signed"))
+	log.Infof("files:")
+
+	for _, file := range files {
+		log.Infof("  %s  %10s  %s", file.Mode, helpers.FormatSize(file.Size), file.Name)
+	}
+
+	log.Infof("%d file(s), %s unzipped", len(files), helpers.FormatSize(lo.SumBy[models.ArtifactFile, uint64](files, func(file models.ArtifactFile) uint64 {
+		return file.Size
+	})))
+
+	return nil
+}
+
+// Download writes the zip of an artifact to the output file, from the cache or from the storage provider
+func (s *ArtifactService) Download(reference string, output string) error {
+	artifact, content, err := s.getArtifactContent(reference)
+
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	err = s.FileSystem.WriteFile(output, []byte(content), 0644)
+
+	if err != nil {
+		return errors.Wrap(err, fmt.Sprintf("failed to write %s", output))
+	}
+
+	log.Infof("%s written, %d bytes, %s", output, len(content), artifact.Digest)
+
+	return nil
+}
+
+// Prune removes the artifacts cached before the given age, in days such as 30d or as a go duration such as 12h
+func (s *ArtifactService) Prune(olderThan string) error {
+	age, err := parseAge(olderThan)
+
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	pruned, err := s.FileHelper.PruneCachedArtifacts(age)
+
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	for _, artifact := range pruned {
+		log.Debugf("removed cached artifact %s", artifact.Digest)
+	}
+
+	log.Infof("removed %d cached artifact(s), %s freed", len(pruned), helpers.FormatSize(uint64(lo.SumBy[models.CachedArtifact, int64](pruned, func(artifact models.CachedArtifact) int64 {
+		return artifact.Size
+	}))))
+
+	return nil
+}
+
+// Sbom returns the software bill of materials generated when the artifact was packaged
+func (s *ArtifactService) Sbom(artifactID string) (string, error) {
+	if !s.TokenHelper.TokenExists() {
+		return "", errors.New("token not found, login to inspect artifacts")
+	}
+
+	artifact, err := s.Client.GetArtifact(artifactID)
+
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+
+	if artifact.Sbom == "" {
+		return "", errors.New(fmt.Sprintf("artifact %s has no sbom, it was deployed by a version of flight not generating them", artifactID))
+	}
+
+	return artifact.Sbom, nil
+}
+
+// Verify verifies that the zip of the artifact is signed by a trusted key, the trusted keys being the given keys
+// and the signing keys of the environment when given
+func (s *ArtifactService) Verify(artifactID string, environment string, keys []string) error {
+	if !s.TokenHelper.TokenExists() {
+		return errors.New("token not found, login to inspect artifacts")
+	}
+
+	trustedKeys, err := s.getTrustedKeys(environment, keys)
+
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	artifact, err := s.Client.GetArtifact(artifactID)
+
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	if artifact.Signature == "" {
+		return errors.New(fmt.Sprintf("artifact %s is not signed", artifactID))
+	}
+
+	publicKey, found := lo.Find[string](trustedKeys, func(trustedKey string) bool {
+		keyID, err := helpers.SigningKeyID(trustedKey)
+
+		return err == nil && keyID == artifact.KeyID
+	})
+
+	if !found {
+		return errors.New(fmt.Sprintf("artifact %s is signed by key %s, which is not trusted", artifactID, artifact.KeyID))
+	}
+
+	// The digest is computed from the zip, as the digest reported with the artifact would also verify a swapped zip
+	_, content, err := s.getArtifactContent(artifactID)
+
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	digest := helpers.ArtifactDigest(content)
+
+	if digest != artifact.Digest {
+		return errors.New(fmt.Sprintf("artifact %s has digest %s instead of the signed digest %s", artifactID, digest, artifact.Digest))
+	}
+
+	err = helpers.VerifySignature(digest, artifact.Signature, publicKey)
+
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	log.Infof("artifact %s with digest %s is signed by trusted key %s", artifactID, digest, artifact.KeyID)
+
+	return nil
+}
+
+// getArtifactContent returns an artifact and its zip from the cache, or downloads it and keeps it in the cache
+func (s *ArtifactService) getArtifactContent(reference string) (models.CachedArtifact, string, error) {
+	cached, err := s.FileHelper.CachedArtifact(reference)
+
+	if err != nil {
+		return models.CachedArtifact{}, "", errors.WithStack(err)
+	}
+
+	if cached != nil {
+		content, err := s.FileHelper.ReadCachedArtifact(cached.Digest)
+
+		return *cached, content, errors.WithStack(err)
+	}
+
+	if !s.TokenHelper.TokenExists() {
+		return models.CachedArtifact{}, "", errors.New(fmt.Sprintf("artifact %s is not cached, login to download it", reference))
+	}
+
+	artifact, err := s.Client.GetArtifact(reference)
+
+	if err != nil {
+		return models.CachedArtifact{}, "", errors.WithStack(err)
+	}
+
+	if artifact.DownloadURL == "" {
+		return models.CachedArtifact{}, "", errors.New(fmt.Sprintf("artifact %s cannot be downloaded", reference))
+	}
+
+	log.Infof("downloading artifact %s", artifact.ID)
+	content, err := s.Client.DownloadArtifact(artifact)
+
+	if err != nil {
+		return models.CachedArtifact{}, "", errors.WithStack(err)
+	}
+
+	digest := helpers.ArtifactDigest(content)
+
+	if artifact.Digest != "" && artifact.Digest != digest {
+		return models.CachedArtifact{}, "", errors.New(fmt.Sprintf("artifact %s has digest %s once downloaded instead of %s", artifact.ID, digest, artifact.Digest))
+	}
+
+	cachedArtifact := models.CachedArtifact{ID: artifact.ID, CommitHash: artifact.CommitHash, CommitMessage: artifact.CommitMessage, Signature: artifact.Signature, KeyID: artifact.KeyID}
+	err = s.FileHelper.CacheArtifact(cachedArtifact, content)
+
+	if err != nil {
+		log.Warnf("artifact not cached: %v", err)
+	}
+
+	cachedArtifact.Digest = digest
+	cachedArtifact.Size = int64(len(content))
+
+	return cachedArtifact, content, nil
+}
+
+func (s *ArtifactService) getTrustedKeys(environment string, keys []string) ([]string, error) {
+	for _, key := range keys {
+		if !helpers.IsSigningKey(key) {
+			return nil, errors.New(fmt.Sprintf("key %s is not a public key printed by flight keys generate", key))
+		}
+	}
+
+	if environment == "" {
+		if len(keys) == 0 {
+			return nil, errors.New("no trusted key, give the environment whose signing_keys are trusted or the trusted keys")
+		}
+
+		return keys, nil
+	}
+
+	err := s.Configuration.Init()
+
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	manifest, err := s.Configuration.GetManifest()
+
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	manifestEnvironment, found := lo.Find[models.ManifestEnvironment](manifest.Environments, func(manifestEnvironment models.ManifestEnvironment) bool {
+		return manifestEnvironment.Name == environment
+	})
+
+	if !found {
+		return nil, errors.New(fmt.Sprintf("environment %s not found in manifest", environment))
+	}
+
+	trustedKeys := append(append([]string{}, keys...), manifestEnvironment.SigningKeys...)
+
+	if len(trustedKeys) == 0 {
+		return nil, errors.New(fmt.Sprintf("environment %s has no signing_keys", environment))
+	}
+
+	return trustedKeys, nil
+}
+
+// parseAge parses an age in days such as 30d, which go durations do not support, or a go duration such as 12h
+func parseAge(value string) (time.Duration, error) {
+	var age time.Duration
+	var err error
+
+	if strings.HasSuffix(value, "d") {
+		var days int
+		days, err = strconv.Atoi(strings.TrimSuffix(value, "d"))
+		age = time.Duration(days) * 24 * time.Hour
+	} else {
+		age, err = time.ParseDuration(value)
+	}
+
+	if err != nil || age < 0 {
+		return 0, errors.New(fmt.Sprintf("%s is not an age, give days such as 30d or a duration such as 12h", value))
+	}
+
+	return age, nil
+}
+
+func shortDigest(digest string) string {
+	_, sum, _ := strings.Cut(digest, ":")
+
+	if len(sum) > shortDigestLength {
+		return sum[:shortDigestLength]
+	}
+
+	return lo.Ternary[string](sum != "", sum, "no digest")
+}
+
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return "no date"
+	}
+
+	return t.Local().Format(artifactTimeFormat)
+}
+
+func formatCommit(hash string, message string) string {
+	if hash == "" {
+		return "no commit"
+	}
+
+	if len(hash) > shortCommitLength {
+		hash = hash[:shortCommitLength]
+	}
+
+	return strings.TrimSpace(hash + " " + message)
+}
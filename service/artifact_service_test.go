@@ -0,0 +1,480 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"github.com/getflight/flight/helpers"
+	"github.com/getflight/flight/mocks"
+	"github.com/getflight/flight/models"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"io/fs"
+	"testing"
+	"time"
+)
+
+func TestArtifactService(t *testing.T) {
+	t.Run("Sbom returns sbom of artifact", func(t *testing.T) {
+		// given
+		clientMock := &mocks.ClientMock{}
+		clientMock.On("GetArtifact", "1").Return(models.Artifact{ID: "1", Sbom: `{"bomFormat":"CycloneDX"}`}, nil)
+
+		tokenHelperMock := &mocks.TokenHelperMock{}
+		tokenHelperMock.On("TokenExists").Return(true)
+
+		artifactService := ArtifactService{
+			Client:      clientMock,
+			TokenHelper: tokenHelperMock,
+		}
+
+		// when
+		sbom, err := artifactService.Sbom("1")
+
+		// then
+		assert.Nil(t, err)
+		assert.Equal(t, `{"bomFormat":"CycloneDX"}`, sbom)
+	})
+
+	t.Run("Sbom without sbom returns error", func(t *testing.T) {
+		// given
+		clientMock := &mocks.ClientMock{}
+		clientMock.On("GetArtifact", "1").Return(models.Artifact{ID: "1"}, nil)
+
+		tokenHelperMock := &mocks.TokenHelperMock{}
+		tokenHelperMock.On("TokenExists").Return(true)
+
+		artifactService := ArtifactService{
+			Client:      clientMock,
+			TokenHelper: tokenHelperMock,
+		}
+
+		// when
+		_, err := artifactService.Sbom("1")
+
+		// then
+		assert.EqualError(t, err, "artifact 1 has no sbom, it was deployed by a version of flight not generating them")
+	})
+
+	t.Run("Sbom with client error returns error", func(t *testing.T) {
+		// given
+		clientMock := &mocks.ClientMock{}
+		clientMock.On("GetArtifact", "1").Return(models.Artifact{}, errors.New("not found"))
+
+		tokenHelperMock := &mocks.TokenHelperMock{}
+		tokenHelperMock.On("TokenExists").Return(true)
+
+		artifactService := ArtifactService{
+			Client:      clientMock,
+			TokenHelper: tokenHelperMock,
+		}
+
+		// when
+		_, err := artifactService.Sbom("1")
+
+		// then
+		assert.NotNil(t, err)
+	})
+
+	t.Run("Sbom without token returns error", func(t *testing.T) {
+		// given
+		tokenHelperMock := &mocks.TokenHelperMock{}
+		tokenHelperMock.On("TokenExists").Return(false)
+
+		artifactService := ArtifactService{
+			TokenHelper: tokenHelperMock,
+		}
+
+		// when
+		_, err := artifactService.Sbom("1")
+
+		// then
+		assert.EqualError(t, err, "token not found, login to inspect artifacts")
+	})
+}
+
+func TestArtifactServiceVerify(t *testing.T) {
+	publicKey, privateKey, _ := ed25519.GenerateKey(rand.Reader)
+	encodedKey := "ed25519:" + base64.StdEncoding.EncodeToString(publicKey)
+	keyID, _ := helpers.SigningKeyID(encodedKey)
+	content := getArtifactZip(t, "executable")
+	signature := base64.StdEncoding.EncodeToString(ed25519.Sign(privateKey, []byte(helpers.ArtifactDigest(content))))
+
+	t.Run("Verify with signature of environment key returns nil", func(t *testing.T) {
+		// given
+		manifest := getManifest()
+		manifest.Environments[0].SigningKeys = []string{encodedKey}
+
+		configurationMock := &mocks.ConfigurationMock{}
+		configurationMock.On("Init").Return(nil)
+		configurationMock.On("GetManifest").Return(manifest, nil)
+
+		artifactService := ArtifactService{
+			Client:        getSignedArtifactClientMock(content, signature, keyID),
+			Configuration: configurationMock,
+			FileHelper:    getUncachedFileHelperMock(),
+			TokenHelper:   getTokenHelperMock(),
+		}
+
+		// when
+		err := artifactService.Verify("1", "dev", nil)
+
+		// then
+		assert.Nil(t, err)
+	})
+
+	t.Run("Verify with invalid signature returns error", func(t *testing.T) {
+		// given
+		artifactService := ArtifactService{
+			Client:      getSignedArtifactClientMock(content, base64.StdEncoding.EncodeToString(make([]byte, 64)), keyID),
+			FileHelper:  getUncachedFileHelperMock(),
+			TokenHelper: getTokenHelperMock(),
+		}
+
+		// when
+		err := artifactService.Verify("1", "", []string{encodedKey})
+
+		// then
+		assert.ErrorContains(t, err, "signature of "+helpers.ArtifactDigest(content)+" is not valid")
+	})
+
+	t.Run("Verify with cached zip not matching signed digest returns error", func(t *testing.T) {
+		// given
+		swapped := getArtifactZip(t, "swapped")
+		fileHelperMock := &mocks.FileHelperMock{}
+		fileHelperMock.On("CachedArtifact", "1").Return(&models.CachedArtifact{ID: "1", Digest: helpers.ArtifactDigest(swapped)}, nil)
+		fileHelperMock.On("ReadCachedArtifact", helpers.ArtifactDigest(swapped)).Return(swapped, nil)
+
+		artifactService := ArtifactService{
+			Client:      getSignedArtifactClientMock(content, signature, keyID),
+			FileHelper:  fileHelperMock,
+			TokenHelper: getTokenHelperMock(),
+		}
+
+		// when
+		err := artifactService.Verify("1", "", []string{encodedKey})
+
+		// then
+		assert.EqualError(t, err, "artifact 1 has digest "+helpers.ArtifactDigest(swapped)+" instead of the signed digest "+helpers.ArtifactDigest(content))
+	})
+
+	t.Run("Verify with unknown key returns error", func(t *testing.T) {
+		// given
+		artifactService := ArtifactService{
+			Client:      getSignedArtifactClientMock(content, signature, "0123456789abcdef"),
+			TokenHelper: getTokenHelperMock(),
+		}
+
+		// when
+		err := artifactService.Verify("1", "", []string{encodedKey})
+
+		// then
+		assert.EqualError(t, err, "artifact 1 is signed by key 0123456789abcdef, which is not trusted")
+	})
+
+	t.Run("Verify with unsigned artifact returns error", func(t *testing.T) {
+		// given
+		artifactService := ArtifactService{
+			Client:      getSignedArtifactClientMock(content, "", ""),
+			TokenHelper: getTokenHelperMock(),
+		}
+
+		// when
+		err := artifactService.Verify("1", "", []string{encodedKey})
+
+		// then
+		assert.EqualError(t, err, "artifact 1 is not signed")
+	})
+
+	t.Run("Verify without trusted keys returns error", func(t *testing.T) {
+		// given
+		artifactService := ArtifactService{
+			TokenHelper: getTokenHelperMock(),
+		}
+
+		// when
+		err := artifactService.Verify("1", "", nil)
+
+		// then
+		assert.EqualError(t, err, "no trusted key, give the environment whose signing_keys are trusted or the trusted keys")
+	})
+}
+
+func getSignedArtifactClientMock(content string, signature string, keyID string) *mocks.ClientMock {
+	artifact := models.Artifact{ID: "1", Digest: helpers.ArtifactDigest(content), Signature: signature, KeyID: keyID, DownloadURL: "url"}
+
+	clientMock := &mocks.ClientMock{}
+	clientMock.On("GetArtifact", "1").Return(artifact, nil)
+	clientMock.On("DownloadArtifact", artifact).Return(content, nil)
+
+	return clientMock
+}
+
+func getUncachedFileHelperMock() *mocks.FileHelperMock {
+	fileHelperMock := &mocks.FileHelperMock{}
+	fileHelperMock.On("CachedArtifact", mock.Anything).Return((*models.CachedArtifact)(nil), nil)
+	fileHelperMock.On("CacheArtifact", mock.Anything, mock.Anything).Return(nil)
+
+	return fileHelperMock
+}
+
+func getTokenHelperMock() *mocks.TokenHelperMock {
+	tokenHelperMock := &mocks.TokenHelperMock{}
+	tokenHelperMock.On("TokenExists").Return(true)
+
+	return tokenHelperMock
+}
+
+func TestArtifactServiceCache(t *testing.T) {
+	content := getArtifactZip(t, "executable")
+	digest := helpers.ArtifactDigest(content)
+	cached := &models.CachedArtifact{ID: "1", Digest: digest, CommitHash: "0123456789abcdef", CommitMessage: "fix handler", Size: int64(len(content))}
+
+	t.Run("List lists cached and uploaded artifacts", func(t *testing.T) {
+		// given
+		clientMock := &mocks.ClientMock{}
+		clientMock.On("GetArtifacts").Return([]models.Artifact{{ID: "1", Digest: digest}, {ID: "2"}}, nil)
+
+		fileHelperMock := &mocks.FileHelperMock{}
+		fileHelperMock.On("CachedArtifacts").Return([]models.CachedArtifact{*cached}, nil)
+
+		artifactService := ArtifactService{
+			Client:      clientMock,
+			FileHelper:  fileHelperMock,
+			TokenHelper: getTokenHelperMock(),
+		}
+
+		// when
+		err := artifactService.List()
+
+		// then
+		assert.Nil(t, err)
+		clientMock.AssertExpectations(t)
+		fileHelperMock.AssertExpectations(t)
+	})
+
+	t.Run("List without token lists cached artifacts", func(t *testing.T) {
+		// given
+		clientMock := &mocks.ClientMock{}
+
+		fileHelperMock := &mocks.FileHelperMock{}
+		fileHelperMock.On("CachedArtifacts").Return([]models.CachedArtifact{}, nil)
+
+		tokenHelperMock := &mocks.TokenHelperMock{}
+		tokenHelperMock.On("TokenExists").Return(false)
+
+		artifactService := ArtifactService{
+			Client:      clientMock,
+			FileHelper:  fileHelperMock,
+			TokenHelper: tokenHelperMock,
+		}
+
+		// when
+		err := artifactService.List()
+
+		// then
+		assert.Nil(t, err)
+		clientMock.AssertNotCalled(t, "GetArtifacts")
+	})
+
+	t.Run("Inspect with cached artifact lists files of zip", func(t *testing.T) {
+		// given
+		fileHelperMock := &mocks.FileHelperMock{}
+		fileHelperMock.On("CachedArtifact", "1").Return(cached, nil)
+		fileHelperMock.On("ReadCachedArtifact", digest).Return(content, nil)
+
+		artifactService := ArtifactService{
+			FileHelper: fileHelperMock,
+		}
+
+		// when
+		err := artifactService.Inspect("1")
+
+		// then
+		assert.Nil(t, err)
+		fileHelperMock.AssertExpectations(t)
+	})
+
+	t.Run("Download with cached artifact writes zip to output", func(t *testing.T) {
+		// given
+		fileHelperMock := &mocks.FileHelperMock{}
+		fileHelperMock.On("CachedArtifact", "1").Return(cached, nil)
+		fileHelperMock.On("ReadCachedArtifact", digest).Return(content, nil)
+
+		fileSystemMock := &mocks.FileSystemMock{}
+		fileSystemMock.On("WriteFile", "artifact.zip", []byte(content), fs.FileMode(0644)).Return(nil)
+
+		artifactService := ArtifactService{
+			FileHelper: fileHelperMock,
+			FileSystem: fileSystemMock,
+		}
+
+		// when
+		err := artifactService.Download("1", "artifact.zip")
+
+		// then
+		assert.Nil(t, err)
+		fileSystemMock.AssertExpectations(t)
+	})
+
+	t.Run("Download without cached artifact downloads and caches artifact", func(t *testing.T) {
+		// given
+		artifact := models.Artifact{ID: "1", Digest: digest, DownloadURL: "url", KeyID: "key"}
+
+		clientMock := &mocks.ClientMock{}
+		clientMock.On("GetArtifact", "1").Return(artifact, nil)
+		clientMock.On("DownloadArtifact", artifact).Return(content, nil)
+
+		fileHelperMock := &mocks.FileHelperMock{}
+		fileHelperMock.On("CachedArtifact", "1").Return((*models.CachedArtifact)(nil), nil)
+		fileHelperMock.On("CacheArtifact", models.CachedArtifact{ID: "1", KeyID: "key"}, content).Return(nil)
+
+		fileSystemMock := &mocks.FileSystemMock{}
+		fileSystemMock.On("WriteFile", "main.zip", []byte(content), fs.FileMode(0644)).Return(nil)
+
+		artifactService := ArtifactService{
+			Client:      clientMock,
+			FileHelper:  fileHelperMock,
+			FileSystem:  fileSystemMock,
+			TokenHelper: getTokenHelperMock(),
+		}
+
+		// when
+		err := artifactService.Download("1", "main.zip")
+
+		// then
+		assert.Nil(t, err)
+		fileHelperMock.AssertExpectations(t)
+		fileSystemMock.AssertExpectations(t)
+	})
+
+	t.Run("Download with zip not matching digest returns error", func(t *testing.T) {
+		// given
+		artifact := models.Artifact{ID: "1", Digest: "sha256:other", DownloadURL: "url"}
+
+		clientMock := &mocks.ClientMock{}
+		clientMock.On("GetArtifact", "1").Return(artifact, nil)
+		clientMock.On("DownloadArtifact", artifact).Return(content, nil)
+
+		fileHelperMock := &mocks.FileHelperMock{}
+		fileHelperMock.On("CachedArtifact", "1").Return((*models.CachedArtifact)(nil), nil)
+
+		artifactService := ArtifactService{
+			Client:      clientMock,
+			FileHelper:  fileHelperMock,
+			FileSystem:  &mocks.FileSystemMock{},
+			TokenHelper: getTokenHelperMock(),
+		}
+
+		// when
+		err := artifactService.Download("1", "main.zip")
+
+		// then
+		assert.EqualError(t, err, "artifact 1 has digest "+digest+" once downloaded instead of sha256:other")
+		fileHelperMock.AssertNotCalled(t, "CacheArtifact", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Download without cached artifact and token returns error", func(t *testing.T) {
+		// given
+		fileHelperMock := &mocks.FileHelperMock{}
+		fileHelperMock.On("CachedArtifact", "1").Return((*models.CachedArtifact)(nil), nil)
+
+		tokenHelperMock := &mocks.TokenHelperMock{}
+		tokenHelperMock.On("TokenExists").Return(false)
+
+		artifactService := ArtifactService{
+			FileHelper:  fileHelperMock,
+			TokenHelper: tokenHelperMock,
+		}
+
+		// when
+		err := artifactService.Download("1", "main.zip")
+
+		// then
+		assert.EqualError(t, err, "artifact 1 is not cached, login to download it")
+	})
+
+	t.Run("Prune removes artifacts cached before age in days", func(t *testing.T) {
+		// given
+		fileHelperMock := &mocks.FileHelperMock{}
+		fileHelperMock.On("PruneCachedArtifacts", 30*24*time.Hour).Return([]models.CachedArtifact{*cached}, nil)
+
+		artifactService := ArtifactService{
+			FileHelper: fileHelperMock,
+		}
+
+		// when
+		err := artifactService.Prune("30d")
+
+		// then
+		assert.Nil(t, err)
+		fileHelperMock.AssertExpectations(t)
+	})
+
+	t.Run("Prune with invalid age returns error", func(t *testing.T) {
+		// given
+		artifactService := ArtifactService{
+			FileHelper: &mocks.FileHelperMock{},
+		}
+
+		// when
+		err := artifactService.Prune("a month")
+
+		// then
+		assert.EqualError(t, err, "a month is not an age, give days such as 30d or a duration such as 12h")
+	})
+
+	t.Run("parseAge parses days and durations", func(t *testing.T) {
+		for value, expected := range map[string]time.Duration{"30d": 30 * 24 * time.Hour, "0d": 0, "12h": 12 * time.Hour, "90m": 90 * time.Minute} {
+			age, err := parseAge(value)
+
+			assert.Nil(t, err)
+			assert.Equal(t, expected, age)
+		}
+
+		for _, value := range []string{"", "d", "-1d", "30", "1w"} {
+			_, err := parseAge(value)
+
+			assert.NotNil(t, err, value)
+		}
+	})
+
+	t.Run("formatTime formats time and returns placeholder without time", func(t *testing.T) {
+		// given
+		createdAt := time.Date(2026, 10, 19, 12, 30, 0, 0, time.Local)
+
+		// when
+		formatted := formatTime(&createdAt)
+		missing := formatTime(nil)
+
+		// then
+		assert.Equal(t, "2026-10-19 12:30", formatted)
+		assert.Equal(t, "no date", missing)
+	})
+
+	t.Run("Artifact without creation time is saved without created_at", func(t *testing.T) {
+		// when
+		body, err := json.Marshal(models.Artifact{Digest: "sha256:digest"})
+
+		// then
+		assert.Nil(t, err)
+		assert.NotContains(t, string(body), "created_at")
+	})
+}
+
+func getArtifactZip(t *testing.T, executable string) string {
+	buffer := &bytes.Buffer{}
+	writer := zip.NewWriter(buffer)
+
+	file, err := writer.Create("main")
+	assert.Nil(t, err)
+	_, err = file.Write([]byte(executable))
+	assert.Nil(t, err)
+	assert.Nil(t, writer.Close())
+
+	return buffer.String()
+}
@@ -3,13 +3,13 @@ package service
 import (
 	"fmt"
 	"github.com/getflight/flight/context"
+	"github.com/getflight/flight/formatters"
 	"github.com/getflight/flight/helpers"
 	"github.com/getflight/flight/http"
 	"github.com/getflight/flight/models"
+	"sort"
 	"strings"
 
-	"github.com/go-playground/validator/v10"
-
 	"github.com/samber/lo"
 
 	"github.com/pkg/errors"
@@ -21,6 +21,10 @@ import (
 
 const (
 	artifactPollRetries = 20
+	// maxVariablesSize is the limit of lambda on the total size of the keys and values of environment variables
+	maxVariablesSize = 4096
+	// variablesSizeReport is the number of largest variables reported when the limit is exceeded
+	variablesSizeReport = 5
 	stateCompleted      = "completed"
 	stateExecuting      = "executing"
 	stateFailed         = "failed"
@@ -32,19 +36,25 @@ var (
 )
 
 type DeploymentServiceType interface {
-	Deploy(environment string) error
+	Deploy(options models.DeployOptions) error
 }
 
 type DeploymentService struct {
-	Client        http.ClientType
-	Configuration context.ConfigurationType
-	FileHelper    helpers.FileHelperType
-	TokenHelper   helpers.TokenHelperType
-	start         time.Time
+	Client            http.ClientType
+	Configuration     context.ConfigurationType
+	FileHelper        helpers.FileHelperType
+	LintService       LintServiceType
+	SecretHelper      helpers.SecretHelperType
+	SigningHelper     helpers.SigningHelperType
+	TokenHelper       helpers.TokenHelperType
+	ValidationService ValidationServiceType
+	VariableHelper    helpers.VariableHelperType
+	start             time.Time
 }
 
-func (s *DeploymentService) Deploy(environment string) error {
+func (s *DeploymentService) Deploy(options models.DeployOptions) error {
 	s.start = time.Now()
+	environment := options.Environment
 
 	err := s.verifyToken()
 
@@ -60,42 +70,74 @@ func (s *DeploymentService) Deploy(environment string) error {
 		return errors.WithStack(err)
 	}
 
-	manifest, err := s.parseManifest()
+	manifest, err := s.parseManifest(environment)
 
 	if err != nil {
 		return errors.WithStack(err)
 	}
 
-	err = s.validateManifest(manifest, environment)
+	manifest, err = s.resolveVariables(manifest, environment)
 
 	if err != nil {
 		return errors.WithStack(err)
 	}
 
-	content, err := s.packageArtifact(manifest)
+	err = s.checkVariablesSize(manifest, environment)
 
 	if err != nil {
 		return errors.WithStack(err)
 	}
 
-	artifact, err := s.saveArtifact()
+	content, err := s.packageArtifact(resolveManifest(manifest, environment))
 
 	if err != nil {
 		return errors.WithStack(err)
 	}
 
-	artifact, err = s.pollArtifactForUpload(artifact)
+	err = s.scanArtifact(content, options.SecretScan)
 
 	if err != nil {
 		return errors.WithStack(err)
 	}
 
-	err = s.uploadArtifact(artifact, content)
+	sbom, err := s.generateSbom(content)
 
 	if err != nil {
 		return errors.WithStack(err)
 	}
 
+	digest := helpers.ArtifactDigest(content)
+	signature, keyID, err := signArtifact(s.SigningHelper, manifest, environment, digest)
+
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	commitHash, commitMessage := helpers.GitCommit()
+	artifact, reused := s.reuseArtifact(digest, keyID)
+
+	if !reused {
+		artifact, err = s.saveArtifact(models.Artifact{Digest: digest, Sbom: sbom, Signature: signature, KeyID: keyID, CommitHash: commitHash, CommitMessage: commitMessage})
+
+		if err != nil {
+			return errors.WithStack(err)
+		}
+
+		artifact, err = s.pollArtifactForUpload(artifact)
+
+		if err != nil {
+			return errors.WithStack(err)
+		}
+
+		err = s.uploadArtifact(artifact, content)
+
+		if err != nil {
+			return errors.WithStack(err)
+		}
+	}
+
+	s.cacheArtifact(models.CachedArtifact{ID: artifact.ID, Project: manifest.Name, CommitHash: commitHash, CommitMessage: commitMessage, Signature: signature, KeyID: keyID}, content)
+
 	deployment, err := s.saveDeployment(artifact, environment, manifest)
 
 	if err != nil {
@@ -136,7 +178,9 @@ func (s *DeploymentService) initializeConfiguration() error {
 	return nil
 }
 
-func (s *DeploymentService) parseManifest() (models.Manifest, error) {
+// parseManifest reads and validates the manifest, then decrypts the encrypted values of the environment. The
+// manifest is validated before decryption, as secret values must be encrypted in the manifest.
+func (s *DeploymentService) parseManifest(environment string) (models.Manifest, error) {
 	log.Info("parsing manifest")
 	manifest, err := s.Configuration.GetManifest()
 
@@ -144,23 +188,138 @@ func (s *DeploymentService) parseManifest() (models.Manifest, error) {
 		return manifest, errors.WithStack(err)
 	}
 
+	err = s.validateManifest(manifest, environment)
+
+	if err != nil {
+		return manifest, errors.WithStack(err)
+	}
+
+	err = s.LintService.CheckManifest(manifest, environment)
+
+	if err != nil {
+		return manifest, errors.WithStack(err)
+	}
+
+	manifest, err = s.decryptVariables(manifest, environment)
+
+	if err != nil {
+		return manifest, errors.WithStack(err)
+	}
+
+	return manifest, nil
+}
+
+func (s *DeploymentService) decryptVariables(manifest models.Manifest, environment string) (models.Manifest, error) {
+	environments := make([]models.ManifestEnvironment, len(manifest.Environments))
+	copy(environments, manifest.Environments)
+	manifest.Environments = environments
+
+	for i, manifestEnvironment := range manifest.Environments {
+		if manifestEnvironment.Name != environment {
+			continue
+		}
+
+		variables := make([]models.ManifestVariable, len(manifestEnvironment.Variables))
+
+		for j, variable := range manifestEnvironment.Variables {
+			if helpers.IsEncrypted(variable.Value) {
+				value, err := s.SecretHelper.Decrypt(variable.Value)
+
+				if err != nil {
+					return manifest, errors.Wrap(err, fmt.Sprintf("failed to decrypt variable %s", variable.Key))
+				}
+
+				formatters.MaskSecret(value)
+				variable.Value = value
+			}
+
+			variables[j] = variable
+		}
+
+		manifest.Environments[i].Variables = variables
+	}
+
 	return manifest, nil
 }
 
 func (s *DeploymentService) validateManifest(manifest models.Manifest, environment string) error {
-	err := validator.New().Struct(manifest)
+	err := s.ValidationService.ValidateManifest(manifest, environment)
 
 	if err != nil {
 		return errors.WithStack(err)
 	}
 
-	if !lo.ContainsBy[models.ManifestEnvironment](manifest.Environments, func(manifestEnvironment models.ManifestEnvironment) bool {
+	return nil
+}
+
+// resolveVariables reads the values of the variables of the environment set through value_from. Secret values
+// are masked from the logs before they are sent, and the sources are not sent.
+func (s *DeploymentService) resolveVariables(manifest models.Manifest, environment string) (models.Manifest, error) {
+	environments := make([]models.ManifestEnvironment, len(manifest.Environments))
+	copy(environments, manifest.Environments)
+	manifest.Environments = environments
+
+	for i, manifestEnvironment := range manifest.Environments {
+		if manifestEnvironment.Name != environment {
+			continue
+		}
+
+		variables := make([]models.ManifestVariable, len(manifestEnvironment.Variables))
+
+		for j, variable := range manifestEnvironment.Variables {
+			if variable.ValueFrom != nil {
+				log.Debugf("resolving variable %s", variable.Key)
+				value, err := s.VariableHelper.Resolve(*variable.ValueFrom)
+
+				if err != nil {
+					return manifest, errors.Wrap(err, fmt.Sprintf("failed to resolve variable %s", variable.Key))
+				}
+
+				variable.Value = value
+				variable.ValueFrom = nil
+			}
+
+			if variable.Secret {
+				formatters.MaskSecret(variable.Value)
+			}
+
+			variables[j] = variable
+		}
+
+		manifest.Environments[i].Variables = variables
+	}
+
+	return manifest, nil
+}
+
+// checkVariablesSize verifies that the resolved variables of the environment fit in the environment of the
+// function, and reports the largest variables when they do not
+func (s *DeploymentService) checkVariablesSize(manifest models.Manifest, environment string) error {
+	manifestEnvironment, _ := lo.Find[models.ManifestEnvironment](manifest.Environments, func(manifestEnvironment models.ManifestEnvironment) bool {
 		return manifestEnvironment.Name == environment
-	}) {
-		return errors.New(fmt.Sprintf("environment %s not found in flight.yml, please configure environment before deploying", environment))
+	})
+
+	variables := make([]models.ManifestVariable, len(manifestEnvironment.Variables))
+	copy(variables, manifestEnvironment.Variables)
+
+	size := lo.SumBy[models.ManifestVariable, int](variables, variableSize)
+	log.Debugf("environment variables use %d of %d bytes", size, maxVariablesSize)
+
+	if size <= maxVariablesSize {
+		return nil
 	}
 
-	return nil
+	sort.SliceStable(variables, func(i, j int) bool {
+		return variableSize(variables[i]) > variableSize(variables[j])
+	})
+
+	log.Errorf("environment variables use %d bytes, over the limit of %d bytes, largest variables:", size, maxVariablesSize)
+
+	for _, variable := range lo.Slice[models.ManifestVariable](variables, 0, variablesSizeReport) {
+		log.Errorf("  %s: %d bytes", variable.Key, variableSize(variable))
+	}
+
+	return errors.New(fmt.Sprintf("environment variables of %s exceed %d bytes, move large values to files packaged with the project", environment, maxVariablesSize))
 }
 
 func (s *DeploymentService) packageArtifact(manifest models.Manifest) (string, error) {
@@ -174,9 +333,127 @@ func (s *DeploymentService) packageArtifact(manifest models.Manifest) (string, e
 	return content, nil
 }
 
-func (s *DeploymentService) saveArtifact() (models.Artifact, error) {
-	log.Info("saving artifact")
-	artifact := models.Artifact{}
+// scanArtifact blocks the deployment when files of the artifact look like secrets, which are reported without
+// their value
+func (s *DeploymentService) scanArtifact(content string, secretScan bool) error {
+	if !secretScan {
+		log.Warn("secret scan disabled, the artifact is uploaded without checking it for secrets")
+
+		return nil
+	}
+
+	log.Info("scanning artifact for secrets")
+	findings, err := s.FileHelper.ScanSecrets(content)
+
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	if len(findings) == 0 {
+		return nil
+	}
+
+	for _, finding := range findings {
+		if finding.Line > 0 {
+			log.Errorf("%s:%d: %s (%s)", finding.File, finding.Line, finding.Description, finding.Rule)
+		} else {
+			log.Errorf("%s: %s (%s)", finding.File, finding.Description, finding.Rule)
+		}
+	}
+
+	return errors.New(fmt.Sprintf("found %d possible secret(s) in the artifact, remove them from the package includes, allow them in %s or deploy with --no-secret-scan", len(findings), helpers.SecretAllowlistFilename))
+}
+
+// generateSbom lists the go modules and files of the artifact, the sbom being saved with the artifact
+func (s *DeploymentService) generateSbom(content string) (string, error) {
+	log.Info("generating sbom")
+	sbom, err := s.FileHelper.GenerateSbom(content)
+
+	if err != nil {
+		return sbom, errors.WithStack(err)
+	}
+
+	return sbom, nil
+}
+
+// signArtifact signs the digest of the artifact with the key of the user, when packaging or deploying it.
+// Environments with signing keys refuse artifacts that are unsigned or signed by a key they do not list.
+func signArtifact(signingHelper helpers.SigningHelperType, manifest models.Manifest, environment string, digest string) (string, string, error) {
+	signature, publicKey, err := signingHelper.Sign(digest)
+
+	if err != nil {
+		return "", "", errors.WithStack(err)
+	}
+
+	manifestEnvironment, _ := lo.Find[models.ManifestEnvironment](manifest.Environments, func(manifestEnvironment models.ManifestEnvironment) bool {
+		return manifestEnvironment.Name == environment
+	})
+
+	if publicKey == "" {
+		if len(manifestEnvironment.SigningKeys) > 0 {
+			return "", "", errors.New(fmt.Sprintf("environment %s only deploys signed artifacts, generate a key with flight keys generate or set %s", environment, helpers.SigningKeyVariable))
+		}
+
+		log.Debug("no signing key, the artifact is not signed")
+
+		return "", "", nil
+	}
+
+	keyID, err := helpers.SigningKeyID(publicKey)
+
+	if err != nil {
+		return "", "", errors.WithStack(err)
+	}
+
+	if len(manifestEnvironment.SigningKeys) > 0 && !lo.Contains[string](manifestEnvironment.SigningKeys, publicKey) {
+		return "", "", errors.New(fmt.Sprintf("artifact is signed by key %s, which is not in the signing_keys of environment %s", keyID, environment))
+	}
+
+	log.Infof("artifact signed by key %s", keyID)
+
+	return signature, keyID, nil
+}
+
+// reuseArtifact returns the artifact uploaded by a previous deployment of the same zip, found in the artifact
+// cache, so that re-deploys and rollbacks skip the upload. The artifact must still exist and be signed by the
+// same key, any other case uploading the artifact again.
+func (s *DeploymentService) reuseArtifact(digest string, keyID string) (models.Artifact, bool) {
+	cached, err := s.FileHelper.CachedArtifact(digest)
+
+	if err != nil {
+		log.Debugf("artifact cache not readable: %v", err)
+
+		return models.Artifact{}, false
+	}
+
+	if cached == nil || cached.ID == "" || cached.KeyID != keyID {
+		return models.Artifact{}, false
+	}
+
+	artifact, err := s.Client.GetArtifact(cached.ID)
+
+	if err != nil || artifact.Digest != digest {
+		log.Debugf("cached artifact %s is no longer uploaded: %v", cached.ID, err)
+
+		return models.Artifact{}, false
+	}
+
+	log.Infof("reusing artifact %s uploaded with digest %s", artifact.ID, digest)
+
+	return artifact, true
+}
+
+// cacheArtifact keeps the deployed zip in the artifact cache, the deployment going on when it cannot be cached
+func (s *DeploymentService) cacheArtifact(artifact models.CachedArtifact, content string) {
+	err := s.FileHelper.CacheArtifact(artifact, content)
+
+	if err != nil {
+		log.Warnf("artifact not cached: %v", err)
+	}
+}
+
+func (s *DeploymentService) saveArtifact(artifact models.Artifact) (models.Artifact, error) {
+	log.Infof("saving artifact %s", artifact.Digest)
 	artifact, err := s.Client.SaveArtifact(artifact)
 
 	if err != nil {
@@ -221,10 +498,24 @@ func (s *DeploymentService) uploadArtifact(artifact models.Artifact, content str
 
 func (s *DeploymentService) saveDeployment(artifact models.Artifact, environment string, manifest models.Manifest) (models.Deployment, error) {
 	log.Info("initiating deployment")
+	environmentManifest := resolveManifest(manifest, environment)
 	deployment := models.Deployment{
 		Artifact:    artifact.ID,
 		Environment: environment,
 		Manifest:    manifest,
+		Runtime:     newRuntime(environmentManifest),
+	}
+
+	if manifest.Package != nil {
+		deployment.Manifest.Files = manifest.Package.Includes
+	}
+
+	if environmentManifest.Trigger == triggerSchedule {
+		deployment.Schedule = environmentManifest.Schedule
+	}
+
+	if environmentManifest.Trigger == triggerQueue {
+		deployment.Queue = environmentManifest.Queue
 	}
 	deployment, err := s.Client.SaveDeployment(deployment)
 
@@ -295,39 +586,5 @@ func (s *DeploymentService) printDeploymentSteps(deployment models.Deployment) (
 }
 
 func (s *DeploymentService) getProject(manifestEnvironment string, manifestProject string) (*models.Project, error) {
-	organisationId, err := s.TokenHelper.GetOrganisation()
-
-	if err != nil {
-		return nil, errors.WithStack(err)
-	}
-
-	organisation, err := s.Client.GetOrganisation(organisationId)
-
-	if err != nil {
-		return nil, errors.WithStack(err)
-	}
-
-	environment, found := lo.Find[models.Environment](organisation.Environments, func(environment models.Environment) bool {
-		return strings.EqualFold(environment.Name, manifestEnvironment)
-	})
-
-	if !found {
-		return nil, errors.WithStack(errors.New(fmt.Sprintf("environment not found in organisation: %s", manifestEnvironment)))
-	}
-
-	environment, err = s.Client.GetEnvironment(environment.ID)
-
-	if err != nil {
-		return nil, errors.WithStack(err)
-	}
-
-	project, found := lo.Find[models.Project](environment.Projects, func(project models.Project) bool {
-		return strings.EqualFold(project.Name, manifestProject)
-	})
-
-	if !found {
-		return nil, errors.WithStack(errors.New(fmt.Sprintf("project not found in organisation: %s", manifestProject)))
-	}
-
-	return &project, nil
+	return findProject(s.Client, s.TokenHelper, manifestEnvironment, manifestProject)
 }
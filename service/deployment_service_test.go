@@ -1,12 +1,16 @@
 package service
 
 import (
+	"bytes"
+	"encoding/base64"
+	"github.com/getflight/flight/helpers"
 	"github.com/getflight/flight/mocks"
 	"github.com/getflight/flight/models"
-	"github.com/go-playground/validator/v10"
 	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus/hooks/test"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"strings"
 	"testing"
 )
 
@@ -48,18 +52,74 @@ func TestDeploymentService(t *testing.T) {
 	t.Run("parseManifest with manifest returns manifest", func(t *testing.T) {
 		// given
 		configuration := &mocks.ConfigurationMock{}
-		configuration.On("GetManifest").Return(models.Manifest{}, nil)
+		configuration.On("GetManifest").Return(getManifest(), nil)
 
 		deploymentService := DeploymentService{
-			Configuration: configuration,
+			Configuration:     configuration,
+			LintService:       getLintServiceMock(),
+			ValidationService: getValidationServiceMock(),
+		}
+
+		// when
+		manifest, err := deploymentService.parseManifest("dev")
+
+		// then
+		assert.Equal(t, getManifest(), manifest)
+		assert.Nil(t, err)
+	})
+
+	t.Run("parseManifest with encrypted variable returns decrypted manifest", func(t *testing.T) {
+		// given
+		manifest := getManifest()
+		manifest.Environments[0].Variables[0].Value = "ENC[value]"
+
+		configuration := &mocks.ConfigurationMock{}
+		configuration.On("GetManifest").Return(manifest, nil)
+
+		secretHelperMock := &mocks.SecretHelperMock{}
+		secretHelperMock.On("Decrypt", "ENC[value]").Return("value1", nil)
+
+		deploymentService := DeploymentService{
+			Configuration:     configuration,
+			LintService:       getLintServiceMock(),
+			SecretHelper:      secretHelperMock,
+			ValidationService: getValidationServiceMock(),
 		}
 
 		// when
-		manifest, err := deploymentService.parseManifest()
+		decryptedManifest, err := deploymentService.parseManifest("dev")
 
 		// then
-		assert.NotNil(t, manifest)
 		assert.Nil(t, err)
+		assert.Equal(t, getManifest(), decryptedManifest)
+		assert.Equal(t, "ENC[value]", manifest.Environments[0].Variables[0].Value)
+		secretHelperMock.AssertExpectations(t)
+	})
+
+	t.Run("parseManifest with policy violation returns error", func(t *testing.T) {
+		// given
+		configuration := &mocks.ConfigurationMock{}
+		configuration.On("GetManifest").Return(getManifest(), nil)
+
+		lintServiceMock := &mocks.LintServiceMock{}
+		lintServiceMock.On("CheckManifest", getManifest(), "dev").Return(errors.New("1 policy violation(s) at error severity"))
+
+		secretHelperMock := &mocks.SecretHelperMock{}
+
+		deploymentService := DeploymentService{
+			Configuration:     configuration,
+			LintService:       lintServiceMock,
+			SecretHelper:      secretHelperMock,
+			ValidationService: getValidationServiceMock(),
+		}
+
+		// when
+		_, err := deploymentService.parseManifest("dev")
+
+		// then
+		assert.EqualError(t, err, "1 policy violation(s) at error severity")
+		lintServiceMock.AssertExpectations(t)
+		secretHelperMock.AssertNotCalled(t, "Decrypt", mock.Anything)
 	})
 
 	t.Run("parseManifest with error returns error", func(t *testing.T) {
@@ -69,10 +129,11 @@ func TestDeploymentService(t *testing.T) {
 
 		deploymentService := DeploymentService{
 			Configuration: configuration,
+			LintService:   getLintServiceMock(),
 		}
 
 		// when
-		_, err := deploymentService.parseManifest()
+		_, err := deploymentService.parseManifest("dev")
 
 		// then
 		assert.NotNil(t, err)
@@ -82,7 +143,9 @@ func TestDeploymentService(t *testing.T) {
 		// given
 		manifest := getManifest()
 
-		deploymentService := DeploymentService{}
+		deploymentService := DeploymentService{
+			ValidationService: &ValidationService{},
+		}
 
 		// when
 		err := deploymentService.validateManifest(manifest, "dev")
@@ -95,7 +158,9 @@ func TestDeploymentService(t *testing.T) {
 		// given
 		manifest := getManifest()
 
-		deploymentService := DeploymentService{}
+		deploymentService := DeploymentService{
+			ValidationService: &ValidationService{},
+		}
 
 		// when
 		err := deploymentService.validateManifest(manifest, "test")
@@ -109,16 +174,19 @@ func TestDeploymentService(t *testing.T) {
 		manifest := getManifest()
 		manifest.Name = ""
 
-		deploymentService := DeploymentService{}
+		deploymentService := DeploymentService{
+			ValidationService: &ValidationService{},
+		}
 
 		// when
 		err := deploymentService.validateManifest(manifest, "dev")
 
 		// then
 		assert.NotNil(t, err)
-		validationErrors := errors.Unwrap(err).(validator.ValidationErrors)
+		var validationErrors ValidationErrors
+		assert.True(t, errors.As(err, &validationErrors))
 		assert.Equal(t, 1, len(validationErrors))
-		assert.Equal(t, "Name", validationErrors[0].Field())
+		assert.Equal(t, "name", validationErrors[0].Path)
 	})
 
 	t.Run("validateManifest with invalid trigger returns error", func(t *testing.T) {
@@ -126,16 +194,19 @@ func TestDeploymentService(t *testing.T) {
 		manifest := getManifest()
 		manifest.Trigger = "test"
 
-		deploymentService := DeploymentService{}
+		deploymentService := DeploymentService{
+			ValidationService: &ValidationService{},
+		}
 
 		// when
 		err := deploymentService.validateManifest(manifest, "dev")
 
 		// then
 		assert.NotNil(t, err)
-		validationErrors := errors.Unwrap(err).(validator.ValidationErrors)
+		var validationErrors ValidationErrors
+		assert.True(t, errors.As(err, &validationErrors))
 		assert.Equal(t, 1, len(validationErrors))
-		assert.Equal(t, "Trigger", validationErrors[0].Field())
+		assert.Equal(t, "trigger", validationErrors[0].Path)
 	})
 
 	t.Run("validateManifest with empty environments returns error", func(t *testing.T) {
@@ -143,16 +214,20 @@ func TestDeploymentService(t *testing.T) {
 		manifest := getManifest()
 		manifest.Environments = []models.ManifestEnvironment{}
 
-		deploymentService := DeploymentService{}
+		deploymentService := DeploymentService{
+			ValidationService: &ValidationService{},
+		}
 
 		// when
 		err := deploymentService.validateManifest(manifest, "dev")
 
 		// then
 		assert.NotNil(t, err)
-		validationErrors := errors.Unwrap(err).(validator.ValidationErrors)
-		assert.Equal(t, 1, len(validationErrors))
-		assert.Equal(t, "Environments", validationErrors[0].Field())
+		var validationErrors ValidationErrors
+		assert.True(t, errors.As(err, &validationErrors))
+		assert.Equal(t, 2, len(validationErrors))
+		assert.Equal(t, "environments", validationErrors[0].Path)
+		assert.Equal(t, "environments", validationErrors[1].Path)
 	})
 
 	t.Run("validateManifest with invalid environment name returns error", func(t *testing.T) {
@@ -160,16 +235,20 @@ func TestDeploymentService(t *testing.T) {
 		manifest := getManifest()
 		manifest.Environments[0].Name = ""
 
-		deploymentService := DeploymentService{}
+		deploymentService := DeploymentService{
+			ValidationService: &ValidationService{},
+		}
 
 		// when
 		err := deploymentService.validateManifest(manifest, "dev")
 
 		// then
 		assert.NotNil(t, err)
-		validationErrors := errors.Unwrap(err).(validator.ValidationErrors)
-		assert.Equal(t, 1, len(validationErrors))
-		assert.Equal(t, "Name", validationErrors[0].Field())
+		var validationErrors ValidationErrors
+		assert.True(t, errors.As(err, &validationErrors))
+		assert.Equal(t, 2, len(validationErrors))
+		assert.Equal(t, "environments[0].name", validationErrors[0].Path)
+		assert.Equal(t, "environments", validationErrors[1].Path)
 	})
 
 	t.Run("validateManifest with invalid database name returns error", func(t *testing.T) {
@@ -177,16 +256,19 @@ func TestDeploymentService(t *testing.T) {
 		manifest := getManifest()
 		manifest.Environments[0].Databases[0].Name = ""
 
-		deploymentService := DeploymentService{}
+		deploymentService := DeploymentService{
+			ValidationService: &ValidationService{},
+		}
 
 		// when
 		err := deploymentService.validateManifest(manifest, "dev")
 
 		// then
 		assert.NotNil(t, err)
-		validationErrors := errors.Unwrap(err).(validator.ValidationErrors)
+		var validationErrors ValidationErrors
+		assert.True(t, errors.As(err, &validationErrors))
 		assert.Equal(t, 1, len(validationErrors))
-		assert.Equal(t, "Name", validationErrors[0].Field())
+		assert.Equal(t, "environments[0].databases[0].name", validationErrors[0].Path)
 	})
 
 	t.Run("validateManifest with invalid database driver returns error", func(t *testing.T) {
@@ -194,16 +276,19 @@ func TestDeploymentService(t *testing.T) {
 		manifest := getManifest()
 		manifest.Environments[0].Databases[0].Driver = "test"
 
-		deploymentService := DeploymentService{}
+		deploymentService := DeploymentService{
+			ValidationService: &ValidationService{},
+		}
 
 		// when
 		err := deploymentService.validateManifest(manifest, "dev")
 
 		// then
 		assert.NotNil(t, err)
-		validationErrors := errors.Unwrap(err).(validator.ValidationErrors)
+		var validationErrors ValidationErrors
+		assert.True(t, errors.As(err, &validationErrors))
 		assert.Equal(t, 1, len(validationErrors))
-		assert.Equal(t, "Driver", validationErrors[0].Field())
+		assert.Equal(t, "environments[0].databases[0].driver", validationErrors[0].Path)
 	})
 
 	t.Run("validateManifest with invalid variable key returns error", func(t *testing.T) {
@@ -211,16 +296,19 @@ func TestDeploymentService(t *testing.T) {
 		manifest := getManifest()
 		manifest.Environments[0].Variables[0].Key = ""
 
-		deploymentService := DeploymentService{}
+		deploymentService := DeploymentService{
+			ValidationService: &ValidationService{},
+		}
 
 		// when
 		err := deploymentService.validateManifest(manifest, "dev")
 
 		// then
 		assert.NotNil(t, err)
-		validationErrors := errors.Unwrap(err).(validator.ValidationErrors)
+		var validationErrors ValidationErrors
+		assert.True(t, errors.As(err, &validationErrors))
 		assert.Equal(t, 1, len(validationErrors))
-		assert.Equal(t, "Key", validationErrors[0].Field())
+		assert.Equal(t, "environments[0].variables[0].key", validationErrors[0].Path)
 	})
 
 	t.Run("validateManifest with invalid variable value returns error", func(t *testing.T) {
@@ -228,16 +316,102 @@ func TestDeploymentService(t *testing.T) {
 		manifest := getManifest()
 		manifest.Environments[0].Variables[0].Value = ""
 
-		deploymentService := DeploymentService{}
+		deploymentService := DeploymentService{
+			ValidationService: &ValidationService{},
+		}
 
 		// when
 		err := deploymentService.validateManifest(manifest, "dev")
 
 		// then
 		assert.NotNil(t, err)
-		validationErrors := errors.Unwrap(err).(validator.ValidationErrors)
+		var validationErrors ValidationErrors
+		assert.True(t, errors.As(err, &validationErrors))
 		assert.Equal(t, 1, len(validationErrors))
-		assert.Equal(t, "Value", validationErrors[0].Field())
+		assert.Equal(t, "environments[0].variables[0].value", validationErrors[0].Path)
+	})
+
+	t.Run("resolveVariables resolves value sources of environment", func(t *testing.T) {
+		// given
+		source := models.ManifestVariableSource{Env: "DB_PASSWORD"}
+
+		manifest := getManifest()
+		manifest.Environments[0].Variables = []models.ManifestVariable{{Key: "DB_PASSWORD", Secret: true, ValueFrom: &source}}
+
+		variableHelperMock := &mocks.VariableHelperMock{}
+		variableHelperMock.On("Resolve", source).Return("secret", nil)
+
+		deploymentService := DeploymentService{
+			VariableHelper: variableHelperMock,
+		}
+
+		// when
+		resolvedManifest, err := deploymentService.resolveVariables(manifest, "dev")
+
+		// then
+		assert.Nil(t, err)
+		assert.Equal(t, models.ManifestVariable{Key: "DB_PASSWORD", Secret: true, Value: "secret"}, resolvedManifest.Environments[0].Variables[0])
+		assert.Equal(t, &source, manifest.Environments[0].Variables[0].ValueFrom)
+		variableHelperMock.AssertExpectations(t)
+	})
+
+	t.Run("resolveVariables with error returns error", func(t *testing.T) {
+		// given
+		source := models.ManifestVariableSource{Env: "DB_PASSWORD"}
+
+		manifest := getManifest()
+		manifest.Environments[0].Variables = []models.ManifestVariable{{Key: "DB_PASSWORD", Secret: true, ValueFrom: &source}}
+
+		variableHelperMock := &mocks.VariableHelperMock{}
+		variableHelperMock.On("Resolve", source).Return("", errors.New("test error"))
+
+		deploymentService := DeploymentService{
+			VariableHelper: variableHelperMock,
+		}
+
+		// when
+		_, err := deploymentService.resolveVariables(manifest, "dev")
+
+		// then
+		assert.NotNil(t, err)
+		variableHelperMock.AssertExpectations(t)
+	})
+
+	t.Run("checkVariablesSize with variables within limit returns nil", func(t *testing.T) {
+		// given
+		manifest := getManifest()
+		manifest.Environments[0].Variables = append(manifest.Environments[0].Variables, models.ManifestVariable{
+			Key:   "CERTIFICATE",
+			Value: "-----BEGIN CERTIFICATE-----\n" + strings.Repeat("a", 3000) + "\n-----END CERTIFICATE-----",
+		})
+
+		deploymentService := DeploymentService{}
+
+		// when
+		err := deploymentService.checkVariablesSize(manifest, "dev")
+
+		// then
+		assert.Nil(t, err)
+	})
+
+	t.Run("checkVariablesSize with variables over limit returns error and reports largest variables", func(t *testing.T) {
+		// given
+		hook := test.NewGlobal()
+
+		manifest := getManifest()
+		manifest.Environments[0].Variables = append(manifest.Environments[0].Variables,
+			models.ManifestVariable{Key: "CONFIG", Value: strings.Repeat("a", 3000)},
+			models.ManifestVariable{Key: "CERTIFICATE", Value: strings.Repeat("a", 2000)},
+		)
+
+		deploymentService := DeploymentService{}
+
+		// when
+		err := deploymentService.checkVariablesSize(manifest, "dev")
+
+		// then
+		assert.NotNil(t, err)
+		assert.Contains(t, getLogs(hook), "environment variables use 5037 bytes, over the limit of 4096 bytes, largest variables:\n  CONFIG: 3006 bytes\n  CERTIFICATE: 2011 bytes\n  var1: 10 bytes")
 	})
 
 	t.Run("packageArtifact with success returns file content", func(t *testing.T) {
@@ -283,14 +457,14 @@ func TestDeploymentService(t *testing.T) {
 	t.Run("saveArtifact with success returns artifact and nil", func(t *testing.T) {
 		// given
 		clientMock := &mocks.ClientMock{}
-		clientMock.On("SaveArtifact", mock.Anything).Return(models.Artifact{}, nil)
+		clientMock.On("SaveArtifact", models.Artifact{Digest: "sha256:digest", Sbom: "{}"}).Return(models.Artifact{}, nil)
 
 		deploymentService := DeploymentService{
 			Client: clientMock,
 		}
 
 		// when
-		artifact, err := deploymentService.saveArtifact()
+		artifact, err := deploymentService.saveArtifact(models.Artifact{Digest: "sha256:digest", Sbom: "{}"})
 
 		// then
 		assert.Nil(t, err)
@@ -308,7 +482,7 @@ func TestDeploymentService(t *testing.T) {
 		}
 
 		// when
-		_, err := deploymentService.saveArtifact()
+		_, err := deploymentService.saveArtifact(models.Artifact{Digest: "sha256:digest", Sbom: "{}"})
 
 		// then
 		assert.NotNil(t, err)
@@ -364,6 +538,118 @@ func TestDeploymentService(t *testing.T) {
 		clientMock.AssertExpectations(t)
 	})
 
+	t.Run("scanArtifact without findings returns nil", func(t *testing.T) {
+		// given
+		fileHelperMock := &mocks.FileHelperMock{}
+		fileHelperMock.On("ScanSecrets", "content").Return([]models.SecretFinding{}, nil)
+
+		deploymentService := DeploymentService{
+			FileHelper: fileHelperMock,
+		}
+
+		// when
+		err := deploymentService.scanArtifact("content", true)
+
+		// then
+		assert.Nil(t, err)
+		fileHelperMock.AssertExpectations(t)
+	})
+
+	t.Run("scanArtifact with findings returns error", func(t *testing.T) {
+		// given
+		fileHelperMock := &mocks.FileHelperMock{}
+		fileHelperMock.On("ScanSecrets", "content").Return([]models.SecretFinding{
+			{File: ".env", Rule: "dotenv-file", Description: "environment file"},
+			{File: "keys/id_rsa", Line: 1, Rule: "private-key", Description: "private key"},
+		}, nil)
+
+		deploymentService := DeploymentService{
+			FileHelper: fileHelperMock,
+		}
+
+		// when
+		err := deploymentService.scanArtifact("content", true)
+
+		// then
+		assert.EqualError(t, err, "found 2 possible secret(s) in the artifact, remove them from the package includes, allow them in .flight-secrets-allowlist or deploy with --no-secret-scan")
+	})
+
+	t.Run("scanArtifact with scan disabled returns nil", func(t *testing.T) {
+		// given
+		fileHelperMock := &mocks.FileHelperMock{}
+
+		deploymentService := DeploymentService{
+			FileHelper: fileHelperMock,
+		}
+
+		// when
+		err := deploymentService.scanArtifact("content", false)
+
+		// then
+		assert.Nil(t, err)
+		fileHelperMock.AssertNotCalled(t, "ScanSecrets", mock.Anything)
+	})
+
+	t.Run("signArtifact returns signature and key id", func(t *testing.T) {
+		// given
+		publicKey := "ed25519:" + base64.StdEncoding.EncodeToString(make([]byte, 32))
+		manifest := getManifest()
+		manifest.Environments[0].SigningKeys = []string{publicKey}
+
+		signingHelperMock := &mocks.SigningHelperMock{}
+		signingHelperMock.On("Sign", "sha256:digest").Return("signature", publicKey, nil)
+
+		deploymentService := DeploymentService{
+			SigningHelper: signingHelperMock,
+		}
+
+		// when
+		signature, keyID, err := signArtifact(deploymentService.SigningHelper, manifest, "dev", "sha256:digest")
+
+		// then
+		assert.Nil(t, err)
+		assert.Equal(t, "signature", signature)
+		assert.Equal(t, "66687aadf862bd77", keyID)
+	})
+
+	t.Run("signArtifact without key returns error when environment requires signatures", func(t *testing.T) {
+		// given
+		manifest := getManifest()
+		manifest.Environments[0].SigningKeys = []string{"ed25519:" + base64.StdEncoding.EncodeToString(make([]byte, 32))}
+
+		signingHelperMock := &mocks.SigningHelperMock{}
+		signingHelperMock.On("Sign", "sha256:digest").Return("", "", nil)
+
+		deploymentService := DeploymentService{
+			SigningHelper: signingHelperMock,
+		}
+
+		// when
+		_, _, err := signArtifact(deploymentService.SigningHelper, manifest, "dev", "sha256:digest")
+
+		// then
+		assert.EqualError(t, err, "environment dev only deploys signed artifacts, generate a key with flight keys generate or set FLIGHT_SIGNING_KEY")
+	})
+
+	t.Run("signArtifact with unknown key returns error", func(t *testing.T) {
+		// given
+		manifest := getManifest()
+		manifest.Environments[0].SigningKeys = []string{"ed25519:" + base64.StdEncoding.EncodeToString(make([]byte, 32))}
+
+		signingHelperMock := &mocks.SigningHelperMock{}
+		signingHelperMock.On("Sign", "sha256:digest").Return("signature", "ed25519:"+base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{1}, 32)), nil)
+
+		deploymentService := DeploymentService{
+			SigningHelper: signingHelperMock,
+		}
+
+		// when
+		_, _, err := signArtifact(deploymentService.SigningHelper, manifest, "dev", "sha256:digest")
+
+		// then
+		assert.ErrorContains(t, err, "which is not in the signing_keys of environment dev")
+	})
+
 	t.Run("uploadArtifact with success returns nil", func(t *testing.T) {
 		// given
 		artifact := models.Artifact{}
@@ -426,6 +712,55 @@ func TestDeploymentService(t *testing.T) {
 		clientMock.AssertExpectations(t)
 	})
 
+	t.Run("saveDeployment sends runtime settings of environment", func(t *testing.T) {
+		// given
+		memory := int64(1024)
+		artifact := models.Artifact{}
+		environment := "dev"
+		manifest := getManifest()
+		manifest.Architecture = "arm64"
+		manifest.Environments[0].Memory = &memory
+
+		clientMock := &mocks.ClientMock{}
+		clientMock.On("SaveDeployment", mock.MatchedBy(func(deployment models.Deployment) bool {
+			return *deployment.Runtime.Memory == memory && deployment.Runtime.Architecture == "arm64"
+		})).Return(models.Deployment{}, nil)
+
+		deploymentService := DeploymentService{
+			Client: clientMock,
+		}
+
+		// when
+		_, err := deploymentService.saveDeployment(artifact, environment, manifest)
+
+		// then
+		assert.Nil(t, err)
+		clientMock.AssertExpectations(t)
+	})
+
+	t.Run("saveDeployment sends package includes as files", func(t *testing.T) {
+		// given
+		includes := []string{"static"}
+		manifest := getManifest()
+		manifest.Package = &models.ManifestPackage{Includes: &includes}
+
+		clientMock := &mocks.ClientMock{}
+		clientMock.On("SaveDeployment", mock.MatchedBy(func(deployment models.Deployment) bool {
+			return deployment.Manifest.Files != nil && deployment.Manifest.Files == &includes
+		})).Return(models.Deployment{}, nil)
+
+		deploymentService := DeploymentService{
+			Client: clientMock,
+		}
+
+		// when
+		_, err := deploymentService.saveDeployment(models.Artifact{}, "dev", manifest)
+
+		// then
+		assert.Nil(t, err)
+		clientMock.AssertExpectations(t)
+	})
+
 	t.Run("saveDeployment with error returns error", func(t *testing.T) {
 		// given
 		artifact := models.Artifact{}
@@ -606,36 +941,111 @@ func TestDeploymentService(t *testing.T) {
 
 		fileHelperMock := &mocks.FileHelperMock{}
 		fileHelperMock.On("Package", manifest).Return("content", nil)
+		fileHelperMock.On("ScanSecrets", "content").Return([]models.SecretFinding{}, nil)
+		fileHelperMock.On("GenerateSbom", "content").Return("{}", nil)
+		fileHelperMock.On("CachedArtifact", helpers.ArtifactDigest(content)).Return((*models.CachedArtifact)(nil), nil)
+		fileHelperMock.On("CacheArtifact", mock.MatchedBy(func(cached models.CachedArtifact) bool {
+			return cached.ID == "1" && cached.Project == "test"
+		}), content).Return(nil)
+
+		signingHelperMock := &mocks.SigningHelperMock{}
+		signingHelperMock.On("Sign", mock.Anything).Return("", "", nil)
 
 		tokenHelperMock := &mocks.TokenHelperMock{}
 		tokenHelperMock.On("TokenExists").Return(true)
 
 		deploymentService := DeploymentService{
-			Client:        clientMock,
-			Configuration: configuration,
-			FileHelper:    fileHelperMock,
-			TokenHelper:   tokenHelperMock,
+			Client:            clientMock,
+			Configuration:     configuration,
+			LintService:       getLintServiceMock(),
+			FileHelper:        fileHelperMock,
+			SigningHelper:     signingHelperMock,
+			TokenHelper:       tokenHelperMock,
+			ValidationService: getValidationServiceMock(),
 		}
 
 		// when
-		err := deploymentService.Deploy(environment)
+		err := deploymentService.Deploy(models.DeployOptions{Environment: environment, SecretScan: true})
 
 		// then
 		assert.Nil(t, err)
 		clientMock.AssertExpectations(t)
+		fileHelperMock.AssertExpectations(t)
+	})
+
+	t.Run("reuseArtifact returns uploaded artifact of cached digest", func(t *testing.T) {
+		// given
+		clientMock := &mocks.ClientMock{}
+		clientMock.On("GetArtifact", "1").Return(models.Artifact{ID: "1", Digest: "sha256:digest", KeyID: "key"}, nil)
+
+		fileHelperMock := &mocks.FileHelperMock{}
+		fileHelperMock.On("CachedArtifact", "sha256:digest").Return(&models.CachedArtifact{ID: "1", Digest: "sha256:digest", KeyID: "key"}, nil)
+
+		deploymentService := DeploymentService{
+			Client:     clientMock,
+			FileHelper: fileHelperMock,
+		}
+
+		// when
+		artifact, reused := deploymentService.reuseArtifact("sha256:digest", "key")
+
+		// then
+		assert.True(t, reused)
+		assert.Equal(t, "1", artifact.ID)
+	})
+
+	t.Run("reuseArtifact with other signing key uploads artifact again", func(t *testing.T) {
+		// given
+		clientMock := &mocks.ClientMock{}
+
+		fileHelperMock := &mocks.FileHelperMock{}
+		fileHelperMock.On("CachedArtifact", "sha256:digest").Return(&models.CachedArtifact{ID: "1", Digest: "sha256:digest", KeyID: "old"}, nil)
+
+		deploymentService := DeploymentService{
+			Client:     clientMock,
+			FileHelper: fileHelperMock,
+		}
+
+		// when
+		_, reused := deploymentService.reuseArtifact("sha256:digest", "key")
+
+		// then
+		assert.False(t, reused)
+		clientMock.AssertNotCalled(t, "GetArtifact", mock.Anything)
+	})
+
+	t.Run("reuseArtifact with removed artifact uploads artifact again", func(t *testing.T) {
+		// given
+		clientMock := &mocks.ClientMock{}
+		clientMock.On("GetArtifact", "1").Return(models.Artifact{}, errors.New("not found"))
+
+		fileHelperMock := &mocks.FileHelperMock{}
+		fileHelperMock.On("CachedArtifact", "sha256:digest").Return(&models.CachedArtifact{ID: "1", Digest: "sha256:digest"}, nil)
+
+		deploymentService := DeploymentService{
+			Client:     clientMock,
+			FileHelper: fileHelperMock,
+		}
+
+		// when
+		_, reused := deploymentService.reuseArtifact("sha256:digest", "")
+
+		// then
+		assert.False(t, reused)
 	})
 }
 
 func getManifest() models.Manifest {
-	files := &[]string{
+	includes := &[]string{
 		"file1",
 		"file2",
 		"file3",
 	}
 
 	manifest := models.Manifest{
+		Version: 2,
 		Name:    "test",
-		Files:   files,
+		Package: &models.ManifestPackage{Includes: includes},
 		Trigger: "queue",
 		Environments: []models.ManifestEnvironment{
 			{
@@ -662,3 +1072,17 @@ func getManifest() models.Manifest {
 
 	return manifest
 }
+
+func getValidationServiceMock() *mocks.ValidationServiceMock {
+	validationServiceMock := &mocks.ValidationServiceMock{}
+	validationServiceMock.On("ValidateManifest", mock.Anything, mock.Anything).Return(nil)
+
+	return validationServiceMock
+}
+
+func getLintServiceMock() *mocks.LintServiceMock {
+	lintServiceMock := &mocks.LintServiceMock{}
+	lintServiceMock.On("CheckManifest", mock.Anything, mock.Anything).Return(nil)
+
+	return lintServiceMock
+}
@@ -0,0 +1,236 @@
+package service
+
+import (
+	"fmt"
+	"github.com/getflight/flight/context"
+	"github.com/getflight/flight/helpers"
+	"github.com/getflight/flight/http"
+	"github.com/getflight/flight/models"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/pkg/errors"
+	"github.com/samber/lo"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	domainStatusIssued = "issued"
+	domainStatusFailed = "failed"
+	domainPollRetries  = 90
+)
+
+var (
+	// domainPollInterval is the time between two checks of the certificate validation, a certificate is
+	// usually issued within minutes of the dns records being created
+	domainPollInterval = 10 * time.Second
+)
+
+type DomainServiceType interface {
+	Add(environment string, name string) error
+	List(environment string) error
+	Verify(environment string, name string) error
+	Remove(environment string, name string) error
+}
+
+type DomainService struct {
+	Client        http.ClientType
+	Configuration context.ConfigurationType
+	TokenHelper   helpers.TokenHelperType
+}
+
+// Add attaches a custom domain to the project and prints the dns records validating its certificate
+func (s *DomainService) Add(environment string, name string) error {
+	err := validator.New().Var(name, "fqdn")
+
+	if err != nil {
+		return errors.New(fmt.Sprintf("domain %s must be a fully qualified domain name", name))
+	}
+
+	manifest, project, err := s.getGatewayProject(environment)
+
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	domain, err := s.Client.SaveDomain(project.ID, models.Domain{Name: strings.ToLower(name)})
+
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	log.Infof("domain %s added to %s in %s", domain.Name, manifest.Name, environment)
+	s.printRecords(domain)
+
+	manifestEnvironment, _ := lo.Find[models.ManifestEnvironment](manifest.Environments, func(manifestEnvironment models.ManifestEnvironment) bool {
+		return manifestEnvironment.Name == environment
+	})
+
+	if !lo.ContainsBy[string](manifestEnvironment.Domains, func(manifestDomain string) bool {
+		return strings.EqualFold(manifestDomain, domain.Name)
+	}) {
+		log.Warnf("domain %s is not in the domains of environment %s in flight.yml, add it to keep it on the next deployment", domain.Name, environment)
+	}
+
+	return nil
+}
+
+// List prints the custom domains of the project with the status of their certificate
+func (s *DomainService) List(environment string) error {
+	_, project, err := s.getGatewayProject(environment)
+
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	domains, err := s.Client.GetDomains(project.ID)
+
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	if len(domains) == 0 {
+		log.Infof("no domains, the project is available at %s", project.Url)
+
+		return nil
+	}
+
+	for _, domain := range domains {
+		log.Infof("%s (%s)", domain.Name, domain.Status)
+	}
+
+	return nil
+}
+
+// Verify prints the dns records to create for a domain and waits until its certificate is issued
+func (s *DomainService) Verify(environment string, name string) error {
+	_, project, err := s.getGatewayProject(environment)
+
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	domain, err := s.findDomain(project, name)
+
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	if domain.Status == domainStatusIssued {
+		log.Infof("certificate for %s is issued", domain.Name)
+
+		return nil
+	}
+
+	s.printRecords(*domain)
+	log.Infof("waiting for certificate validation of %s", domain.Name)
+
+	for i := 0; i < domainPollRetries; i++ {
+		polledDomain, err := s.Client.GetDomain(domain.ID)
+
+		if err != nil {
+			return errors.WithStack(err)
+		}
+
+		switch polledDomain.Status {
+		case domainStatusIssued:
+			log.Infof("certificate for %s is issued", polledDomain.Name)
+
+			return nil
+		case domainStatusFailed:
+			return errors.New(fmt.Sprintf("certificate validation for %s failed, check the dns records and add the domain again", polledDomain.Name))
+		}
+
+		log.Debugf("certificate validation pending with status %s, retry attempt: %v for domain: %v", polledDomain.Status, i+1, polledDomain.Name)
+		time.Sleep(domainPollInterval)
+	}
+
+	return errors.New(fmt.Sprintf("certificate validation for %s is still pending, check the dns records and verify again later", domain.Name))
+}
+
+// Remove detaches a custom domain from the project
+func (s *DomainService) Remove(environment string, name string) error {
+	_, project, err := s.getGatewayProject(environment)
+
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	domain, err := s.findDomain(project, name)
+
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	err = s.Client.DeleteDomain(domain.ID)
+
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	log.Infof("domain %s removed, delete its dns records once traffic has moved", domain.Name)
+
+	return nil
+}
+
+func (s *DomainService) printRecords(domain models.Domain) {
+	if len(domain.Records) == 0 {
+		return
+	}
+
+	log.Info("create the following dns records:")
+
+	for _, record := range domain.Records {
+		log.Infof("  %s %s %s", record.Name, record.Type, record.Value)
+	}
+}
+
+func (s *DomainService) findDomain(project *models.Project, name string) (*models.Domain, error) {
+	domains, err := s.Client.GetDomains(project.ID)
+
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	domain, found := lo.Find[models.Domain](domains, func(domain models.Domain) bool {
+		return strings.EqualFold(domain.Name, name)
+	})
+
+	if !found {
+		return nil, errors.New(fmt.Sprintf("domain not found in project %s: %s", project.Name, name))
+	}
+
+	return &domain, nil
+}
+
+func (s *DomainService) getGatewayProject(environment string) (*models.Manifest, *models.Project, error) {
+	if !s.TokenHelper.TokenExists() {
+		return nil, nil, errors.New("token not found, login to manage domains")
+	}
+
+	err := s.Configuration.Init()
+
+	if err != nil {
+		return nil, nil, errors.WithStack(err)
+	}
+
+	manifest, err := s.Configuration.GetManifest()
+
+	if err != nil {
+		return nil, nil, errors.WithStack(err)
+	}
+
+	if manifest.Trigger != triggerGateway {
+		return nil, nil, errors.New(fmt.Sprintf("project %s is triggered by %s, custom domains require the gateway trigger", manifest.Name, manifest.Trigger))
+	}
+
+	project, err := findProject(s.Client, s.TokenHelper, environment, manifest.Name)
+
+	if err != nil {
+		return nil, nil, errors.WithStack(err)
+	}
+
+	return &manifest, project, nil
+}
@@ -0,0 +1,211 @@
+package service
+
+import (
+	"github.com/getflight/flight/mocks"
+	"github.com/getflight/flight/models"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestDomainService(t *testing.T) {
+	pollInterval := domainPollInterval
+	domainPollInterval = 0
+	t.Cleanup(func() {
+		domainPollInterval = pollInterval
+	})
+
+	t.Run("Add saves domain and prints dns records", func(t *testing.T) {
+		// given
+		hook := test.NewGlobal()
+		domain := models.Domain{
+			ID:      "4",
+			Name:    "api.example.com",
+			Status:  "pending_validation",
+			Records: []models.DomainRecord{{Name: "_abc.api.example.com.", Type: "CNAME", Value: "_xyz.acm-validations.aws."}},
+		}
+
+		clientMock := getDomainClientMock()
+		clientMock.On("SaveDomain", "3", models.Domain{Name: "api.example.com"}).Return(domain, nil)
+
+		domainService := DomainService{
+			Client:        clientMock,
+			Configuration: getDomainConfigurationMock("gateway"),
+			TokenHelper:   getDomainTokenHelperMock(),
+		}
+
+		// when
+		err := domainService.Add("dev", "API.example.com")
+
+		// then
+		assert.Nil(t, err)
+		assert.Contains(t, getLogs(hook), "create the following dns records:\n  _abc.api.example.com. CNAME _xyz.acm-validations.aws.")
+		assert.Contains(t, getLogs(hook), "domain api.example.com is not in the domains of environment dev")
+		clientMock.AssertExpectations(t)
+	})
+
+	t.Run("Add with invalid domain returns error", func(t *testing.T) {
+		// given
+		domainService := DomainService{}
+
+		// when
+		err := domainService.Add("dev", "localhost")
+
+		// then
+		assert.NotNil(t, err)
+	})
+
+	t.Run("Add with project not triggered by gateway returns error", func(t *testing.T) {
+		// given
+		domainService := DomainService{
+			Configuration: getDomainConfigurationMock("queue"),
+			TokenHelper:   getDomainTokenHelperMock(),
+		}
+
+		// when
+		err := domainService.Add("dev", "api.example.com")
+
+		// then
+		assert.NotNil(t, err)
+	})
+
+	t.Run("List prints domains with status", func(t *testing.T) {
+		// given
+		hook := test.NewGlobal()
+
+		clientMock := getDomainClientMock()
+		clientMock.On("GetDomains", "3").Return([]models.Domain{{ID: "4", Name: "api.example.com", Status: "issued"}}, nil)
+
+		domainService := DomainService{
+			Client:        clientMock,
+			Configuration: getDomainConfigurationMock("gateway"),
+			TokenHelper:   getDomainTokenHelperMock(),
+		}
+
+		// when
+		err := domainService.List("dev")
+
+		// then
+		assert.Nil(t, err)
+		assert.Contains(t, getLogs(hook), "api.example.com (issued)")
+		clientMock.AssertExpectations(t)
+	})
+
+	t.Run("Verify polls domain until certificate is issued", func(t *testing.T) {
+		// given
+		hook := test.NewGlobal()
+		domain := models.Domain{ID: "4", Name: "api.example.com", Status: "pending_validation"}
+		issuedDomain := models.Domain{ID: "4", Name: "api.example.com", Status: "issued"}
+
+		clientMock := getDomainClientMock()
+		clientMock.On("GetDomains", "3").Return([]models.Domain{domain}, nil)
+		clientMock.On("GetDomain", "4").Return(domain, nil).Once()
+		clientMock.On("GetDomain", "4").Return(issuedDomain, nil).Once()
+
+		domainService := DomainService{
+			Client:        clientMock,
+			Configuration: getDomainConfigurationMock("gateway"),
+			TokenHelper:   getDomainTokenHelperMock(),
+		}
+
+		// when
+		err := domainService.Verify("dev", "api.example.com")
+
+		// then
+		assert.Nil(t, err)
+		assert.Contains(t, getLogs(hook), "certificate for api.example.com is issued")
+		clientMock.AssertExpectations(t)
+	})
+
+	t.Run("Verify with failed validation returns error", func(t *testing.T) {
+		// given
+		domain := models.Domain{ID: "4", Name: "api.example.com", Status: "pending_validation"}
+
+		clientMock := getDomainClientMock()
+		clientMock.On("GetDomains", "3").Return([]models.Domain{domain}, nil)
+		clientMock.On("GetDomain", "4").Return(models.Domain{ID: "4", Name: "api.example.com", Status: "failed"}, nil)
+
+		domainService := DomainService{
+			Client:        clientMock,
+			Configuration: getDomainConfigurationMock("gateway"),
+			TokenHelper:   getDomainTokenHelperMock(),
+		}
+
+		// when
+		err := domainService.Verify("dev", "api.example.com")
+
+		// then
+		assert.NotNil(t, err)
+		clientMock.AssertExpectations(t)
+	})
+
+	t.Run("Remove deletes domain", func(t *testing.T) {
+		// given
+		clientMock := getDomainClientMock()
+		clientMock.On("GetDomains", "3").Return([]models.Domain{{ID: "4", Name: "api.example.com"}}, nil)
+		clientMock.On("DeleteDomain", "4").Return(nil)
+
+		domainService := DomainService{
+			Client:        clientMock,
+			Configuration: getDomainConfigurationMock("gateway"),
+			TokenHelper:   getDomainTokenHelperMock(),
+		}
+
+		// when
+		err := domainService.Remove("dev", "api.example.com")
+
+		// then
+		assert.Nil(t, err)
+		clientMock.AssertExpectations(t)
+	})
+
+	t.Run("Remove with unknown domain returns error", func(t *testing.T) {
+		// given
+		clientMock := getDomainClientMock()
+		clientMock.On("GetDomains", "3").Return([]models.Domain{}, nil)
+
+		domainService := DomainService{
+			Client:        clientMock,
+			Configuration: getDomainConfigurationMock("gateway"),
+			TokenHelper:   getDomainTokenHelperMock(),
+		}
+
+		// when
+		err := domainService.Remove("dev", "api.example.com")
+
+		// then
+		assert.NotNil(t, err)
+		clientMock.AssertExpectations(t)
+	})
+}
+
+func getDomainConfigurationMock(trigger string) *mocks.ConfigurationMock {
+	manifest := getManifest()
+	manifest.Trigger = trigger
+
+	configuration := &mocks.ConfigurationMock{}
+	configuration.On("Init").Return(nil)
+	configuration.On("GetManifest").Return(manifest, nil)
+
+	return configuration
+}
+
+func getDomainTokenHelperMock() *mocks.TokenHelperMock {
+	tokenHelperMock := &mocks.TokenHelperMock{}
+	tokenHelperMock.On("TokenExists").Return(true)
+	tokenHelperMock.On("GetOrganisation").Return("1", nil)
+
+	return tokenHelperMock
+}
+
+// getDomainClientMock returns a client finding project 3 named after the test manifest in environment dev
+func getDomainClientMock() *mocks.ClientMock {
+	organisation := models.Organisation{ID: "1", Environments: []models.Environment{{ID: "2", Name: "dev"}}}
+	environment := models.Environment{ID: "2", Name: "dev", Projects: []models.Project{{ID: "3", Name: "test"}}}
+
+	clientMock := &mocks.ClientMock{}
+	clientMock.On("GetOrganisation", "1").Return(organisation, nil)
+	clientMock.On("GetEnvironment", "2").Return(environment, nil)
+
+	return clientMock
+}
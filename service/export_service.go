@@ -0,0 +1,343 @@
+package service
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/getflight/flight/context"
+	"github.com/getflight/flight/helpers"
+	"github.com/getflight/flight/models"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/samber/lo"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	cloudformationVersion   = "2010-09-09"
+	cloudformationTransform = "AWS::Serverless-2016-10-31"
+	exportRuntime           = "provided.al2"
+	exportHandler           = "main"
+	exportDatabaseUsername  = "flight"
+	// exportMaxReceiveCount is used when the manifest leaves the receive count of the dead letter queue to the
+	// platform default
+	exportMaxReceiveCount = 5
+)
+
+var (
+	logicalIdSeparators = regexp.MustCompile(`[^A-Za-z0-9]+`)
+)
+
+type ExportServiceType interface {
+	Cloudformation(environment string) (string, error)
+}
+
+// ExportService renders what flight provisions for an environment in the formats of other tools, so that it
+// can be reviewed or deployed outside of flight
+type ExportService struct {
+	Configuration     context.ConfigurationType
+	ValidationService ValidationServiceType
+}
+
+type cloudformationTemplate struct {
+	AWSTemplateFormatVersion string                             `yaml:"AWSTemplateFormatVersion"`
+	Transform                string                             `yaml:"Transform"`
+	Description              string                             `yaml:"Description"`
+	Parameters               map[string]cloudformationParameter `yaml:"Parameters"`
+	Resources                map[string]cloudformationResource  `yaml:"Resources"`
+	Outputs                  map[string]cloudformationOutput    `yaml:"Outputs,omitempty"`
+}
+
+type cloudformationParameter struct {
+	Type        string `yaml:"Type"`
+	NoEcho      bool   `yaml:"NoEcho,omitempty"`
+	Description string `yaml:"Description"`
+}
+
+type cloudformationResource struct {
+	Type       string                 `yaml:"Type"`
+	DependsOn  string                 `yaml:"DependsOn,omitempty"`
+	Properties map[string]interface{} `yaml:"Properties,omitempty"`
+}
+
+type cloudformationOutput struct {
+	Description string      `yaml:"Description"`
+	Value       interface{} `yaml:"Value"`
+}
+
+// Cloudformation returns the manifest resolved for an environment as a SAM template. Values only known when
+// deploying, such as secrets, the code location and database passwords, are parameters of the template.
+func (s *ExportService) Cloudformation(environment string) (string, error) {
+	err := s.Configuration.Init()
+
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+
+	manifest, err := s.Configuration.GetManifest()
+
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+
+	err = s.ValidationService.ValidateManifest(manifest, environment)
+
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+
+	manifestEnvironment, _ := lo.Find[models.ManifestEnvironment](manifest.Environments, func(manifestEnvironment models.ManifestEnvironment) bool {
+		return manifestEnvironment.Name == environment
+	})
+
+	template := newCloudformationTemplate(resolveManifest(manifest, environment), manifestEnvironment)
+
+	content := &bytes.Buffer{}
+	content.WriteString(fmt.Sprintf("# %s in %s exported by flight export, deploy it with sam deploy\n", manifest.Name, environment))
+
+	encoder := yaml.NewEncoder(content)
+	encoder.SetIndent(manifestIndent)
+
+	err = encoder.Encode(template)
+
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+
+	return content.String(), nil
+}
+
+func newCloudformationTemplate(manifest models.Manifest, manifestEnvironment models.ManifestEnvironment) *cloudformationTemplate {
+	template := &cloudformationTemplate{
+		AWSTemplateFormatVersion: cloudformationVersion,
+		Transform:                cloudformationTransform,
+		Description:              fmt.Sprintf("%s in %s, the function is packaged as a zip with the bootstrap and main executable of flight deploy", manifest.Name, manifestEnvironment.Name),
+		Parameters: map[string]cloudformationParameter{
+			"CodeBucket": {Type: "String", Description: "bucket of the zip of the function"},
+			"CodeKey":    {Type: "String", Description: "key of the zip of the function"},
+		},
+		Resources: map[string]cloudformationResource{},
+		Outputs:   map[string]cloudformationOutput{},
+	}
+
+	function := map[string]interface{}{
+		"FunctionName":  fmt.Sprintf("%s-%s", manifest.Name, manifestEnvironment.Name),
+		"CodeUri":       map[string]interface{}{"Bucket": cloudformationRef("CodeBucket"), "Key": cloudformationRef("CodeKey")},
+		"Handler":       exportHandler,
+		"Runtime":       exportRuntime,
+		"Architectures": []string{lo.Ternary[string](manifest.Architecture != "", manifest.Architecture, "x86_64")},
+	}
+
+	if manifest.Memory != nil {
+		function["MemorySize"] = *manifest.Memory
+	}
+
+	if manifest.Timeout != nil {
+		function["Timeout"] = *manifest.Timeout
+	}
+
+	if manifest.EphemeralStorage != nil {
+		function["EphemeralStorage"] = map[string]interface{}{"Size": *manifest.EphemeralStorage}
+	}
+
+	if variables := exportVariables(template, manifestEnvironment.Variables); len(variables) > 0 {
+		function["Environment"] = map[string]interface{}{"Variables": variables}
+	}
+
+	if events := exportTrigger(template, manifest, manifestEnvironment); len(events) > 0 {
+		function["Events"] = events
+	}
+
+	template.Resources["Function"] = cloudformationResource{Type: "AWS::Serverless::Function", Properties: function}
+
+	for _, database := range manifestEnvironment.Databases {
+		exportDatabase(template, database)
+	}
+
+	return template
+}
+
+// exportVariables returns the variables of the function. Secrets and values read when deploying are
+// parameters, so that the template never contains them.
+func exportVariables(template *cloudformationTemplate, variables []models.ManifestVariable) map[string]interface{} {
+	exported := map[string]interface{}{}
+
+	for _, variable := range variables {
+		if !variable.Secret && variable.ValueFrom == nil {
+			exported[variable.Key] = variable.Value
+
+			continue
+		}
+
+		parameter := cloudformationParameter{Type: "String", NoEcho: variable.Secret, Description: fmt.Sprintf("value of variable %s", variable.Key)}
+
+		switch {
+		case variable.ValueFrom != nil:
+			parameter.Description = fmt.Sprintf("value of variable %s read%s", variable.Key, formatVariableSource(variable.ValueFrom))
+		case helpers.IsEncrypted(variable.Value):
+			parameter.Description = fmt.Sprintf("value of secret variable %s, encrypted in flight.yml", variable.Key)
+		case variable.Secret:
+			parameter.Description = fmt.Sprintf("value of secret variable %s", variable.Key)
+		}
+
+		parameterName := "Variable" + logicalId(variable.Key)
+		template.Parameters[parameterName] = parameter
+		exported[variable.Key] = cloudformationRef(parameterName)
+	}
+
+	return exported
+}
+
+// exportTrigger returns the events of the function and adds the resources of its trigger
+func exportTrigger(template *cloudformationTemplate, manifest models.Manifest, manifestEnvironment models.ManifestEnvironment) map[string]interface{} {
+	switch manifest.Trigger {
+	case triggerGateway:
+		template.Outputs["Url"] = cloudformationOutput{
+			Description: "url of the gateway",
+			Value:       cloudformationSub("https://${ServerlessHttpApi}.execute-api.${AWS::Region}.amazonaws.com"),
+		}
+
+		for i, domain := range manifestEnvironment.Domains {
+			exportDomain(template, domain, i)
+		}
+
+		return map[string]interface{}{"Gateway": map[string]interface{}{"Type": "HttpApi"}}
+	case triggerQueue:
+		return exportQueue(template, manifest)
+	case triggerSchedule:
+		schedule := map[string]interface{}{
+			"Schedule": manifest.Schedule.Expression,
+			"Enabled":  isScheduleEnabled(manifest),
+		}
+
+		if manifest.Schedule.Payload != "" {
+			schedule["Input"] = manifest.Schedule.Payload
+		}
+
+		return map[string]interface{}{"Schedule": map[string]interface{}{"Type": "Schedule", "Properties": schedule}}
+	}
+
+	return nil
+}
+
+func exportQueue(template *cloudformationTemplate, manifest models.Manifest) map[string]interface{} {
+	queueSettings := lo.Ternary[*models.ManifestQueue](manifest.Queue != nil, manifest.Queue, &models.ManifestQueue{})
+	queue := map[string]interface{}{}
+	event := map[string]interface{}{"Queue": cloudformationGetAtt("Queue.Arn")}
+
+	if queueSettings.VisibilityTimeout != nil {
+		queue["VisibilityTimeout"] = *queueSettings.VisibilityTimeout
+	}
+
+	if queueSettings.DeadLetterQueue != nil && *queueSettings.DeadLetterQueue {
+		template.Resources["DeadLetterQueue"] = cloudformationResource{Type: "AWS::SQS::Queue"}
+		queue["RedrivePolicy"] = map[string]interface{}{
+			"deadLetterTargetArn": cloudformationGetAtt("DeadLetterQueue.Arn"),
+			"maxReceiveCount":     lo.Ternary[int64](queueSettings.MaxReceiveCount != nil, lo.FromPtr[int64](queueSettings.MaxReceiveCount), exportMaxReceiveCount),
+		}
+	}
+
+	if queueSettings.BatchSize != nil {
+		event["BatchSize"] = *queueSettings.BatchSize
+	}
+
+	if queueSettings.BatchWindow != nil {
+		event["MaximumBatchingWindowInSeconds"] = *queueSettings.BatchWindow
+	}
+
+	template.Resources["Queue"] = cloudformationResource{Type: "AWS::SQS::Queue", Properties: queue}
+	template.Outputs["QueueUrl"] = cloudformationOutput{Description: "url of the queue triggering the function", Value: cloudformationRef("Queue")}
+
+	return map[string]interface{}{"Queue": map[string]interface{}{"Type": "SQS", "Properties": event}}
+}
+
+// exportDomain maps a custom domain to the gateway, using a certificate issued for the domains beforehand
+func exportDomain(template *cloudformationTemplate, domain string, index int) {
+	domainId := fmt.Sprintf("Domain%d", index+1)
+
+	template.Parameters["CertificateArn"] = cloudformationParameter{Type: "String", Description: "arn of the certificate of the custom domains"}
+	template.Resources[domainId] = cloudformationResource{
+		Type: "AWS::ApiGatewayV2::DomainName",
+		Properties: map[string]interface{}{
+			"DomainName": domain,
+			"DomainNameConfigurations": []interface{}{
+				map[string]interface{}{"CertificateArn": cloudformationRef("CertificateArn"), "EndpointType": "REGIONAL"},
+			},
+		},
+	}
+	template.Resources[domainId+"Mapping"] = cloudformationResource{
+		Type:      "AWS::ApiGatewayV2::ApiMapping",
+		DependsOn: "ServerlessHttpApiApiGatewayDefaultStage",
+		Properties: map[string]interface{}{
+			"ApiId":      cloudformationRef("ServerlessHttpApi"),
+			"DomainName": cloudformationRef(domainId),
+			"Stage":      "$default",
+		},
+	}
+}
+
+// exportDatabase adds a serverless aurora cluster, its password being a parameter of the template
+func exportDatabase(template *cloudformationTemplate, database models.ManifestDatabase) {
+	databaseId := "Database" + logicalId(database.Name)
+	scaling := map[string]interface{}{}
+
+	if database.MinCapacity != nil {
+		scaling["MinCapacity"] = *database.MinCapacity
+	}
+
+	if database.MaxCapacity != nil {
+		scaling["MaxCapacity"] = *database.MaxCapacity
+	}
+
+	if database.AutoPause != nil {
+		scaling["AutoPause"] = *database.AutoPause
+	}
+
+	cluster := map[string]interface{}{
+		"Engine":             "aurora-" + database.Driver,
+		"EngineMode":         "serverless",
+		"DatabaseName":       logicalIdSeparators.ReplaceAllString(database.Name, "_"),
+		"MasterUsername":     exportDatabaseUsername,
+		"MasterUserPassword": cloudformationRef(databaseId + "Password"),
+	}
+
+	if len(scaling) > 0 {
+		cluster["ScalingConfiguration"] = scaling
+	}
+
+	if database.EngineVersion != "" {
+		cluster["EngineVersion"] = database.EngineVersion
+	}
+
+	if database.BackupRetention != nil {
+		cluster["BackupRetentionPeriod"] = *database.BackupRetention
+	}
+
+	template.Parameters[databaseId+"Password"] = cloudformationParameter{Type: "String", NoEcho: true, Description: fmt.Sprintf("password of database %s", database.Name)}
+	template.Resources[databaseId] = cloudformationResource{Type: "AWS::RDS::DBCluster", Properties: cluster}
+	template.Outputs[databaseId+"Endpoint"] = cloudformationOutput{Description: fmt.Sprintf("endpoint of database %s", database.Name), Value: cloudformationGetAtt(databaseId + ".Endpoint.Address")}
+}
+
+// logicalId returns a name usable as a cloudformation logical id, such as ApiKey for API_KEY
+func logicalId(name string) string {
+	return strings.Join(lo.Map[string, string](logicalIdSeparators.Split(name, -1), func(word string, _ int) string {
+		if word == "" {
+			return ""
+		}
+
+		return strings.ToUpper(word[:1]) + strings.ToLower(word[1:])
+	}), "")
+}
+
+func cloudformationRef(name string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!Ref", Value: name}
+}
+
+func cloudformationGetAtt(attribute string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!GetAtt", Value: attribute}
+}
+
+func cloudformationSub(value string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!Sub", Value: value}
+}
@@ -0,0 +1,106 @@
+package service
+
+import (
+	"github.com/getflight/flight/mocks"
+	"github.com/getflight/flight/models"
+	"github.com/pkg/errors"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestExportService(t *testing.T) {
+	t.Run("Cloudformation returns template of environment with secrets as parameters", func(t *testing.T) {
+		// given
+		manifest := getManifest()
+		manifest.Queue = &models.ManifestQueue{BatchSize: lo.ToPtr[int64](5), DeadLetterQueue: lo.ToPtr[bool](true)}
+		manifest.Environments[0].Variables = append(manifest.Environments[0].Variables, models.ManifestVariable{Key: "API_KEY", Secret: true, ValueFrom: &models.ManifestVariableSource{Env: "API_KEY"}})
+
+		exportService := ExportService{
+			Configuration:     getExportConfigurationMock(manifest),
+			ValidationService: getValidationServiceMock(),
+		}
+
+		// when
+		template, err := exportService.Cloudformation("dev")
+
+		// then
+		assert.Nil(t, err)
+		assert.Contains(t, template, "  VariableApiKey:\n    Type: String\n    NoEcho: true\n    Description: value of variable API_KEY read from env API_KEY\n")
+		assert.Contains(t, template, "        Variables:\n          API_KEY: !Ref VariableApiKey\n          var1: value1\n          var2: value2\n")
+		assert.Contains(t, template, "        Queue:\n          Properties:\n            BatchSize: 5\n            Queue: !GetAtt Queue.Arn\n          Type: SQS\n")
+		assert.Contains(t, template, "      RedrivePolicy:\n        deadLetterTargetArn: !GetAtt DeadLetterQueue.Arn\n        maxReceiveCount: 5\n")
+		assert.Contains(t, template, "      Engine: aurora-mysql\n      EngineMode: serverless\n      MasterUserPassword: !Ref DatabaseDbPassword\n")
+		assert.NotContains(t, template, "secret")
+	})
+
+	t.Run("Cloudformation returns template with environment overrides and custom domains", func(t *testing.T) {
+		// given
+		manifest := getManifest()
+		manifest.Trigger = "gateway"
+		manifest.Memory = lo.ToPtr[int64](256)
+		manifest.Environments[0].Memory = lo.ToPtr[int64](1024)
+		manifest.Environments[0].Domains = []string{"api.example.com"}
+
+		exportService := ExportService{
+			Configuration:     getExportConfigurationMock(manifest),
+			ValidationService: getValidationServiceMock(),
+		}
+
+		// when
+		template, err := exportService.Cloudformation("dev")
+
+		// then
+		assert.Nil(t, err)
+		assert.Contains(t, template, "      MemorySize: 1024\n")
+		assert.Contains(t, template, "        Gateway:\n          Type: HttpApi\n")
+		assert.Contains(t, template, "  Domain1:\n    Type: AWS::ApiGatewayV2::DomainName\n    Properties:\n      DomainName: api.example.com\n")
+		assert.Contains(t, template, "      ApiId: !Ref ServerlessHttpApi\n      DomainName: !Ref Domain1\n")
+		assert.Contains(t, template, "    Value: !Sub https://${ServerlessHttpApi}.execute-api.${AWS::Region}.amazonaws.com\n")
+	})
+
+	t.Run("Cloudformation returns template with schedule event", func(t *testing.T) {
+		// given
+		manifest := getManifest()
+		manifest.Trigger = "schedule"
+		manifest.Schedule = &models.ManifestSchedule{Expression: "rate(1 hour)", Payload: `{"job":"sync"}`}
+
+		exportService := ExportService{
+			Configuration:     getExportConfigurationMock(manifest),
+			ValidationService: getValidationServiceMock(),
+		}
+
+		// when
+		template, err := exportService.Cloudformation("dev")
+
+		// then
+		assert.Nil(t, err)
+		assert.Contains(t, template, "        Schedule:\n          Properties:\n            Enabled: true\n            Input: '{\"job\":\"sync\"}'\n            Schedule: rate(1 hour)\n          Type: Schedule\n")
+	})
+
+	t.Run("Cloudformation returns error when environment is not in manifest", func(t *testing.T) {
+		// given
+		validationServiceMock := &mocks.ValidationServiceMock{}
+		validationServiceMock.On("ValidateManifest", getManifest(), "production").Return(errors.New("environment production not found"))
+
+		exportService := ExportService{
+			Configuration:     getExportConfigurationMock(getManifest()),
+			ValidationService: validationServiceMock,
+		}
+
+		// when
+		_, err := exportService.Cloudformation("production")
+
+		// then
+		assert.EqualError(t, err, "environment production not found")
+		validationServiceMock.AssertExpectations(t)
+	})
+}
+
+func getExportConfigurationMock(manifest models.Manifest) *mocks.ConfigurationMock {
+	configuration := &mocks.ConfigurationMock{}
+	configuration.On("Init").Return(nil)
+	configuration.On("GetManifest").Return(manifest, nil)
+
+	return configuration
+}
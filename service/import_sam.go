@@ -0,0 +1,311 @@
+package service
+
+import (
+	"fmt"
+	"github.com/getflight/flight/models"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/samber/lo"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	samFunctionType = "AWS::Serverless::Function"
+)
+
+var (
+	samSubReference = regexp.MustCompile(`\$\{([^{}!]+)\}`)
+	camelCaseWords  = regexp.MustCompile(`([a-z0-9])([A-Z])`)
+
+	// samStageParameters are the names of the parameters holding the stage a template is deployed to
+	samStageParameters = []string{"stage", "stagename", "environment", "env"}
+	samFunctionKeys    = []string{"Handler", "Runtime", "CodeUri", "FunctionName", "Description", "MemorySize", "Timeout", "Architectures", "EphemeralStorage", "Environment", "Events"}
+)
+
+// samResolver resolves the intrinsic functions of a SAM template for a stage, using the default values of
+// the parameters
+type samResolver struct {
+	stageParameter string
+	parameters     *yaml.Node
+}
+
+// importSam translates a function of an AWS SAM template, the allowed values of its stage parameter becoming
+// environments
+func importSam(root *yaml.Node, function string) (*manifestImport, error) {
+	resources := mappingValue(root, "Resources")
+
+	functionNames := lo.Filter[string](mappingKeys(resources), func(name string, _ int) bool {
+		resourceType, _ := plainScalar(mappingValue(mappingValue(resources, name), "Type"))
+
+		return resourceType == samFunctionType
+	})
+
+	functionName, err := selectFunction(functionNames, function, samFunctionType)
+
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	imported := &manifestImport{}
+	resolver := &samResolver{parameters: mappingValue(root, "Parameters")}
+	properties := mappingValue(mappingValue(resources, functionName), "Properties")
+	globals := mappingValue(mappingValue(root, "Globals"), "Function")
+	propertiesPath := fmt.Sprintf("Resources.%s.Properties", functionName)
+
+	imported.manifest.Name, _ = plainScalar(mappingValue(properties, "FunctionName"))
+
+	if imported.manifest.Name == "" || strings.Contains(imported.manifest.Name, "${") {
+		imported.manifest.Name = strings.ToLower(camelCaseWords.ReplaceAllString(strings.TrimSuffix(functionName, "Function"), "$1-$2"))
+	}
+
+	for _, stage := range resolver.stages() {
+		imported.manifest.Environments = append(imported.manifest.Environments, models.ManifestEnvironment{Name: stage})
+	}
+
+	for _, name := range mappingKeys(resources) {
+		if name != functionName {
+			resourceType, _ := plainScalar(mappingValue(mappingValue(resources, name), "Type"))
+			imported.warn("Resources.%s of type %s", name, resourceType)
+		}
+	}
+
+	reportUnknownKeys(imported, globals, "Globals.Function", samFunctionKeys)
+	reportUnknownKeys(imported, properties, propertiesPath, samFunctionKeys)
+
+	importRuntime(imported, "Globals.Function.Runtime", mappingValue(globals, "Runtime"))
+	importRuntime(imported, propertiesPath+".Runtime", mappingValue(properties, "Runtime"))
+
+	handler, _ := plainScalar(mappingValue(properties, "Handler"))
+	importHandler(imported, handler)
+
+	imported.manifest.Memory = importInt(imported, propertiesPath+".MemorySize", resolver.resolveSetting(mappingValue(properties, "MemorySize")), "Globals.Function.MemorySize", resolver.resolveSetting(mappingValue(globals, "MemorySize")))
+	imported.manifest.Timeout = importInt(imported, propertiesPath+".Timeout", resolver.resolveSetting(mappingValue(properties, "Timeout")), "Globals.Function.Timeout", resolver.resolveSetting(mappingValue(globals, "Timeout")))
+	imported.manifest.EphemeralStorage = importInt(imported, propertiesPath+".EphemeralStorage.Size", mappingValue(mappingValue(properties, "EphemeralStorage"), "Size"), "Globals.Function.EphemeralStorage.Size", mappingValue(mappingValue(globals, "EphemeralStorage"), "Size"))
+	imported.manifest.Architecture = importArchitecture(imported, propertiesPath+".Architectures", mappingValue(properties, "Architectures"), "Globals.Function.Architectures", mappingValue(globals, "Architectures"))
+
+	functionVariables := mappingValue(mappingValue(properties, "Environment"), "Variables")
+	resolver.importEnvironment(imported, "Globals.Function.Environment.Variables", mappingValue(mappingValue(globals, "Environment"), "Variables"), functionVariables)
+	resolver.importEnvironment(imported, propertiesPath+".Environment.Variables", functionVariables, nil)
+
+	importSamEvents(imported, propertiesPath+".Events", mappingValue(properties, "Events"))
+
+	return imported, nil
+}
+
+// stages returns the allowed values of the stage parameter, or its default value
+func (r *samResolver) stages() []string {
+	for _, name := range mappingKeys(r.parameters) {
+		if !lo.Contains[string](samStageParameters, strings.ToLower(name)) {
+			continue
+		}
+
+		r.stageParameter = name
+		parameter := mappingValue(r.parameters, name)
+
+		if allowedValues := mappingValue(parameter, "AllowedValues"); allowedValues != nil && allowedValues.Kind == yaml.SequenceNode {
+			return lo.Map[*yaml.Node, string](allowedValues.Content, func(value *yaml.Node, _ int) string {
+				return value.Value
+			})
+		}
+
+		if defaultValue, found := plainScalar(mappingValue(parameter, "Default")); found {
+			return []string{defaultValue}
+		}
+	}
+
+	return []string{defaultEnvironment}
+}
+
+// resolveSetting returns the node of a setting referencing a parameter with a default value, as settings
+// do not vary between environments
+func (r *samResolver) resolveSetting(setting *yaml.Node) *yaml.Node {
+	function, argument, found := samIntrinsic(setting)
+
+	if !found || function != "Ref" {
+		return setting
+	}
+
+	if defaultValue := mappingValue(mappingValue(r.parameters, argument.Value), "Default"); defaultValue != nil && argument.Value != r.stageParameter {
+		return defaultValue
+	}
+
+	return setting
+}
+
+func (r *samResolver) importEnvironment(imported *manifestImport, environmentPath string, variables *yaml.Node, overrides *yaml.Node) {
+	for _, key := range mappingKeys(variables) {
+		if mappingValue(overrides, key) != nil {
+			continue
+		}
+
+		values := map[string]models.ManifestVariable{}
+		var err error
+
+		for _, manifestEnvironment := range imported.manifest.Environments {
+			var value string
+			value, err = r.resolveValue(mappingValue(variables, key), manifestEnvironment.Name)
+
+			if err != nil {
+				break
+			}
+
+			values[manifestEnvironment.Name] = models.ManifestVariable{Value: value}
+		}
+
+		if err != nil {
+			imported.warn("%s.%s %v", environmentPath, key, err)
+
+			continue
+		}
+
+		importVariable(imported, key, values)
+	}
+}
+
+// resolveValue resolves the value of a variable for a stage, supporting references to parameters with Ref
+// and Sub. Other intrinsic functions refer to resources created by the template, which are not translated.
+func (r *samResolver) resolveValue(node *yaml.Node, stage string) (string, error) {
+	if value, found := plainScalar(node); found {
+		return value, nil
+	}
+
+	function, argument, found := samIntrinsic(node)
+
+	if !found {
+		return "", errors.New("is not a string")
+	}
+
+	switch {
+	case function == "Ref":
+		return r.resolveParameter(argument.Value, stage)
+	case function == "Fn::Sub" && argument.Kind == yaml.ScalarNode:
+		var resolveErr error
+
+		resolved := samSubReference.ReplaceAllStringFunc(argument.Value, func(reference string) string {
+			value, err := r.resolveParameter(strings.TrimSuffix(strings.TrimPrefix(reference, "${"), "}"), stage)
+
+			if err != nil && resolveErr == nil {
+				resolveErr = err
+			}
+
+			return value
+		})
+
+		return resolved, resolveErr
+	}
+
+	return "", errors.New(fmt.Sprintf("uses %s, which is resolved by cloudformation", function))
+}
+
+func (r *samResolver) resolveParameter(name string, stage string) (string, error) {
+	if name == r.stageParameter {
+		return stage, nil
+	}
+
+	if defaultValue, found := plainScalar(mappingValue(mappingValue(r.parameters, name), "Default")); found {
+		return defaultValue, nil
+	}
+
+	return "", errors.New(fmt.Sprintf("references %s, which is resolved by cloudformation", name))
+}
+
+func importSamEvents(imported *manifestImport, eventsPath string, events *yaml.Node) {
+	for _, name := range mappingKeys(events) {
+		event := mappingValue(events, name)
+		eventType, _ := plainScalar(mappingValue(event, "Type"))
+		eventProperties := mappingValue(event, "Properties")
+		eventPath := fmt.Sprintf("%s.%s.Properties", eventsPath, name)
+
+		switch eventType {
+		case "Api", "HttpApi":
+			if importTrigger(imported, eventPath, triggerGateway) {
+				reportUnknownKeys(imported, eventProperties, eventPath, []string{"Path", "Method", "RestApiId", "ApiId"})
+			}
+		case "SQS":
+			if importTrigger(imported, eventPath, triggerQueue) {
+				importSamQueue(imported, eventPath, eventProperties)
+			}
+		case "Schedule", "ScheduleV2":
+			if importTrigger(imported, eventPath, triggerSchedule) {
+				importSamSchedule(imported, eventPath, eventProperties)
+			}
+		default:
+			imported.warn("%s.%s events of type %s are not supported, the triggers are gateway, queue and schedule", eventsPath, name, eventType)
+		}
+	}
+
+	if imported.manifest.Trigger == "" {
+		imported.manifest.Trigger = triggerGateway
+		imported.warn("%s has no Api, SQS or Schedule event, the gateway trigger is used", eventsPath)
+	}
+}
+
+func importSamQueue(imported *manifestImport, eventPath string, properties *yaml.Node) {
+	imported.warn("%s.Queue flight creates the queue of the project, send the messages to it", eventPath)
+	reportUnknownKeys(imported, properties, eventPath, []string{"Queue", "BatchSize", "MaximumBatchingWindowInSeconds", "Enabled"})
+
+	batchSize := importInt(imported, eventPath+".BatchSize", mappingValue(properties, "BatchSize"), "", nil)
+	batchWindow := importInt(imported, eventPath+".MaximumBatchingWindowInSeconds", mappingValue(properties, "MaximumBatchingWindowInSeconds"), "", nil)
+
+	if batchSize != nil || batchWindow != nil {
+		imported.manifest.Queue = &models.ManifestQueue{BatchSize: batchSize, BatchWindow: batchWindow}
+	}
+}
+
+func importSamSchedule(imported *manifestImport, eventPath string, properties *yaml.Node) {
+	schedule := &models.ManifestSchedule{}
+	reportUnknownKeys(imported, properties, eventPath, []string{"Schedule", "ScheduleExpression", "Enabled", "State", "Input", "Name", "Description"})
+
+	for _, key := range []string{"Schedule", "ScheduleExpression"} {
+		if expression, found := plainScalar(mappingValue(properties, key)); found {
+			schedule.Expression = expression
+		}
+	}
+
+	if enabled, found := plainBool(mappingValue(properties, "Enabled")); found {
+		schedule.Enabled = enabled
+	}
+
+	if state, found := plainScalar(mappingValue(properties, "State")); found {
+		schedule.Enabled = lo.ToPtr[bool](state == "ENABLED")
+	}
+
+	if input := mappingValue(properties, "Input"); input != nil {
+		if payload, found := jsonPayload(input); found {
+			schedule.Payload = payload
+		} else {
+			imported.warn("%s.Input is not json", eventPath)
+		}
+	}
+
+	imported.manifest.Schedule = schedule
+}
+
+// samIntrinsic returns the intrinsic function of a node and its argument, in either the short !Ref form or
+// the long Ref: form
+func samIntrinsic(node *yaml.Node) (string, *yaml.Node, bool) {
+	node = resolveAlias(node)
+
+	if node == nil {
+		return "", nil, false
+	}
+
+	if strings.HasPrefix(node.Tag, "!") && !strings.HasPrefix(node.Tag, "!!") {
+		argument := *node
+		argument.Tag = ""
+
+		if node.Tag == "!Ref" {
+			return "Ref", &argument, true
+		}
+
+		return "Fn::" + strings.TrimPrefix(node.Tag, "!"), &argument, true
+	}
+
+	if keys := mappingKeys(node); len(keys) == 1 && (keys[0] == "Ref" || strings.HasPrefix(keys[0], "Fn::")) {
+		return keys[0], mappingValue(node, keys[0]), true
+	}
+
+	return "", nil, false
+}
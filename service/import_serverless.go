@@ -0,0 +1,385 @@
+package service
+
+import (
+	"fmt"
+	"github.com/getflight/flight/models"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/samber/lo"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	serverlessReference = regexp.MustCompile(`\$\{([^{}]+)\}`)
+
+	// serverlessKeys are the settings translated or safely ignored, the other settings are reported
+	serverlessKeys         = []string{"service", "frameworkVersion", "provider", "functions", "params", "custom", "useDotenv", "configValidationMode", "variablesResolutionMode"}
+	serverlessProviderKeys = []string{"name", "runtime", "stage", "region", "profile", "memorySize", "timeout", "architecture", "environment"}
+	serverlessFunctionKeys = []string{"handler", "name", "description", "runtime", "memorySize", "timeout", "architecture", "ephemeralStorageSize", "environment", "events"}
+	serverlessStageKeys    = []string{"sls:stage", "opt:stage", "self:provider.stage"}
+)
+
+// serverlessResolver resolves the ${...} references of a serverless.yml for a stage
+type serverlessResolver struct {
+	service string
+	params  *yaml.Node
+}
+
+// importServerless translates a function of a serverless.yml, its stages becoming environments
+func importServerless(root *yaml.Node, function string) (*manifestImport, error) {
+	functions := mappingValue(root, "functions")
+	functionName, err := selectFunction(mappingKeys(functions), function, "function")
+
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	serviceName, found := plainScalar(mappingValue(root, "service"))
+
+	if !found {
+		serviceName, _ = plainScalar(mappingValue(mappingValue(root, "service"), "name"))
+	}
+
+	imported := &manifestImport{}
+	imported.manifest.Name = serviceName
+
+	if len(mappingKeys(functions)) > 1 {
+		imported.manifest.Name = fmt.Sprintf("%s-%s", serviceName, functionName)
+	}
+
+	provider := mappingValue(root, "provider")
+	functionNode := mappingValue(functions, functionName)
+	functionPath := "functions." + functionName
+	resolver := &serverlessResolver{service: serviceName, params: mappingValue(root, "params")}
+
+	for _, stage := range serverlessStages(provider, resolver.params) {
+		imported.manifest.Environments = append(imported.manifest.Environments, models.ManifestEnvironment{Name: stage})
+	}
+
+	reportUnknownKeys(imported, root, "", serverlessKeys)
+	reportUnknownKeys(imported, provider, "provider", serverlessProviderKeys)
+	reportUnknownKeys(imported, functionNode, functionPath, serverlessFunctionKeys)
+
+	importRuntime(imported, "provider.runtime", mappingValue(provider, "runtime"))
+	importRuntime(imported, functionPath+".runtime", mappingValue(functionNode, "runtime"))
+
+	handler, _ := plainScalar(mappingValue(functionNode, "handler"))
+	importHandler(imported, handler)
+
+	imported.manifest.Memory = importInt(imported, functionPath+".memorySize", mappingValue(functionNode, "memorySize"), "provider.memorySize", mappingValue(provider, "memorySize"))
+	imported.manifest.Timeout = importInt(imported, functionPath+".timeout", mappingValue(functionNode, "timeout"), "provider.timeout", mappingValue(provider, "timeout"))
+	imported.manifest.EphemeralStorage = importInt(imported, functionPath+".ephemeralStorageSize", mappingValue(functionNode, "ephemeralStorageSize"), "", nil)
+	imported.manifest.Architecture = importArchitecture(imported, functionPath+".architecture", mappingValue(functionNode, "architecture"), "provider.architecture", mappingValue(provider, "architecture"))
+
+	resolver.importEnvironment(imported, "provider.environment", mappingValue(provider, "environment"), mappingValue(functionNode, "environment"))
+	resolver.importEnvironment(imported, functionPath+".environment", mappingValue(functionNode, "environment"), nil)
+
+	importServerlessEvents(imported, functionPath+".events", mappingValue(functionNode, "events"))
+
+	return imported, nil
+}
+
+// serverlessStages returns the stages of the service, which are the default stage of the provider and the
+// stages with parameters
+func serverlessStages(provider *yaml.Node, params *yaml.Node) []string {
+	var stages []string
+
+	if stage, found := plainScalar(mappingValue(provider, "stage")); found && !strings.Contains(stage, "${") {
+		stages = append(stages, stage)
+	}
+
+	for _, stage := range mappingKeys(params) {
+		if stage != "default" && !lo.Contains[string](stages, stage) {
+			stages = append(stages, stage)
+		}
+	}
+
+	if len(stages) == 0 {
+		stages = append(stages, defaultEnvironment)
+	}
+
+	return stages
+}
+
+// importEnvironment adds the environment variables to every environment, skipping the keys overridden by the
+// function environment
+func (r *serverlessResolver) importEnvironment(imported *manifestImport, environmentPath string, environment *yaml.Node, overrides *yaml.Node) {
+	for _, key := range mappingKeys(environment) {
+		if mappingValue(overrides, key) != nil {
+			continue
+		}
+
+		value, found := plainScalar(mappingValue(environment, key))
+
+		if !found {
+			imported.warn("%s.%s is not a string", environmentPath, key)
+
+			continue
+		}
+
+		values := map[string]models.ManifestVariable{}
+		var err error
+
+		for _, manifestEnvironment := range imported.manifest.Environments {
+			values[manifestEnvironment.Name], err = r.resolveVariable(value, manifestEnvironment.Name)
+
+			if err != nil {
+				break
+			}
+		}
+
+		if err != nil {
+			imported.warn("%s.%s %v", environmentPath, key, err)
+
+			continue
+		}
+
+		importVariable(imported, key, values)
+	}
+}
+
+// resolveVariable resolves the references of a variable value for a stage. A value made of a single
+// ${env:...} reference is read from the environment when deploying.
+func (r *serverlessResolver) resolveVariable(value string, stage string) (models.ManifestVariable, error) {
+	if match := serverlessReference.FindStringSubmatch(value); match != nil && match[0] == value && strings.HasPrefix(match[1], "env:") && !strings.Contains(match[1], ",") {
+		return models.ManifestVariable{ValueFrom: &models.ManifestVariableSource{Env: strings.TrimPrefix(match[1], "env:")}}, nil
+	}
+
+	var resolveErr error
+
+	resolved := serverlessReference.ReplaceAllStringFunc(value, func(reference string) string {
+		resolvedReference, err := r.resolveReference(strings.TrimSuffix(strings.TrimPrefix(reference, "${"), "}"), stage)
+
+		if err != nil && resolveErr == nil {
+			resolveErr = err
+		}
+
+		return resolvedReference
+	})
+
+	if resolveErr != nil {
+		return models.ManifestVariable{}, resolveErr
+	}
+
+	return models.ManifestVariable{Value: resolved}, nil
+}
+
+func (r *serverlessResolver) resolveReference(reference string, stage string) (string, error) {
+	source, fallback, hasFallback := strings.Cut(reference, ",")
+	source = strings.TrimSpace(source)
+
+	value, err := r.resolveSource(source, stage)
+
+	if err == nil || !hasFallback {
+		return value, err
+	}
+
+	fallback = strings.TrimSpace(fallback)
+
+	if len(fallback) >= 2 && (fallback[0] == '\'' || fallback[0] == '"') && fallback[len(fallback)-1] == fallback[0] {
+		return fallback[1 : len(fallback)-1], nil
+	}
+
+	return r.resolveReference(fallback, stage)
+}
+
+func (r *serverlessResolver) resolveSource(source string, stage string) (string, error) {
+	switch {
+	case lo.Contains[string](serverlessStageKeys, source):
+		return stage, nil
+	case source == "self:service":
+		return r.service, nil
+	case strings.HasPrefix(source, "param:"):
+		key := strings.TrimPrefix(source, "param:")
+
+		for _, paramsStage := range []string{stage, "default"} {
+			if value, found := plainScalar(mappingValue(mappingValue(r.params, paramsStage), key)); found {
+				return value, nil
+			}
+		}
+
+		return "", errors.New(fmt.Sprintf("references ${%s} which has no value in stage %s", source, stage))
+	case strings.HasPrefix(source, "ssm:") || strings.HasPrefix(source, "aws:") || strings.HasPrefix(source, "cf:") || strings.HasPrefix(source, "s3:"):
+		return "", errors.New(fmt.Sprintf("references ${%s} which is read from aws when deploying, encrypt the value with flight secrets encrypt instead", source))
+	case strings.HasPrefix(source, "env:"):
+		return "", errors.New(fmt.Sprintf("references ${%s} within a value, only values made of a single ${env:...} are read from the environment", source))
+	}
+
+	return "", errors.New(fmt.Sprintf("references ${%s} which cannot be resolved", source))
+}
+
+func importServerlessEvents(imported *manifestImport, eventsPath string, events *yaml.Node) {
+	if events != nil && events.Kind == yaml.SequenceNode {
+		for i, event := range events.Content {
+			keys := mappingKeys(event)
+
+			if len(keys) == 0 {
+				continue
+			}
+
+			eventPath := fmt.Sprintf("%s[%d].%s", eventsPath, i, keys[0])
+			eventNode := mappingValue(event, keys[0])
+
+			switch keys[0] {
+			case "http", "httpApi":
+				if importTrigger(imported, eventPath, triggerGateway) {
+					reportUnknownKeys(imported, eventNode, eventPath, []string{"path", "method"})
+				}
+			case "sqs":
+				if importTrigger(imported, eventPath, triggerQueue) {
+					importServerlessQueue(imported, eventPath, eventNode)
+				}
+			case "schedule":
+				if importTrigger(imported, eventPath, triggerSchedule) {
+					importServerlessSchedule(imported, eventPath, eventNode)
+				}
+			default:
+				imported.warn("%s events are not supported, the triggers are gateway, queue and schedule", eventPath)
+			}
+		}
+	}
+
+	if imported.manifest.Trigger == "" {
+		imported.manifest.Trigger = triggerGateway
+		imported.warn("%s has no http, sqs or schedule event, the gateway trigger is used", eventsPath)
+	}
+}
+
+func importServerlessQueue(imported *manifestImport, eventPath string, event *yaml.Node) {
+	arn, found := plainScalar(event)
+
+	if !found {
+		arn, _ = plainScalar(mappingValue(event, "arn"))
+	}
+
+	imported.warn("%s flight creates the queue of the project, send the messages of %s to it", eventPath, lo.Ternary[string](arn != "", arn, "the queue"))
+	reportUnknownKeys(imported, event, eventPath, []string{"arn", "batchSize", "maximumBatchingWindow", "enabled"})
+
+	batchSize := importInt(imported, eventPath+".batchSize", mappingValue(event, "batchSize"), "", nil)
+	batchWindow := importInt(imported, eventPath+".maximumBatchingWindow", mappingValue(event, "maximumBatchingWindow"), "", nil)
+
+	if batchSize != nil || batchWindow != nil {
+		imported.manifest.Queue = &models.ManifestQueue{BatchSize: batchSize, BatchWindow: batchWindow}
+	}
+}
+
+func importServerlessSchedule(imported *manifestImport, eventPath string, event *yaml.Node) {
+	schedule := &models.ManifestSchedule{}
+	rate := event
+
+	if event.Kind == yaml.MappingNode {
+		rate = mappingValue(event, "rate")
+		reportUnknownKeys(imported, event, eventPath, []string{"rate", "enabled", "input", "name", "description"})
+
+		if enabled, found := plainBool(mappingValue(event, "enabled")); found {
+			schedule.Enabled = enabled
+		} else if mappingValue(event, "enabled") != nil {
+			imported.warn("%s.enabled is not a boolean", eventPath)
+		}
+
+		if input := mappingValue(event, "input"); input != nil {
+			if payload, found := jsonPayload(input); found {
+				schedule.Payload = payload
+			} else {
+				imported.warn("%s.input is not json", eventPath)
+			}
+		}
+	}
+
+	if rate != nil && rate.Kind == yaml.SequenceNode {
+		for _, extraRate := range lo.Slice[*yaml.Node](rate.Content, 1, len(rate.Content)) {
+			imported.warn("%s.rate %s, a schedule has a single expression", eventPath, extraRate.Value)
+		}
+
+		rate = lo.Ternary[*yaml.Node](len(rate.Content) > 0, rate.Content[0], nil)
+	}
+
+	schedule.Expression, _ = plainScalar(rate)
+	imported.manifest.Schedule = schedule
+}
+
+// importTrigger sets the trigger of the manifest, reporting the events of other triggers as functions are
+// triggered by a single source
+func importTrigger(imported *manifestImport, eventPath string, trigger string) bool {
+	switch {
+	case imported.manifest.Trigger == "":
+		imported.manifest.Trigger = trigger
+
+		return true
+	case imported.manifest.Trigger == trigger && trigger == triggerGateway:
+		return true
+	case imported.manifest.Trigger == trigger:
+		imported.warn("%s, a project has a single %s", eventPath, trigger)
+
+		return false
+	}
+
+	imported.warn("%s, the project is already triggered by %s and has a single trigger", eventPath, imported.manifest.Trigger)
+
+	return false
+}
+
+// importRuntime reports the runtimes of other languages, flight deploying go executables on a custom runtime
+func importRuntime(imported *manifestImport, settingPath string, setting *yaml.Node) {
+	runtime, found := plainScalar(setting)
+
+	if found && runtime != "go1.x" && !strings.HasPrefix(runtime, "provided") {
+		imported.warn("%s %s, flight deploys go executables", settingPath, runtime)
+	}
+}
+
+// importInt returns the first setting that is set, reporting settings that are not plain numbers
+func importInt(imported *manifestImport, settingPath string, setting *yaml.Node, fallbackPath string, fallback *yaml.Node) *int64 {
+	if setting == nil {
+		if fallback == nil {
+			return nil
+		}
+
+		return importInt(imported, fallbackPath, fallback, "", nil)
+	}
+
+	value, found := plainInt(setting)
+
+	if !found {
+		imported.warn("%s %s is not a number", settingPath, setting.Value)
+
+		return nil
+	}
+
+	return value
+}
+
+func importArchitecture(imported *manifestImport, settingPath string, setting *yaml.Node, fallbackPath string, fallback *yaml.Node) string {
+	if setting == nil {
+		if fallback == nil {
+			return ""
+		}
+
+		return importArchitecture(imported, fallbackPath, fallback, "", nil)
+	}
+
+	if setting.Kind == yaml.SequenceNode && len(setting.Content) == 1 {
+		setting = setting.Content[0]
+	}
+
+	value, found := plainScalar(setting)
+
+	if !found || !lo.Contains[string]([]string{"x86_64", "arm64"}, value) {
+		imported.warn("%s %s is not x86_64 or arm64", settingPath, setting.Value)
+
+		return ""
+	}
+
+	return value
+}
+
+// reportUnknownKeys reports the keys of a mapping that are not translated
+func reportUnknownKeys(imported *manifestImport, node *yaml.Node, nodePath string, keys []string) {
+	for _, key := range mappingKeys(node) {
+		if !lo.Contains[string](keys, key) {
+			imported.warn("%s", joinManifestPath(nodePath, key))
+		}
+	}
+}
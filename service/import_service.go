@@ -0,0 +1,280 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/getflight/flight/context"
+	"github.com/getflight/flight/helpers"
+	"github.com/getflight/flight/models"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+
+	log "github.com/sirupsen/logrus"
+)
+
+type ImportServiceType interface {
+	Import(file string, function string, force bool) error
+}
+
+// ImportService translates the configuration of other serverless tools to a flight manifest
+type ImportService struct {
+	FileSystem        helpers.FileSystemType
+	ValidationService ValidationServiceType
+}
+
+// manifestImport is a manifest being translated, along with the settings that could not be translated
+type manifestImport struct {
+	manifest models.Manifest
+	warnings []string
+}
+
+func (i *manifestImport) warn(format string, args ...interface{}) {
+	i.warnings = append(i.warnings, fmt.Sprintf(format, args...))
+}
+
+// Import writes flight.yml from a serverless.yml or AWS SAM template. A single function is translated, which
+// must be given when the file defines several. Settings that could not be translated are reported, and kept
+// as comments at the top of the manifest.
+func (s *ImportService) Import(file string, function string, force bool) error {
+	if _, err := s.FileSystem.ReadFile(manifestFilename); err == nil && !force {
+		return errors.New(fmt.Sprintf("%s already exists, use --force to overwrite it", manifestFilename))
+	}
+
+	content, err := s.FileSystem.ReadFile(file)
+
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	document := &yaml.Node{}
+	err = yaml.Unmarshal(content, document)
+
+	if err != nil {
+		return errors.Wrap(err, fmt.Sprintf("%s is not valid yaml", file))
+	}
+
+	root := document
+
+	if root.Kind == yaml.DocumentNode && len(root.Content) > 0 {
+		root = root.Content[0]
+	}
+
+	var imported *manifestImport
+
+	switch {
+	case mappingValue(root, "AWSTemplateFormatVersion") != nil || mappingValue(root, "Transform") != nil:
+		imported, err = importSam(root, function)
+	case mappingValue(root, "service") != nil && mappingValue(root, "functions") != nil:
+		imported, err = importServerless(root, function)
+	default:
+		return errors.New(fmt.Sprintf("%s is neither a serverless.yml nor an AWS SAM template", file))
+	}
+
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	imported.manifest.Version = context.ManifestVersion
+
+	for _, warning := range imported.warnings {
+		log.Warnf("not translated: %s", warning)
+	}
+
+	err = s.ValidationService.ValidateManifest(imported.manifest, "")
+
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	manifestContent, err := encodeManifest(imported.manifest)
+
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	err = s.FileSystem.WriteFile(manifestFilename, append([]byte(importHeader(file, imported.warnings)), manifestContent...), 0644)
+
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	log.Infof("%s written from %s with %d setting(s) not translated", manifestFilename, file, len(imported.warnings))
+	log.Infof("build the executable named after the project before deploying: GOOS=linux GOARCH=amd64 CGO_ENABLED=0 go build -o %s", imported.manifest.Name)
+
+	return nil
+}
+
+func importHeader(file string, warnings []string) string {
+	var b strings.Builder
+
+	b.WriteString(fmt.Sprintf("# imported from %s by flight import\n", path.Base(file)))
+
+	if len(warnings) > 0 {
+		b.WriteString("# not translated:\n")
+
+		for _, warning := range warnings {
+			b.WriteString(fmt.Sprintf("#   - %s\n", strings.ReplaceAll(warning, "\n", " ")))
+		}
+	}
+
+	return b.String()
+}
+
+// importHandler reports the handler when it is not the executable flight deploys, which is named after the
+// project
+func importHandler(imported *manifestImport, handler string) {
+	if handler != "" && path.Base(handler) != imported.manifest.Name {
+		imported.warn("handler %s, build the executable as %s instead", handler, imported.manifest.Name)
+	}
+}
+
+// importVariable adds a variable to every environment, with a value per environment
+func importVariable(imported *manifestImport, key string, values map[string]models.ManifestVariable) {
+	for i, environment := range imported.manifest.Environments {
+		variable, found := values[environment.Name]
+
+		if !found {
+			continue
+		}
+
+		variable.Key = key
+		imported.manifest.Environments[i].Variables = append(imported.manifest.Environments[i].Variables, variable)
+	}
+}
+
+// mappingValue returns the value of a key in a mapping node, which is nil when the key does not exist
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	node = resolveAlias(node)
+
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return resolveAlias(node.Content[i+1])
+		}
+	}
+
+	return nil
+}
+
+// mappingKeys returns the keys of a mapping node, in the order of the document
+func mappingKeys(node *yaml.Node) []string {
+	node = resolveAlias(node)
+
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+
+	var keys []string
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		keys = append(keys, node.Content[i].Value)
+	}
+
+	return keys
+}
+
+func resolveAlias(node *yaml.Node) *yaml.Node {
+	for node != nil && node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+
+	return node
+}
+
+// plainScalar returns the value of a scalar node without a custom tag, found is false for other nodes,
+// such as the !Ref or ${...} references that have to be resolved first
+func plainScalar(node *yaml.Node) (string, bool) {
+	node = resolveAlias(node)
+
+	if node == nil || node.Kind != yaml.ScalarNode || (node.Tag != "" && !strings.HasPrefix(node.Tag, "!!")) {
+		return "", false
+	}
+
+	return node.Value, true
+}
+
+func plainInt(node *yaml.Node) (*int64, bool) {
+	value, found := plainScalar(node)
+
+	if !found {
+		return nil, false
+	}
+
+	number, err := strconv.ParseInt(value, 10, 64)
+
+	if err != nil {
+		return nil, false
+	}
+
+	return &number, true
+}
+
+func plainBool(node *yaml.Node) (*bool, bool) {
+	value, found := plainScalar(node)
+
+	if !found {
+		return nil, false
+	}
+
+	enabled, err := strconv.ParseBool(value)
+
+	if err != nil {
+		return nil, false
+	}
+
+	return &enabled, true
+}
+
+// jsonPayload returns a schedule input as json, inputs being given either as a json string or as a mapping
+func jsonPayload(node *yaml.Node) (string, bool) {
+	if value, found := plainScalar(node); found {
+		return value, json.Valid([]byte(value))
+	}
+
+	var payload interface{}
+
+	if err := node.Decode(&payload); err != nil {
+		return "", false
+	}
+
+	content, err := json.Marshal(payload)
+
+	if err != nil {
+		return "", false
+	}
+
+	return string(content), true
+}
+
+// selectFunction returns the function to translate, which must be given when there are several
+func selectFunction(names []string, function string, kind string) (string, error) {
+	sorted := append([]string{}, names...)
+	sort.Strings(sorted)
+
+	if function != "" {
+		for _, name := range names {
+			if name == function {
+				return name, nil
+			}
+		}
+
+		return "", errors.New(fmt.Sprintf("%s %s not found, choose one of %s", kind, function, strings.Join(sorted, ", ")))
+	}
+
+	switch len(names) {
+	case 0:
+		return "", errors.New(fmt.Sprintf("no %s found", kind))
+	case 1:
+		return names[0], nil
+	}
+
+	return "", errors.New(fmt.Sprintf("a flight project deploys a single function, choose the %s to import with --function: %s", kind, strings.Join(sorted, ", ")))
+}
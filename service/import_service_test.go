@@ -0,0 +1,259 @@
+package service
+
+import (
+	"github.com/getflight/flight/mocks"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"io/fs"
+	"testing"
+)
+
+const serverlessFile = `service: orders
+frameworkVersion: "3"
+provider:
+  name: aws
+  runtime: provided.al2
+  stage: dev
+  memorySize: 512
+  architecture: arm64
+  iam:
+    role: arn:aws:iam::123456789012:role/orders
+  environment:
+    STAGE: ${sls:stage}
+    TABLE: ${self:service}-${sls:stage}
+params:
+  default:
+    logLevel: info
+  prod:
+    logLevel: warn
+functions:
+  api:
+    handler: bootstrap
+    timeout: 10
+    environment:
+      LOG_LEVEL: ${param:logLevel}
+      API_KEY: ${ssm:/orders/api-key}
+      TOKEN: ${env:ORDERS_TOKEN}
+    events:
+      - httpApi: '*'
+      - sqs:
+          arn: arn:aws:sqs:eu-west-1:123456789012:orders
+          batchSize: 5
+plugins:
+  - serverless-go-plugin
+`
+
+const serverlessManifest = `# imported from serverless.yml by flight import
+# not translated:
+#   - plugins
+#   - provider.iam
+#   - handler bootstrap, build the executable as orders instead
+#   - functions.api.environment.API_KEY references ${ssm:/orders/api-key} which is read from aws when deploying, encrypt the value with flight secrets encrypt instead
+#   - functions.api.events[1].sqs, the project is already triggered by gateway and has a single trigger
+version: 2
+name: orders
+trigger: gateway
+memory: 512
+timeout: 10
+architecture: arm64
+environments:
+  - name: dev
+    variables:
+      - key: STAGE
+        value: dev
+      - key: TABLE
+        value: orders-dev
+      - key: LOG_LEVEL
+        value: info
+      - key: TOKEN
+        value_from:
+          env: ORDERS_TOKEN
+  - name: prod
+    variables:
+      - key: STAGE
+        value: prod
+      - key: TABLE
+        value: orders-prod
+      - key: LOG_LEVEL
+        value: warn
+      - key: TOKEN
+        value_from:
+          env: ORDERS_TOKEN
+`
+
+const samFile = `AWSTemplateFormatVersion: "2010-09-09"
+Transform: AWS::Serverless-2016-10-31
+Parameters:
+  Stage:
+    Type: String
+    AllowedValues: [staging, prod]
+  Retention:
+    Type: Number
+    Default: 7
+Globals:
+  Function:
+    Timeout: 30
+    Runtime: provided.al2
+Resources:
+  NightlyReportFunction:
+    Type: AWS::Serverless::Function
+    Properties:
+      Handler: nightly-report
+      MemorySize: 256
+      Architectures: [x86_64]
+      Environment:
+        Variables:
+          BUCKET: !Sub "reports-${Stage}"
+          RETENTION: !Ref Retention
+          TABLE: !GetAtt ReportsTable.Arn
+      Events:
+        Nightly:
+          Type: Schedule
+          Properties:
+            Schedule: cron(0 3 * * ? *)
+            Enabled: false
+            Input: '{"report": "daily"}'
+  ReportsTable:
+    Type: AWS::DynamoDB::Table
+`
+
+const samManifest = `# imported from template.yaml by flight import
+# not translated:
+#   - Resources.ReportsTable of type AWS::DynamoDB::Table
+#   - Resources.NightlyReportFunction.Properties.Environment.Variables.TABLE uses Fn::GetAtt, which is resolved by cloudformation
+version: 2
+name: nightly-report
+trigger: schedule
+schedule:
+  expression: cron(0 3 * * ? *)
+  enabled: false
+  payload: '{"report": "daily"}'
+memory: 256
+timeout: 30
+architecture: x86_64
+environments:
+  - name: staging
+    variables:
+      - key: BUCKET
+        value: reports-staging
+      - key: RETENTION
+        value: "7"
+  - name: prod
+    variables:
+      - key: BUCKET
+        value: reports-prod
+      - key: RETENTION
+        value: "7"
+`
+
+func TestImportService(t *testing.T) {
+	t.Run("Import writes manifest translated from serverless.yml", func(t *testing.T) {
+		// given
+		fileSystemMock := getImportFileSystemMock("serverless.yml", serverlessFile)
+		fileSystemMock.On("WriteFile", "flight.yml", []byte(serverlessManifest), fs.FileMode(0644)).Return(nil)
+
+		importService := ImportService{
+			FileSystem:        fileSystemMock,
+			ValidationService: getValidationServiceMock(),
+		}
+
+		// when
+		err := importService.Import("serverless.yml", "", false)
+
+		// then
+		assert.Nil(t, err)
+		fileSystemMock.AssertExpectations(t)
+	})
+
+	t.Run("Import writes manifest translated from SAM template", func(t *testing.T) {
+		// given
+		fileSystemMock := getImportFileSystemMock("template.yaml", samFile)
+		fileSystemMock.On("WriteFile", "flight.yml", []byte(samManifest), fs.FileMode(0644)).Return(nil)
+
+		importService := ImportService{
+			FileSystem:        fileSystemMock,
+			ValidationService: getValidationServiceMock(),
+		}
+
+		// when
+		err := importService.Import("template.yaml", "", false)
+
+		// then
+		assert.Nil(t, err)
+		fileSystemMock.AssertExpectations(t)
+	})
+
+	t.Run("Import returns error when function is not chosen among several", func(t *testing.T) {
+		// given
+		fileSystemMock := getImportFileSystemMock("serverless.yml", "service: orders\nfunctions:\n  api:\n    handler: api\n  worker:\n    handler: worker\n")
+
+		importService := ImportService{
+			FileSystem: fileSystemMock,
+		}
+
+		// when
+		err := importService.Import("serverless.yml", "", false)
+
+		// then
+		assert.EqualError(t, err, "a flight project deploys a single function, choose the function to import with --function: api, worker")
+	})
+
+	t.Run("Import names project after service and chosen function", func(t *testing.T) {
+		// given
+		fileSystemMock := getImportFileSystemMock("serverless.yml", "service: orders\nfunctions:\n  api:\n    handler: api\n  worker:\n    handler: orders-worker\n    events:\n      - schedule: rate(1 hour)\n")
+		fileSystemMock.On("WriteFile", "flight.yml", []byte("# imported from serverless.yml by flight import\nversion: 2\nname: orders-worker\ntrigger: schedule\nschedule:\n  expression: rate(1 hour)\nenvironments:\n  - name: dev\n"), fs.FileMode(0644)).Return(nil)
+
+		importService := ImportService{
+			FileSystem:        fileSystemMock,
+			ValidationService: getValidationServiceMock(),
+		}
+
+		// when
+		err := importService.Import("serverless.yml", "worker", false)
+
+		// then
+		assert.Nil(t, err)
+		fileSystemMock.AssertExpectations(t)
+	})
+
+	t.Run("Import returns error when file format is unknown", func(t *testing.T) {
+		// given
+		fileSystemMock := getImportFileSystemMock("docker-compose.yml", "services:\n  api:\n    image: api\n")
+
+		importService := ImportService{
+			FileSystem: fileSystemMock,
+		}
+
+		// when
+		err := importService.Import("docker-compose.yml", "", false)
+
+		// then
+		assert.EqualError(t, err, "docker-compose.yml is neither a serverless.yml nor an AWS SAM template")
+	})
+
+	t.Run("Import returns error when manifest exists", func(t *testing.T) {
+		// given
+		fileSystemMock := &mocks.FileSystemMock{}
+		fileSystemMock.On("ReadFile", "flight.yml").Return([]byte("name: test\n"), nil)
+
+		importService := ImportService{
+			FileSystem: fileSystemMock,
+		}
+
+		// when
+		err := importService.Import("serverless.yml", "", false)
+
+		// then
+		assert.EqualError(t, err, "flight.yml already exists, use --force to overwrite it")
+		fileSystemMock.AssertNotCalled(t, "WriteFile", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func getImportFileSystemMock(file string, content string) *mocks.FileSystemMock {
+	fileSystemMock := &mocks.FileSystemMock{}
+	fileSystemMock.On("ReadFile", "flight.yml").Return([]byte{}, errors.New("not found"))
+	fileSystemMock.On("ReadFile", file).Return([]byte(content), nil)
+
+	return fileSystemMock
+}
@@ -0,0 +1,373 @@
+package service
+
+import (
+	"bufio"
+	"bytes"
+	"embed"
+	"fmt"
+	"github.com/getflight/flight/context"
+	"github.com/getflight/flight/helpers"
+	"github.com/getflight/flight/models"
+	"go/parser"
+	"go/token"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/samber/lo"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	manifestFilename    = "flight.yml"
+	ignoreFilename      = ".flightignore"
+	defaultTemplate     = "api"
+	defaultEnvironment  = "dev"
+	defaultMainPackage  = "."
+	flightignoreContent = "flightignore.tmpl"
+)
+
+var (
+	//go:embed templates
+	templates embed.FS
+
+	// initTemplates are the starter manifests offered by flight init, by name
+	initTemplates = map[string]string{
+		"api":    "HTTP API behind a gateway",
+		"worker": "queue worker",
+		"cron":   "scheduled job",
+	}
+	invalidNameCharacters = regexp.MustCompile(`[^a-z0-9-]+`)
+)
+
+type InitServiceType interface {
+	Init(options models.InitOptions) error
+}
+
+type InitService struct {
+	FileSystem        helpers.FileSystemType
+	Input             io.Reader
+	ValidationService ValidationServiceType
+	reader            *bufio.Reader
+}
+
+type initDatabase struct {
+	Name   string
+	Driver string
+}
+
+type initData struct {
+	Module       string
+	Name         string
+	Main         string
+	Environments []string
+	Databases    []initDatabase
+}
+
+// Init writes a commented manifest for the go module of the current directory, along with a .flightignore
+// file when asked for
+func (s *InitService) Init(options models.InitOptions) error {
+	if _, err := s.FileSystem.ReadFile(manifestFilename); err == nil && !options.Force {
+		return errors.New(fmt.Sprintf("%s already exists, use --force to overwrite it", manifestFilename))
+	}
+
+	data, templateName, err := s.getInitData(options)
+
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	content, err := s.renderManifest(templateName, data)
+
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	err = s.FileSystem.WriteFile(manifestFilename, content, 0644)
+
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	log.Infof("%s written for %s using the %s template", manifestFilename, data.Name, templateName)
+
+	if options.Ignore {
+		err = s.writeIgnore(options.Force)
+
+		if err != nil {
+			return errors.WithStack(err)
+		}
+	}
+
+	log.Infof("build %s with: GOOS=linux GOARCH=amd64 CGO_ENABLED=0 go build -o %s %s", data.Name, data.Name, data.Main)
+	log.Infof("then deploy with: flight deploy -e %s", data.Environments[0])
+
+	return nil
+}
+
+// getInitData detects the module and main packages of the current directory and completes the options with
+// the detected values, asking for them when interactive
+func (s *InitService) getInitData(options models.InitOptions) (*initData, string, error) {
+	module := s.detectModule()
+	mainPackages, err := s.detectMainPackages()
+
+	if err != nil {
+		return nil, "", errors.WithStack(err)
+	}
+
+	defaultName := "app"
+	defaultMain := defaultMainPackage
+
+	if module != "" {
+		defaultName = path.Base(module)
+	}
+
+	if len(mainPackages) > 0 {
+		defaultMain = mainPackages[0]
+
+		if defaultMain != defaultMainPackage {
+			defaultName = path.Base(defaultMain)
+		}
+	}
+
+	name, err := s.answer(options.Interactive, options.Name, "project name", defaultName)
+
+	if err != nil {
+		return nil, "", errors.WithStack(err)
+	}
+
+	templateNames := lo.Keys[string, string](initTemplates)
+	sort.Strings(templateNames)
+
+	templateName, err := s.answer(options.Interactive, options.Template, fmt.Sprintf("template (%s)", s.describeTemplates(templateNames)), defaultTemplate)
+
+	if err != nil {
+		return nil, "", errors.WithStack(err)
+	}
+
+	if _, found := initTemplates[templateName]; !found {
+		return nil, "", errors.New(fmt.Sprintf("template %s not found, choose one of %s", templateName, strings.Join(templateNames, ", ")))
+	}
+
+	main, err := s.answer(options.Interactive && len(mainPackages) > 1, options.Main, fmt.Sprintf("main package (%s)", strings.Join(mainPackages, ", ")), defaultMain)
+
+	if err != nil {
+		return nil, "", errors.WithStack(err)
+	}
+
+	environments, err := s.answerList(options.Interactive, options.Environments, "environments, comma separated", defaultEnvironment)
+
+	if err != nil {
+		return nil, "", errors.WithStack(err)
+	}
+
+	databases, err := s.answerList(options.Interactive, options.Databases, "databases as name:driver with driver mysql or postgresql, comma separated", "")
+
+	if err != nil {
+		return nil, "", errors.WithStack(err)
+	}
+
+	data := &initData{
+		Module:       lo.Ternary[string](module != "", module, name),
+		Name:         invalidNameCharacters.ReplaceAllString(strings.ToLower(name), "-"),
+		Main:         main,
+		Environments: environments,
+	}
+
+	for _, database := range databases {
+		databaseName, driver, found := strings.Cut(database, ":")
+
+		if !found {
+			return nil, "", errors.New(fmt.Sprintf("database %s must be of the form name:driver", database))
+		}
+
+		data.Databases = append(data.Databases, initDatabase{Name: databaseName, Driver: driver})
+	}
+
+	return data, templateName, nil
+}
+
+// renderManifest renders the manifest template and validates the result, so init never writes a manifest
+// that deploy would reject
+func (s *InitService) renderManifest(name string, data *initData) ([]byte, error) {
+	manifestTemplate, err := template.ParseFS(templates, "templates/databases.tmpl", fmt.Sprintf("templates/%s.yml.tmpl", name))
+
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	content := &bytes.Buffer{}
+	err = manifestTemplate.ExecuteTemplate(content, fmt.Sprintf("%s.yml.tmpl", name), data)
+
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	node := &yaml.Node{}
+	err = yaml.Unmarshal(content.Bytes(), node)
+
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	manifest, err := context.DecodeManifest(node)
+
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	err = s.ValidationService.ValidateManifest(manifest, "")
+
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return content.Bytes(), nil
+}
+
+func (s *InitService) writeIgnore(force bool) error {
+	if _, err := s.FileSystem.ReadFile(ignoreFilename); err == nil && !force {
+		log.Infof("%s already exists, keeping it", ignoreFilename)
+
+		return nil
+	}
+
+	content, err := templates.ReadFile("templates/" + flightignoreContent)
+
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	err = s.FileSystem.WriteFile(ignoreFilename, content, 0644)
+
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	log.Infof("%s written", ignoreFilename)
+
+	return nil
+}
+
+// detectModule returns the path of the go module of the current directory, which is empty outside of a module
+func (s *InitService) detectModule() string {
+	content, err := s.FileSystem.ReadFile("go.mod")
+
+	if err != nil {
+		log.Debugf("no go module found: %v", err)
+
+		return ""
+	}
+
+	for _, line := range strings.Split(string(content), "\n") {
+		if line = strings.TrimSpace(line); strings.HasPrefix(line, "module ") {
+			return strings.Trim(strings.TrimSpace(strings.TrimPrefix(line, "module ")), `"`)
+		}
+	}
+
+	return ""
+}
+
+// detectMainPackages returns the directories of the main packages of the current directory, relative to it
+func (s *InitService) detectMainPackages() ([]string, error) {
+	var mainPackages []string
+
+	err := filepath.WalkDir(".", func(filePath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return errors.WithStack(err)
+		}
+
+		if entry.IsDir() {
+			if filePath != "." && (strings.HasPrefix(entry.Name(), ".") || entry.Name() == "vendor" || entry.Name() == "testdata") {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		if !strings.HasSuffix(filePath, ".go") || strings.HasSuffix(filePath, "_test.go") {
+			return nil
+		}
+
+		file, err := parser.ParseFile(token.NewFileSet(), filePath, nil, parser.PackageClauseOnly)
+
+		if err != nil {
+			log.Debugf("skipping %s: %v", filePath, err)
+
+			return nil
+		}
+
+		directory := "./" + filepath.ToSlash(filepath.Dir(filePath))
+
+		if directory == "./." {
+			directory = defaultMainPackage
+		}
+
+		if file.Name.Name == "main" && !lo.Contains[string](mainPackages, directory) {
+			mainPackages = append(mainPackages, directory)
+		}
+
+		return nil
+	})
+
+	if err != nil && !os.IsNotExist(err) {
+		return nil, errors.WithStack(err)
+	}
+
+	return mainPackages, nil
+}
+
+// answer returns the given value, or asks for it when interactive, falling back to the default value
+func (s *InitService) answer(interactive bool, value string, question string, defaultValue string) (string, error) {
+	if value != "" || !interactive {
+		return lo.Ternary[string](value != "", value, defaultValue), nil
+	}
+
+	if s.reader == nil {
+		s.reader = bufio.NewReader(lo.Ternary[io.Reader](s.Input != nil, s.Input, os.Stdin))
+	}
+
+	fmt.Printf("%s [%s]: ", question, defaultValue)
+
+	line, err := s.reader.ReadString('\n')
+
+	if err != nil && err != io.EOF {
+		return "", errors.WithStack(err)
+	}
+
+	line = strings.TrimSpace(line)
+
+	return lo.Ternary[string](line != "", line, defaultValue), nil
+}
+
+func (s *InitService) answerList(interactive bool, values []string, question string, defaultValue string) ([]string, error) {
+	if len(values) > 0 {
+		return values, nil
+	}
+
+	value, err := s.answer(interactive, "", question, defaultValue)
+
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return lo.Compact[string](lo.Map[string, string](strings.Split(value, ","), func(item string, _ int) string {
+		return strings.TrimSpace(item)
+	})), nil
+}
+
+func (s *InitService) describeTemplates(names []string) string {
+	return strings.Join(lo.Map[string, string](names, func(name string, _ int) string {
+		return fmt.Sprintf("%s: %s", name, initTemplates[name])
+	}), ", ")
+}
@@ -0,0 +1,154 @@
+package service
+
+import (
+	"github.com/getflight/flight/mocks"
+	"github.com/getflight/flight/models"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"io/fs"
+	"strings"
+	"testing"
+)
+
+func TestInitService(t *testing.T) {
+	t.Run("Init writes valid manifest for each template", func(t *testing.T) {
+		for _, template := range []string{"api", "worker", "cron"} {
+			// given
+			var written []byte
+
+			fileSystemMock := getInitFileSystemMock()
+			fileSystemMock.On("WriteFile", "flight.yml", mock.Anything, fs.FileMode(0644)).Run(func(args mock.Arguments) {
+				written = args.Get(1).([]byte)
+			}).Return(nil)
+
+			initService := InitService{
+				FileSystem:        fileSystemMock,
+				ValidationService: getValidationServiceMock(),
+			}
+
+			// when
+			err := initService.Init(models.InitOptions{
+				Template:     template,
+				Environments: []string{"dev", "prod"},
+				Databases:    []string{"orders:postgresql"},
+			})
+
+			// then
+			assert.Nil(t, err)
+			assert.Contains(t, string(written), "# flight.yml of github.com/example/orders-api")
+			assert.Contains(t, string(written), "name: orders-api\n")
+			assert.Contains(t, string(written), "  - name: prod\n")
+			assert.Contains(t, string(written), "      - name: orders\n        driver: postgresql\n")
+		}
+	})
+
+	t.Run("Init asks for options when interactive", func(t *testing.T) {
+		// given
+		var written []byte
+
+		fileSystemMock := getInitFileSystemMock()
+		fileSystemMock.On("WriteFile", "flight.yml", mock.Anything, fs.FileMode(0644)).Run(func(args mock.Arguments) {
+			written = args.Get(1).([]byte)
+		}).Return(nil)
+
+		initService := InitService{
+			FileSystem:        fileSystemMock,
+			Input:             strings.NewReader("Billing\nworker\nstaging, prod\n\n"),
+			ValidationService: getValidationServiceMock(),
+		}
+
+		// when
+		err := initService.Init(models.InitOptions{Interactive: true})
+
+		// then
+		assert.Nil(t, err)
+		assert.Contains(t, string(written), "name: billing\n")
+		assert.Contains(t, string(written), "trigger: queue\n")
+		assert.Contains(t, string(written), "  - name: staging\n")
+		assert.Contains(t, string(written), "    # databases:\n")
+	})
+
+	t.Run("Init writes flightignore when asked for", func(t *testing.T) {
+		// given
+		fileSystemMock := getInitFileSystemMock()
+		fileSystemMock.On("ReadFile", ".flightignore").Return([]byte{}, errors.New("not found"))
+		fileSystemMock.On("WriteFile", "flight.yml", mock.Anything, fs.FileMode(0644)).Return(nil)
+		fileSystemMock.On("WriteFile", ".flightignore", mock.Anything, fs.FileMode(0644)).Return(nil)
+
+		initService := InitService{
+			FileSystem:        fileSystemMock,
+			ValidationService: getValidationServiceMock(),
+		}
+
+		// when
+		err := initService.Init(models.InitOptions{Ignore: true})
+
+		// then
+		assert.Nil(t, err)
+		fileSystemMock.AssertExpectations(t)
+	})
+
+	t.Run("Init returns error when manifest exists", func(t *testing.T) {
+		// given
+		fileSystemMock := &mocks.FileSystemMock{}
+		fileSystemMock.On("ReadFile", "flight.yml").Return([]byte("name: test\n"), nil)
+
+		initService := InitService{
+			FileSystem: fileSystemMock,
+		}
+
+		// when
+		err := initService.Init(models.InitOptions{})
+
+		// then
+		assert.EqualError(t, err, "flight.yml already exists, use --force to overwrite it")
+		fileSystemMock.AssertNotCalled(t, "WriteFile", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Init returns error when template is unknown", func(t *testing.T) {
+		// given
+		fileSystemMock := getInitFileSystemMock()
+
+		initService := InitService{
+			FileSystem: fileSystemMock,
+		}
+
+		// when
+		err := initService.Init(models.InitOptions{Template: "website"})
+
+		// then
+		assert.EqualError(t, err, "template website not found, choose one of api, cron, worker")
+	})
+
+	t.Run("Init returns error when database driver is not supported", func(t *testing.T) {
+		// given
+		fileSystemMock := getInitFileSystemMock()
+
+		validationServiceMock := &mocks.ValidationServiceMock{}
+		validationServiceMock.On("ValidateManifest", mock.MatchedBy(func(manifest models.Manifest) bool {
+			return manifest.Environments[0].Databases[0].Driver == "mongodb"
+		}), "").Return(errors.New("driver mongodb is not supported"))
+
+		initService := InitService{
+			FileSystem:        fileSystemMock,
+			ValidationService: validationServiceMock,
+		}
+
+		// when
+		err := initService.Init(models.InitOptions{Databases: []string{"orders:mongodb"}})
+
+		// then
+		assert.EqualError(t, err, "driver mongodb is not supported")
+		validationServiceMock.AssertExpectations(t)
+		fileSystemMock.AssertNotCalled(t, "WriteFile", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func getInitFileSystemMock() *mocks.FileSystemMock {
+	fileSystemMock := &mocks.FileSystemMock{}
+	fileSystemMock.On("ReadFile", "flight.yml").Return([]byte{}, errors.New("not found"))
+	fileSystemMock.On("ReadFile", "go.mod").Return([]byte("module github.com/example/orders-api\n\ngo 1.18\n"), nil)
+
+	return fileSystemMock
+}
@@ -0,0 +1,39 @@
+package service
+
+import (
+	"github.com/getflight/flight/helpers"
+
+	"github.com/pkg/errors"
+
+	log "github.com/sirupsen/logrus"
+)
+
+type KeyServiceType interface {
+	Generate(force bool) (string, error)
+}
+
+// KeyService manages the key signing the artifacts deployed by the user
+type KeyService struct {
+	SigningHelper helpers.SigningHelperType
+}
+
+// Generate generates the signing key of the user and returns its public key, to be added to the signing_keys
+// of the environments that only deploy artifacts signed by trusted keys
+func (s *KeyService) Generate(force bool) (string, error) {
+	publicKey, err := s.SigningHelper.GenerateKey(force)
+
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+
+	keyID, err := helpers.SigningKeyID(publicKey)
+
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+
+	log.Infof("signing key %s generated, add its public key to the signing_keys of your environments", keyID)
+	log.Infof("in ci, set %s to the private key stored in the work path", helpers.SigningKeyVariable)
+
+	return publicKey, nil
+}
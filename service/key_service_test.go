@@ -0,0 +1,46 @@
+package service
+
+import (
+	"encoding/base64"
+	"github.com/getflight/flight/mocks"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestKeyService(t *testing.T) {
+	t.Run("Generate returns public key", func(t *testing.T) {
+		// given
+		publicKey := "ed25519:" + base64.StdEncoding.EncodeToString(make([]byte, 32))
+
+		signingHelperMock := &mocks.SigningHelperMock{}
+		signingHelperMock.On("GenerateKey", true).Return(publicKey, nil)
+
+		keyService := KeyService{
+			SigningHelper: signingHelperMock,
+		}
+
+		// when
+		result, err := keyService.Generate(true)
+
+		// then
+		assert.Nil(t, err)
+		assert.Equal(t, publicKey, result)
+	})
+
+	t.Run("Generate with existing key returns error", func(t *testing.T) {
+		// given
+		signingHelperMock := &mocks.SigningHelperMock{}
+		signingHelperMock.On("GenerateKey", false).Return("", errors.New("signing key already exists, use --force to replace it"))
+
+		keyService := KeyService{
+			SigningHelper: signingHelperMock,
+		}
+
+		// when
+		_, err := keyService.Generate(false)
+
+		// then
+		assert.EqualError(t, err, "signing key already exists, use --force to replace it")
+	})
+}
@@ -0,0 +1,437 @@
+package service
+
+import (
+	"fmt"
+	"github.com/getflight/flight/context"
+	"github.com/getflight/flight/helpers"
+	"github.com/getflight/flight/models"
+	"path"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/mitchellh/mapstructure"
+	"github.com/pkg/errors"
+	"github.com/samber/lo"
+	"gopkg.in/yaml.v3"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	policyFilename  = ".flight-policy.yml"
+	severityError   = "error"
+	severityWarning = "warning"
+	severityOff     = "off"
+	// matchesCondition matches the value against a regular expression, which may contain the , and |
+	// separators of validator conditions
+	matchesCondition = "matches="
+)
+
+var (
+	policyPathSegment = regexp.MustCompile(`^([^\[\]]+)(?:\[([^\[\]]*)\])?$`)
+
+	// builtinRules apply to every manifest, a rule of the policy file with the same name overrides them
+	builtinRules = []models.PolicyRule{
+		{
+			Name:        "production-database-capacity",
+			Description: "production databases must set max_capacity to bound their cost",
+			Path:        "environments[prod*].databases[*].max_capacity",
+			Condition:   "required",
+			Severity:    severityError,
+		},
+		{
+			Name:        "plaintext-secret",
+			Description: "variables named *_SECRET must be secret",
+			Path:        "environments[*].variables[*_SECRET].secret",
+			Condition:   "eq=true",
+			Severity:    severityError,
+		},
+		{
+			Name:        "plaintext-password",
+			Description: "variables named *_PASSWORD must be secret",
+			Path:        "environments[*].variables[*_PASSWORD].secret",
+			Condition:   "eq=true",
+			Severity:    severityError,
+		},
+		{
+			Name:        "environment-name",
+			Description: "environment names are made of lowercase letters, digits and dashes",
+			Path:        "environments[*].name",
+			Condition:   matchesCondition + "^[a-z][a-z0-9-]*$",
+			Severity:    severityWarning,
+		},
+	}
+)
+
+type LintServiceType interface {
+	Lint(environment string) error
+	CheckManifest(manifest models.Manifest, environment string) error
+}
+
+// LintService checks the manifest against the rules of the organisation, which are the built-in rules and
+// the rules of the .flight-policy.yml file next to the manifest
+type LintService struct {
+	Configuration context.ConfigurationType
+	FileSystem    helpers.FileSystemType
+}
+
+// PolicyViolation is a value of the manifest that does not satisfy the condition of a rule
+type PolicyViolation struct {
+	Rule     models.PolicyRule
+	File     string
+	Path     string
+	Line     int
+	Column   int
+	Value    string
+	Severity string
+}
+
+func (v PolicyViolation) Error() string {
+	var b strings.Builder
+
+	if v.Line > 0 {
+		b.WriteString(fmt.Sprintf("%s:%d:%d: ", v.File, v.Line, v.Column))
+	}
+
+	b.WriteString(v.Path)
+
+	if v.Value != "" {
+		b.WriteString(" " + v.Value)
+	}
+
+	b.WriteString(fmt.Sprintf(" violates %s (%s)", v.Rule.Name, v.Rule.Condition))
+
+	if v.Rule.Description != "" {
+		b.WriteString(": " + v.Rule.Description)
+	}
+
+	return b.String()
+}
+
+// policyValue is a value of the manifest selected by the path of a rule
+type policyValue struct {
+	path  string
+	value reflect.Value
+}
+
+// Lint reads the manifest from the configuration and reports every policy violation found in it
+func (s *LintService) Lint(environment string) error {
+	err := s.Configuration.Init()
+
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	manifest, err := s.Configuration.GetManifest()
+
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	return s.CheckManifest(manifest, environment)
+}
+
+// CheckManifest reports the policy violations of the manifest for the given environment, or for every
+// environment when empty. An error is returned when a violation is at error severity.
+func (s *LintService) CheckManifest(manifest models.Manifest, environment string) error {
+	rules, err := s.getRules()
+
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	violations, err := s.lintManifest(manifest, environment, rules)
+
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	blocking := 0
+
+	for _, violation := range violations {
+		if violation.Severity == severityError {
+			blocking++
+			log.Error(violation.Error())
+		} else {
+			log.Warn(violation.Error())
+		}
+	}
+
+	if blocking > 0 {
+		return errors.New(fmt.Sprintf("%d policy violation(s) at error severity, fix them or change the severity of their rule in %s", blocking, policyFilename))
+	}
+
+	if len(violations) == 0 {
+		log.Infof("manifest satisfies %d policy rule(s)", len(rules))
+	}
+
+	return nil
+}
+
+func (s *LintService) lintManifest(manifest models.Manifest, environment string, rules []models.PolicyRule) ([]PolicyViolation, error) {
+	var violations []PolicyViolation
+
+	node, err := s.Configuration.GetManifestNode()
+
+	if err != nil {
+		log.Debugf("%+v", err)
+	}
+
+	validate := newManifestValidator()
+
+	for _, rule := range rules {
+		values, err := findPolicyValues(reflect.ValueOf(manifest), strings.Split(rule.Path, "."), "", environment)
+
+		if err != nil {
+			return nil, errors.Wrap(err, fmt.Sprintf("rule %s has an invalid path %s", rule.Name, rule.Path))
+		}
+
+		for _, value := range values {
+			satisfied, err := checkCondition(validate, value.value, rule.Condition)
+
+			if err != nil {
+				return nil, errors.Wrap(err, fmt.Sprintf("rule %s has an invalid condition %s", rule.Name, rule.Condition))
+			}
+
+			if satisfied {
+				continue
+			}
+
+			violation := PolicyViolation{
+				Rule:     rule,
+				File:     s.Configuration.GetManifestFile(),
+				Path:     value.path,
+				Value:    formatPolicyValue(value.value),
+				Severity: rule.Severity,
+			}
+
+			violation.Line, violation.Column = findManifestPosition(node, value.path)
+			violations = append(violations, violation)
+		}
+	}
+
+	return violations, nil
+}
+
+// getRules returns the built-in rules merged with the rules of the policy file, leaving out the rules
+// turned off
+func (s *LintService) getRules() ([]models.PolicyRule, error) {
+	rules := append([]models.PolicyRule{}, builtinRules...)
+	policyFile := filepath.Join(filepath.Dir(s.Configuration.GetManifestFile()), policyFilename)
+	content, err := s.FileSystem.ReadFile(policyFile)
+
+	if err != nil {
+		log.Debugf("no policy file found: %v", err)
+	} else {
+		policy, err := decodePolicy(content)
+
+		if err != nil {
+			return nil, errors.Wrap(err, fmt.Sprintf("%s is invalid", policyFile))
+		}
+
+		for _, policyRule := range policy.Rules {
+			rules, err = mergeRule(rules, policyRule)
+
+			if err != nil {
+				return nil, errors.Wrap(err, fmt.Sprintf("%s is invalid", policyFile))
+			}
+		}
+	}
+
+	return lo.Filter[models.PolicyRule](rules, func(rule models.PolicyRule, _ int) bool {
+		return rule.Severity != severityOff
+	}), nil
+}
+
+func decodePolicy(content []byte) (*models.Policy, error) {
+	policy := &models.Policy{}
+	values := map[string]interface{}{}
+	err := yaml.Unmarshal(content, &values)
+
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		TagName: "json",
+		Result:  policy,
+	})
+
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	err = decoder.Decode(values)
+
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return policy, nil
+}
+
+// mergeRule overrides the set fields of the rule with the same name, or adds the rule when it is new
+func mergeRule(rules []models.PolicyRule, policyRule models.PolicyRule) ([]models.PolicyRule, error) {
+	if policyRule.Severity != "" && !lo.Contains[string]([]string{severityError, severityWarning, severityOff}, policyRule.Severity) {
+		return nil, errors.New(fmt.Sprintf("rule %s has severity %s, use error, warning or off", policyRule.Name, policyRule.Severity))
+	}
+
+	for i, rule := range rules {
+		if rule.Name != policyRule.Name {
+			continue
+		}
+
+		rules[i].Description = lo.Ternary[string](policyRule.Description != "", policyRule.Description, rule.Description)
+		rules[i].Path = lo.Ternary[string](policyRule.Path != "", policyRule.Path, rule.Path)
+		rules[i].Condition = lo.Ternary[string](policyRule.Condition != "", policyRule.Condition, rule.Condition)
+		rules[i].Severity = lo.Ternary[string](policyRule.Severity != "", policyRule.Severity, rule.Severity)
+
+		return rules, nil
+	}
+
+	if policyRule.Name == "" || policyRule.Path == "" || policyRule.Condition == "" {
+		return nil, errors.New("rules must have a name, a path and a condition")
+	}
+
+	policyRule.Severity = lo.Ternary[string](policyRule.Severity != "", policyRule.Severity, severityError)
+
+	return append(rules, policyRule), nil
+}
+
+// findPolicyValues returns the values of the manifest at the path of a rule. Path segments are manifest keys,
+// lists being selected with [pattern], where the pattern matches the name or key of their items. Only the
+// given environment is selected when not empty.
+func findPolicyValues(value reflect.Value, segments []string, valuePath string, environment string) ([]policyValue, error) {
+	if len(segments) == 0 {
+		return []policyValue{{path: valuePath, value: value}}, nil
+	}
+
+	for value.Kind() == reflect.Pointer {
+		if value.IsNil() {
+			return nil, nil
+		}
+
+		value = value.Elem()
+	}
+
+	match := policyPathSegment.FindStringSubmatch(segments[0])
+
+	if match == nil || value.Kind() != reflect.Struct {
+		return nil, errors.New(fmt.Sprintf("%s cannot be selected", segments[0]))
+	}
+
+	field, found := lo.Find[reflect.StructField](reflect.VisibleFields(value.Type()), func(field reflect.StructField) bool {
+		return manifestFieldName(field) == match[1]
+	})
+
+	if !found {
+		return nil, errors.New(fmt.Sprintf("%s is not a manifest key", match[1]))
+	}
+
+	fieldValue := value.FieldByIndex(field.Index)
+	fieldPath := joinManifestPath(valuePath, match[1])
+	isList := fieldValue.Kind() == reflect.Slice
+
+	if isList != (match[2] != "") {
+		return nil, errors.New(fmt.Sprintf("%s must be selected as %s[pattern] when it is a list only", match[1], match[1]))
+	}
+
+	if !isList {
+		return findPolicyValues(fieldValue, segments[1:], fieldPath, environment)
+	}
+
+	var values []policyValue
+
+	for i := 0; i < fieldValue.Len(); i++ {
+		name := policyItemName(fieldValue.Index(i))
+
+		if matched, err := path.Match(match[2], name); err != nil || !matched {
+			continue
+		}
+
+		if match[1] == "environments" && environment != "" && name != environment {
+			continue
+		}
+
+		itemValues, err := findPolicyValues(fieldValue.Index(i), segments[1:], fmt.Sprintf("%s[%d]", fieldPath, i), environment)
+
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+
+		values = append(values, itemValues...)
+	}
+
+	return values, nil
+}
+
+// policyItemName returns the name or key identifying an item of a list, or the item itself for scalars
+func policyItemName(item reflect.Value) string {
+	if item.Kind() != reflect.Struct {
+		return fmt.Sprint(item.Interface())
+	}
+
+	for _, field := range reflect.VisibleFields(item.Type()) {
+		if name := manifestFieldName(field); name == "name" || name == "key" {
+			return item.FieldByIndex(field.Index).String()
+		}
+	}
+
+	return ""
+}
+
+// checkCondition returns whether a value satisfies a condition, which uses the syntax of validator tags.
+// Unset values only fail the conditions requiring them.
+func checkCondition(validate *validator.Validate, value reflect.Value, condition string) (satisfied bool, err error) {
+	for value.Kind() == reflect.Pointer {
+		if value.IsNil() {
+			return !strings.Contains(condition, "required"), nil
+		}
+
+		value = value.Elem()
+	}
+
+	if strings.HasPrefix(condition, matchesCondition) {
+		expression, err := regexp.Compile(strings.TrimPrefix(condition, matchesCondition))
+
+		if err != nil {
+			return false, errors.WithStack(err)
+		}
+
+		return expression.MatchString(fmt.Sprint(value.Interface())), nil
+	}
+
+	// the validator panics on unknown conditions, which are reported as invalid rules
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = errors.New(fmt.Sprint(recovered))
+		}
+	}()
+
+	err = validate.Var(value.Interface(), condition)
+
+	if _, ok := err.(validator.ValidationErrors); ok {
+		return false, nil
+	}
+
+	return err == nil, errors.WithStack(err)
+}
+
+func formatPolicyValue(value reflect.Value) string {
+	for value.Kind() == reflect.Pointer {
+		if value.IsNil() {
+			return "(unset)"
+		}
+
+		value = value.Elem()
+	}
+
+	if value.Kind() == reflect.Struct || value.Kind() == reflect.Slice {
+		return ""
+	}
+
+	return fmt.Sprintf("%q", fmt.Sprint(value.Interface()))
+}
@@ -0,0 +1,163 @@
+package service
+
+import (
+	"github.com/getflight/flight/mocks"
+	"github.com/getflight/flight/models"
+	"github.com/pkg/errors"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+	"testing"
+)
+
+func TestLintService(t *testing.T) {
+	t.Run("CheckManifest with compliant manifest returns nil", func(t *testing.T) {
+		// given
+		lintService := LintService{
+			Configuration: getLintConfigurationMock(),
+			FileSystem:    getPolicyFileSystemMock(""),
+		}
+
+		// when
+		err := lintService.CheckManifest(getManifest(), "")
+
+		// then
+		assert.Nil(t, err)
+	})
+
+	t.Run("lintManifest reports built-in rules", func(t *testing.T) {
+		// given
+		manifest := getManifest()
+		manifest.Environments = append(manifest.Environments, models.ManifestEnvironment{
+			Name:      "Production",
+			Databases: []models.ManifestDatabase{{Name: "db", Driver: "mysql", MinCapacity: lo.ToPtr[int64](2)}},
+			Variables: []models.ManifestVariable{{Key: "STRIPE_SECRET", Value: "sk_live"}},
+		})
+
+		lintService := LintService{
+			Configuration: getLintConfigurationMock(),
+			FileSystem:    getPolicyFileSystemMock(""),
+		}
+
+		rules, _ := lintService.getRules()
+
+		// when
+		violations, err := lintService.lintManifest(manifest, "", rules)
+
+		// then
+		assert.Nil(t, err)
+		assert.Equal(t, []string{
+			"environments[1].variables[0].secret \"false\" violates plaintext-secret (eq=true): variables named *_SECRET must be secret",
+			"environments[1].name \"Production\" violates environment-name (matches=^[a-z][a-z0-9-]*$): environment names are made of lowercase letters, digits and dashes",
+		}, lo.Map[PolicyViolation, string](violations, func(violation PolicyViolation, _ int) string {
+			return violation.Error()
+		}))
+	})
+
+	t.Run("lintManifest only reports rules of given environment", func(t *testing.T) {
+		// given
+		manifest := getManifest()
+		manifest.Environments = append(manifest.Environments, models.ManifestEnvironment{
+			Name:      "production",
+			Databases: []models.ManifestDatabase{{Name: "db", Driver: "mysql"}},
+		})
+
+		lintService := LintService{
+			Configuration: getLintConfigurationMock(),
+			FileSystem:    getPolicyFileSystemMock(""),
+		}
+
+		rules, _ := lintService.getRules()
+
+		// when
+		devViolations, devErr := lintService.lintManifest(manifest, "dev", rules)
+		productionViolations, productionErr := lintService.lintManifest(manifest, "production", rules)
+
+		// then
+		assert.Nil(t, devErr)
+		assert.Nil(t, productionErr)
+		assert.Empty(t, devViolations)
+		assert.Equal(t, 1, len(productionViolations))
+		assert.Equal(t, "environments[1].databases[0].max_capacity (unset) violates production-database-capacity (required): production databases must set max_capacity to bound their cost", productionViolations[0].Error())
+	})
+
+	t.Run("CheckManifest applies rules of policy file", func(t *testing.T) {
+		// given
+		policy := `rules:
+  - name: memory-limit
+    description: functions use at most 1 GB
+    path: memory
+    condition: max=1024
+  - name: environment-name
+    condition: matches=^(dev|staging|production)$
+    severity: warning
+  - name: plaintext-password
+    severity: "off"
+`
+		manifest := getManifest()
+		manifest.Memory = lo.ToPtr[int64](2048)
+
+		lintService := LintService{
+			Configuration: getLintConfigurationMock(),
+			FileSystem:    getPolicyFileSystemMock(policy),
+		}
+
+		// when
+		err := lintService.CheckManifest(manifest, "")
+
+		// then
+		assert.EqualError(t, err, "1 policy violation(s) at error severity, fix them or change the severity of their rule in .flight-policy.yml")
+	})
+
+	t.Run("CheckManifest returns error when rule path is invalid", func(t *testing.T) {
+		// given
+		policy := "rules:\n  - name: bad\n    path: environments.name\n    condition: required\n"
+
+		lintService := LintService{
+			Configuration: getLintConfigurationMock(),
+			FileSystem:    getPolicyFileSystemMock(policy),
+		}
+
+		// when
+		err := lintService.CheckManifest(getManifest(), "")
+
+		// then
+		assert.EqualError(t, err, "rule bad has an invalid path environments.name: environments must be selected as environments[pattern] when it is a list only")
+	})
+
+	t.Run("CheckManifest returns error when rule condition is unknown", func(t *testing.T) {
+		// given
+		policy := "rules:\n  - name: bad\n    path: name\n    condition: shiny\n"
+
+		lintService := LintService{
+			Configuration: getLintConfigurationMock(),
+			FileSystem:    getPolicyFileSystemMock(policy),
+		}
+
+		// when
+		err := lintService.CheckManifest(getManifest(), "")
+
+		// then
+		assert.ErrorContains(t, err, "rule bad has an invalid condition shiny")
+	})
+}
+
+func getLintConfigurationMock() *mocks.ConfigurationMock {
+	configuration := &mocks.ConfigurationMock{}
+	configuration.On("GetManifestFile").Return("flight.yml")
+	configuration.On("GetManifestNode").Return(&yaml.Node{}, nil)
+
+	return configuration
+}
+
+func getPolicyFileSystemMock(policy string) *mocks.FileSystemMock {
+	fileSystemMock := &mocks.FileSystemMock{}
+
+	if policy == "" {
+		fileSystemMock.On("ReadFile", ".flight-policy.yml").Return([]byte{}, errors.New("not found"))
+	} else {
+		fileSystemMock.On("ReadFile", ".flight-policy.yml").Return([]byte(policy), nil)
+	}
+
+	return fileSystemMock
+}
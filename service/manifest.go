@@ -0,0 +1,190 @@
+package service
+
+import (
+	"bytes"
+	"github.com/getflight/flight/models"
+	"reflect"
+	"strconv"
+
+	"github.com/pkg/errors"
+	"github.com/samber/lo"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	triggerGateway  = "gateway"
+	triggerQueue    = "queue"
+	triggerSchedule = "schedule"
+)
+
+// isScheduleEnabled returns whether the schedule of a manifest resolved with resolveManifest fires, schedules
+// being enabled unless configured otherwise
+func isScheduleEnabled(manifest models.Manifest) bool {
+	return manifest.Trigger == triggerSchedule && manifest.Schedule != nil && (manifest.Schedule.Enabled == nil || *manifest.Schedule.Enabled)
+}
+
+// variableSize returns the size counted by lambda for an environment variable, which is the size of its key
+// and value in bytes
+func variableSize(variable models.ManifestVariable) int {
+	return len(variable.Key) + len(variable.Value)
+}
+
+// resolveManifest returns a copy of the manifest where the top level settings are replaced by the
+// overrides of the given environment
+func resolveManifest(manifest models.Manifest, environment string) models.Manifest {
+	manifestEnvironment, found := lo.Find[models.ManifestEnvironment](manifest.Environments, func(manifestEnvironment models.ManifestEnvironment) bool {
+		return manifestEnvironment.Name == environment
+	})
+
+	if !found {
+		return manifest
+	}
+
+	if manifestEnvironment.Memory != nil {
+		manifest.Memory = manifestEnvironment.Memory
+	}
+
+	if manifestEnvironment.Timeout != nil {
+		manifest.Timeout = manifestEnvironment.Timeout
+	}
+
+	if manifestEnvironment.EphemeralStorage != nil {
+		manifest.EphemeralStorage = manifestEnvironment.EphemeralStorage
+	}
+
+	if manifestEnvironment.Architecture != "" {
+		manifest.Architecture = manifestEnvironment.Architecture
+	}
+
+	if manifestEnvironment.Package != nil {
+		manifest.Package = resolvePackage(manifest.Package, manifestEnvironment.Package)
+	}
+
+	if manifest.Schedule != nil && manifestEnvironment.Schedule != nil {
+		// copy the schedule so that the overrides do not leak to other environments
+		schedule := *manifest.Schedule
+
+		if manifestEnvironment.Schedule.Expression != "" {
+			schedule.Expression = manifestEnvironment.Schedule.Expression
+		}
+
+		if manifestEnvironment.Schedule.Enabled != nil {
+			schedule.Enabled = manifestEnvironment.Schedule.Enabled
+		}
+
+		if manifestEnvironment.Schedule.Payload != "" {
+			schedule.Payload = manifestEnvironment.Schedule.Payload
+		}
+
+		manifest.Schedule = &schedule
+	}
+
+	return manifest
+}
+
+// resolvePackage returns the package of an environment, whose includes are added to the includes of the
+// project and whose other settings replace the settings of the project
+func resolvePackage(projectPackage *models.ManifestPackage, environmentPackage *models.ManifestPackage) *models.ManifestPackage {
+	resolved := models.ManifestPackage{}
+
+	if projectPackage != nil {
+		resolved = *projectPackage
+	}
+
+	if environmentPackage.Includes != nil {
+		var includes []string
+
+		if resolved.Includes != nil {
+			includes = append(includes, *resolved.Includes...)
+		}
+
+		includes = append(includes, *environmentPackage.Includes...)
+		resolved.Includes = &includes
+	}
+
+	if environmentPackage.Symlinks != "" {
+		resolved.Symlinks = environmentPackage.Symlinks
+	}
+
+	if environmentPackage.Bootstrap != "" {
+		resolved.Bootstrap = environmentPackage.Bootstrap
+	}
+
+	return &resolved
+}
+
+// newRuntime returns the function settings of a manifest resolved with resolveManifest. Unset settings
+// are left empty for the platform to apply its defaults.
+func newRuntime(manifest models.Manifest) models.Runtime {
+	return models.Runtime{
+		Memory:           manifest.Memory,
+		Timeout:          manifest.Timeout,
+		EphemeralStorage: manifest.EphemeralStorage,
+		Architecture:     manifest.Architecture,
+	}
+}
+
+// encodeManifest returns the manifest as yaml, using the manifest keys and leaving out the settings that are
+// not set, so that generated manifests only contain what differs from the defaults
+func encodeManifest(manifest models.Manifest) ([]byte, error) {
+	content := &bytes.Buffer{}
+	encoder := yaml.NewEncoder(content)
+	encoder.SetIndent(manifestIndent)
+
+	err := encoder.Encode(manifestNode(reflect.ValueOf(manifest)))
+
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return content.Bytes(), nil
+}
+
+// manifestNode returns the yaml node of a manifest value, which is nil for empty structs and slices. Fields
+// are left out when zero, except pointers which are only unset when nil, so that enabled: false is kept.
+func manifestNode(value reflect.Value) *yaml.Node {
+	switch value.Kind() {
+	case reflect.Pointer:
+		if value.IsNil() {
+			return nil
+		}
+
+		return manifestNode(value.Elem())
+	case reflect.Struct:
+		node := &yaml.Node{Kind: yaml.MappingNode}
+
+		for _, field := range reflect.VisibleFields(value.Type()) {
+			name := manifestFieldName(field)
+
+			fieldValue := value.FieldByIndex(field.Index)
+
+			if name == "" || !field.IsExported() || (fieldValue.Kind() != reflect.Pointer && fieldValue.IsZero()) {
+				continue
+			}
+
+			if fieldNode := manifestNode(fieldValue); fieldNode != nil {
+				node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: name}, fieldNode)
+			}
+		}
+
+		return lo.Ternary[*yaml.Node](len(node.Content) > 0, node, nil)
+	case reflect.Slice:
+		node := &yaml.Node{Kind: yaml.SequenceNode}
+
+		for i := 0; i < value.Len(); i++ {
+			if itemNode := manifestNode(value.Index(i)); itemNode != nil {
+				node.Content = append(node.Content, itemNode)
+			}
+		}
+
+		return lo.Ternary[*yaml.Node](len(node.Content) > 0, node, nil)
+	case reflect.String:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value.String()}
+	case reflect.Int, reflect.Int64:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!int", Value: strconv.FormatInt(value.Int(), 10)}
+	case reflect.Bool:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: strconv.FormatBool(value.Bool())}
+	}
+
+	return nil
+}
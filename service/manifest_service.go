@@ -0,0 +1,90 @@
+package service
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/getflight/flight/context"
+	"github.com/getflight/flight/helpers"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	manifestIndent = 2
+)
+
+type ManifestServiceType interface {
+	Migrate() error
+}
+
+type ManifestService struct {
+	Configuration context.ConfigurationType
+	FileSystem    helpers.FileSystemType
+}
+
+// Migrate rewrites the manifest file in the current manifest version, keeping its comments
+func (s *ManifestService) Migrate() error {
+	err := s.Configuration.Init()
+
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	file := s.Configuration.GetManifestFile()
+
+	if !context.IsYamlManifest(file) {
+		return errors.New(fmt.Sprintf("%s can not be migrated in place, only yaml manifests can", file))
+	}
+
+	content, err := s.FileSystem.ReadFile(file)
+
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	document := &yaml.Node{}
+	err = yaml.Unmarshal(content, document)
+
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	version, warnings, err := context.MigrateManifest(document)
+
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	if version == context.ManifestVersion {
+		log.Infof("%s is already at version %d", file, context.ManifestVersion)
+
+		return nil
+	}
+
+	migrated := &bytes.Buffer{}
+	encoder := yaml.NewEncoder(migrated)
+	encoder.SetIndent(manifestIndent)
+
+	err = encoder.Encode(document)
+
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	err = s.FileSystem.WriteFile(file, migrated.Bytes(), 0644)
+
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	for _, warning := range warnings {
+		log.Infof("migrated: %s", warning)
+	}
+
+	log.Infof("%s migrated from version %d to version %d", file, version, context.ManifestVersion)
+
+	return nil
+}
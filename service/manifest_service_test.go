@@ -0,0 +1,77 @@
+package service
+
+import (
+	"github.com/getflight/flight/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"io/fs"
+	"testing"
+)
+
+func TestManifestService(t *testing.T) {
+	t.Run("Migrate rewrites manifest in current version", func(t *testing.T) {
+		// given
+		configuration := &mocks.ConfigurationMock{}
+		configuration.On("Init").Return(nil)
+		configuration.On("GetManifestFile").Return("flight.yml")
+
+		fileSystemMock := &mocks.FileSystemMock{}
+		fileSystemMock.On("ReadFile", "flight.yml").Return([]byte("name: test # project\nfiles:\n  - static\n"), nil)
+		fileSystemMock.On("WriteFile", "flight.yml", []byte("version: 2\nname: test # project\npackage:\n  includes:\n    - static\n"), fs.FileMode(0644)).Return(nil)
+
+		manifestService := ManifestService{
+			Configuration: configuration,
+			FileSystem:    fileSystemMock,
+		}
+
+		// when
+		err := manifestService.Migrate()
+
+		// then
+		assert.Nil(t, err)
+		fileSystemMock.AssertExpectations(t)
+	})
+
+	t.Run("Migrate with current version does not rewrite manifest", func(t *testing.T) {
+		// given
+		configuration := &mocks.ConfigurationMock{}
+		configuration.On("Init").Return(nil)
+		configuration.On("GetManifestFile").Return("flight.yml")
+
+		fileSystemMock := &mocks.FileSystemMock{}
+		fileSystemMock.On("ReadFile", "flight.yml").Return([]byte("version: 2\nname: test\n"), nil)
+
+		manifestService := ManifestService{
+			Configuration: configuration,
+			FileSystem:    fileSystemMock,
+		}
+
+		// when
+		err := manifestService.Migrate()
+
+		// then
+		assert.Nil(t, err)
+		fileSystemMock.AssertNotCalled(t, "WriteFile", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Migrate with toml manifest returns error", func(t *testing.T) {
+		// given
+		configuration := &mocks.ConfigurationMock{}
+		configuration.On("Init").Return(nil)
+		configuration.On("GetManifestFile").Return("flight.toml")
+
+		fileSystemMock := &mocks.FileSystemMock{}
+
+		manifestService := ManifestService{
+			Configuration: configuration,
+			FileSystem:    fileSystemMock,
+		}
+
+		// when
+		err := manifestService.Migrate()
+
+		// then
+		assert.EqualError(t, err, "flight.toml can not be migrated in place, only yaml manifests can")
+		fileSystemMock.AssertNotCalled(t, "WriteFile", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,96 @@
+package service
+
+import (
+	"github.com/getflight/flight/models"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestManifest(t *testing.T) {
+	t.Run("resolveManifest replaces settings with environment overrides", func(t *testing.T) {
+		// given
+		memory := int64(256)
+		timeout := int64(30)
+		environmentMemory := int64(1024)
+
+		manifest := getManifest()
+		manifest.Memory = &memory
+		manifest.Timeout = &timeout
+		manifest.Architecture = "x86_64"
+		manifest.Environments[0].Memory = &environmentMemory
+		manifest.Environments[0].Architecture = "arm64"
+
+		// when
+		result := resolveManifest(manifest, "dev")
+
+		// then
+		assert.Equal(t, environmentMemory, *result.Memory)
+		assert.Equal(t, timeout, *result.Timeout)
+		assert.Nil(t, result.EphemeralStorage)
+		assert.Equal(t, "arm64", result.Architecture)
+		assert.Equal(t, memory, *manifest.Memory)
+	})
+
+	t.Run("resolveManifest merges environment schedule", func(t *testing.T) {
+		// given
+		enabled := false
+
+		manifest := getManifest()
+		manifest.Trigger = "schedule"
+		manifest.Schedule = &models.ManifestSchedule{Expression: "rate(1 hour)", Payload: "{}"}
+		manifest.Environments[0].Schedule = &models.ManifestEnvironmentSchedule{Expression: "rate(2 hours)", Enabled: &enabled}
+
+		// when
+		result := resolveManifest(manifest, "dev")
+
+		// then
+		assert.Equal(t, "rate(2 hours)", result.Schedule.Expression)
+		assert.Equal(t, "{}", result.Schedule.Payload)
+		assert.False(t, isScheduleEnabled(result))
+		assert.Equal(t, "rate(1 hour)", manifest.Schedule.Expression)
+		assert.True(t, isScheduleEnabled(manifest))
+	})
+
+	t.Run("resolveManifest adds environment includes to project includes", func(t *testing.T) {
+		// given
+		manifest := getManifest()
+		manifest.Environments[0].Package = &models.ManifestPackage{Includes: &[]string{"configs/dev.yml:config.yml"}, Symlinks: "preserve", Bootstrap: "bootstrap.sh"}
+
+		// when
+		result := resolveManifest(manifest, "dev")
+
+		// then
+		assert.Equal(t, []string{"file1", "file2", "file3", "configs/dev.yml:config.yml"}, *result.Package.Includes)
+		assert.Equal(t, "preserve", result.Package.Symlinks)
+		assert.Equal(t, "bootstrap.sh", result.Package.Bootstrap)
+		assert.Equal(t, []string{"file1", "file2", "file3"}, *manifest.Package.Includes)
+		assert.Equal(t, "", manifest.Package.Symlinks)
+	})
+
+	t.Run("resolveManifest with unknown environment returns manifest", func(t *testing.T) {
+		// given
+		manifest := getManifest()
+
+		// when
+		result := resolveManifest(manifest, "test")
+
+		// then
+		assert.Equal(t, manifest, result)
+	})
+
+	t.Run("newRuntime returns manifest settings", func(t *testing.T) {
+		// given
+		memory := int64(512)
+		manifest := getManifest()
+		manifest.Memory = &memory
+		manifest.Architecture = "arm64"
+
+		// when
+		runtime := newRuntime(manifest)
+
+		// then
+		assert.Equal(t, memory, *runtime.Memory)
+		assert.Nil(t, runtime.Timeout)
+		assert.Equal(t, "arm64", runtime.Architecture)
+	})
+}
@@ -0,0 +1,80 @@
+package service
+
+import (
+	"fmt"
+	"github.com/getflight/flight/context"
+	"github.com/getflight/flight/helpers"
+	"github.com/getflight/flight/models"
+
+	"github.com/pkg/errors"
+
+	log "github.com/sirupsen/logrus"
+)
+
+type PackageServiceType interface {
+	Package(environment string, output string) error
+}
+
+// PackageService builds the artifact zip without deploying it, the zip being identical to the one flight
+// deploy uploads
+type PackageService struct {
+	Configuration     context.ConfigurationType
+	FileHelper        helpers.FileHelperType
+	FileSystem        helpers.FileSystemType
+	SigningHelper     helpers.SigningHelperType
+	ValidationService ValidationServiceType
+}
+
+// Package writes the artifact zip to the output file and keeps a copy in the artifact cache, along with its
+// signature when the user has a signing key. The manifest is validated for the environment when given, and
+// resolved for it as flight deploy does.
+func (s *PackageService) Package(environment string, output string) error {
+	err := s.Configuration.Init()
+
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	manifest, err := s.Configuration.GetManifest()
+
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	err = s.ValidationService.ValidateManifest(manifest, environment)
+
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	log.Info("packaging artifact")
+	content, err := s.FileHelper.Package(resolveManifest(manifest, environment))
+
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	digest := helpers.ArtifactDigest(content)
+	signature, keyID, err := signArtifact(s.SigningHelper, manifest, environment, digest)
+
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	err = s.FileSystem.WriteFile(output, []byte(content), 0644)
+
+	if err != nil {
+		return errors.Wrap(err, fmt.Sprintf("failed to write %s", output))
+	}
+
+	log.Infof("%s written, %d bytes, %s", output, len(content), digest)
+
+	commitHash, commitMessage := helpers.GitCommit()
+	err = s.FileHelper.CacheArtifact(models.CachedArtifact{Project: manifest.Name, CommitHash: commitHash, CommitMessage: commitMessage, Signature: signature, KeyID: keyID}, content)
+
+	if err != nil {
+		log.Warnf("artifact not cached: %v", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,194 @@
+package service
+
+import (
+	"encoding/base64"
+	"github.com/getflight/flight/helpers"
+	"github.com/getflight/flight/mocks"
+	"github.com/getflight/flight/models"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"io/fs"
+	"testing"
+)
+
+func TestPackageService(t *testing.T) {
+	t.Run("Package writes artifact zip to output", func(t *testing.T) {
+		// given
+		configurationMock := getPackageConfigurationMock()
+
+		fileHelperMock := &mocks.FileHelperMock{}
+		fileHelperMock.On("Package", getManifest()).Return("zip", nil)
+		fileHelperMock.On("CacheArtifact", mock.MatchedBy(func(artifact models.CachedArtifact) bool {
+			return artifact.Project == "test" && artifact.ID == "" && artifact.Signature == ""
+		}), "zip").Return(nil)
+
+		fileSystemMock := &mocks.FileSystemMock{}
+		fileSystemMock.On("WriteFile", "build/main.zip", []byte("zip"), fs.FileMode(0644)).Return(nil)
+
+		packageService := PackageService{
+			Configuration:     configurationMock,
+			FileHelper:        fileHelperMock,
+			FileSystem:        fileSystemMock,
+			SigningHelper:     getUnsignedSigningHelperMock(),
+			ValidationService: getValidationServiceMock(),
+		}
+
+		// when
+		err := packageService.Package("dev", "build/main.zip")
+
+		// then
+		assert.Nil(t, err)
+		fileHelperMock.AssertExpectations(t)
+		fileSystemMock.AssertExpectations(t)
+	})
+
+	t.Run("Package with signing key caches signature of artifact", func(t *testing.T) {
+		// given
+		publicKey := "ed25519:" + base64.StdEncoding.EncodeToString(make([]byte, 32))
+
+		signingHelperMock := &mocks.SigningHelperMock{}
+		signingHelperMock.On("Sign", helpers.ArtifactDigest("zip")).Return("signature", publicKey, nil)
+
+		fileHelperMock := &mocks.FileHelperMock{}
+		fileHelperMock.On("Package", getManifest()).Return("zip", nil)
+		fileHelperMock.On("CacheArtifact", mock.MatchedBy(func(artifact models.CachedArtifact) bool {
+			return artifact.Signature == "signature" && artifact.KeyID == "66687aadf862bd77"
+		}), "zip").Return(nil)
+
+		fileSystemMock := &mocks.FileSystemMock{}
+		fileSystemMock.On("WriteFile", "main.zip", []byte("zip"), fs.FileMode(0644)).Return(nil)
+
+		packageService := PackageService{
+			Configuration:     getPackageConfigurationMock(),
+			FileHelper:        fileHelperMock,
+			FileSystem:        fileSystemMock,
+			SigningHelper:     signingHelperMock,
+			ValidationService: getValidationServiceMock(),
+		}
+
+		// when
+		err := packageService.Package("dev", "main.zip")
+
+		// then
+		assert.Nil(t, err)
+		signingHelperMock.AssertExpectations(t)
+		fileHelperMock.AssertExpectations(t)
+	})
+
+	t.Run("Package without key returns error when environment requires signatures", func(t *testing.T) {
+		// given
+		manifest := getManifest()
+		manifest.Environments[0].SigningKeys = []string{"ed25519:" + base64.StdEncoding.EncodeToString(make([]byte, 32))}
+
+		configurationMock := getLintConfigurationMock()
+		configurationMock.On("Init").Return(nil)
+		configurationMock.On("GetManifest").Return(manifest, nil)
+
+		fileHelperMock := &mocks.FileHelperMock{}
+		fileHelperMock.On("Package", mock.Anything).Return("zip", nil)
+
+		fileSystemMock := &mocks.FileSystemMock{}
+
+		packageService := PackageService{
+			Configuration:     configurationMock,
+			FileHelper:        fileHelperMock,
+			FileSystem:        fileSystemMock,
+			SigningHelper:     getUnsignedSigningHelperMock(),
+			ValidationService: getValidationServiceMock(),
+		}
+
+		// when
+		err := packageService.Package("dev", "main.zip")
+
+		// then
+		assert.ErrorContains(t, err, "environment dev only deploys signed artifacts")
+		fileSystemMock.AssertNotCalled(t, "WriteFile", mock.Anything, mock.Anything, mock.Anything)
+		fileHelperMock.AssertNotCalled(t, "CacheArtifact", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Package with cache error returns nil", func(t *testing.T) {
+		// given
+		fileHelperMock := &mocks.FileHelperMock{}
+		fileHelperMock.On("Package", getManifest()).Return("zip", nil)
+		fileHelperMock.On("CacheArtifact", mock.Anything, "zip").Return(errors.New("disk full"))
+
+		fileSystemMock := &mocks.FileSystemMock{}
+		fileSystemMock.On("WriteFile", "main.zip", mock.Anything, mock.Anything).Return(nil)
+
+		packageService := PackageService{
+			Configuration:     getPackageConfigurationMock(),
+			FileHelper:        fileHelperMock,
+			FileSystem:        fileSystemMock,
+			SigningHelper:     getUnsignedSigningHelperMock(),
+			ValidationService: getValidationServiceMock(),
+		}
+
+		// when
+		err := packageService.Package("", "main.zip")
+
+		// then
+		assert.Nil(t, err)
+		fileHelperMock.AssertExpectations(t)
+	})
+
+	t.Run("Package with unknown environment returns error", func(t *testing.T) {
+		// given
+		fileHelperMock := &mocks.FileHelperMock{}
+
+		validationServiceMock := &mocks.ValidationServiceMock{}
+		validationServiceMock.On("ValidateManifest", getManifest(), "prod").Return(errors.New("environment prod not found"))
+
+		packageService := PackageService{
+			Configuration:     getPackageConfigurationMock(),
+			FileHelper:        fileHelperMock,
+			FileSystem:        &mocks.FileSystemMock{},
+			ValidationService: validationServiceMock,
+		}
+
+		// when
+		err := packageService.Package("prod", "main.zip")
+
+		// then
+		assert.EqualError(t, err, "environment prod not found")
+		fileHelperMock.AssertNotCalled(t, "Package", mock.Anything)
+	})
+
+	t.Run("Package with failed write returns error", func(t *testing.T) {
+		// given
+		fileHelperMock := &mocks.FileHelperMock{}
+		fileHelperMock.On("Package", getManifest()).Return("zip", nil)
+
+		fileSystemMock := &mocks.FileSystemMock{}
+		fileSystemMock.On("WriteFile", "main.zip", mock.Anything, mock.Anything).Return(errors.New("read-only"))
+
+		packageService := PackageService{
+			Configuration:     getPackageConfigurationMock(),
+			FileHelper:        fileHelperMock,
+			FileSystem:        fileSystemMock,
+			SigningHelper:     getUnsignedSigningHelperMock(),
+			ValidationService: getValidationServiceMock(),
+		}
+
+		// when
+		err := packageService.Package("", "main.zip")
+
+		// then
+		assert.ErrorContains(t, err, "failed to write main.zip")
+	})
+}
+
+func getPackageConfigurationMock() *mocks.ConfigurationMock {
+	configurationMock := getLintConfigurationMock()
+	configurationMock.On("Init").Return(nil)
+	configurationMock.On("GetManifest").Return(getManifest(), nil)
+
+	return configurationMock
+}
+
+func getUnsignedSigningHelperMock() *mocks.SigningHelperMock {
+	signingHelperMock := &mocks.SigningHelperMock{}
+	signingHelperMock.On("Sign", mock.Anything).Return("", "", nil)
+
+	return signingHelperMock
+}
@@ -0,0 +1,315 @@
+package service
+
+import (
+	"fmt"
+	"github.com/getflight/flight/context"
+	"github.com/getflight/flight/helpers"
+	"github.com/getflight/flight/http"
+	"github.com/getflight/flight/models"
+	"strings"
+	"time"
+
+	"github.com/samber/lo"
+
+	"github.com/pkg/errors"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	defaultSetting    = "platform default"
+	scheduleFireTimes = 5
+	secretMask        = "****"
+)
+
+type PlanServiceType interface {
+	Plan(environment string) error
+}
+
+type PlanService struct {
+	Client            http.ClientType
+	Configuration     context.ConfigurationType
+	TokenHelper       helpers.TokenHelperType
+	ValidationService ValidationServiceType
+}
+
+// Plan prints what a deployment of the manifest to the environment would configure, without deploying
+func (s *PlanService) Plan(environment string) error {
+	err := s.Configuration.Init()
+
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	manifest, err := s.Configuration.GetManifest()
+
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	err = s.ValidationService.ValidateManifest(manifest, environment)
+
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	manifest = resolveManifest(manifest, environment)
+
+	manifestEnvironment, _ := lo.Find[models.ManifestEnvironment](manifest.Environments, func(manifestEnvironment models.ManifestEnvironment) bool {
+		return manifestEnvironment.Name == environment
+	})
+
+	log.Infof("plan for %s in %s", manifest.Name, environment)
+	log.Infof("trigger: %s", manifest.Trigger)
+
+	s.printRuntime(manifest)
+
+	err = s.printSchedule(manifest)
+
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	s.printQueue(manifest)
+
+	deployedEnvironment, err := s.getDeployedEnvironment(environment)
+
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	s.printDomains(manifestEnvironment)
+	s.printDatabases(manifestEnvironment, deployedEnvironment)
+	s.printVariables(manifestEnvironment)
+
+	return nil
+}
+
+// getDeployedEnvironment returns the environment as currently deployed, which is nil when it was never deployed
+// or when the user is not logged in
+func (s *PlanService) getDeployedEnvironment(environment string) (*models.Environment, error) {
+	if !s.TokenHelper.TokenExists() {
+		log.Info("login to compare the plan with the deployed environment")
+
+		return nil, nil
+	}
+
+	deployedEnvironment, err := findEnvironment(s.Client, s.TokenHelper, environment)
+
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return deployedEnvironment, nil
+}
+
+func (s *PlanService) printRuntime(manifest models.Manifest) {
+	log.Info("runtime:")
+	log.Infof("  memory: %s", formatSetting(manifest.Memory, "%d MB"))
+	log.Infof("  timeout: %s", formatSetting(manifest.Timeout, "%d s"))
+	log.Infof("  ephemeral storage: %s", formatSetting(manifest.EphemeralStorage, "%d MB"))
+	log.Infof("  architecture: %s", formatTextSetting(manifest.Architecture))
+}
+
+func (s *PlanService) printSchedule(manifest models.Manifest) error {
+	if manifest.Trigger != triggerSchedule {
+		return nil
+	}
+
+	log.Infof("schedule: %s", manifest.Schedule.Expression)
+
+	if !isScheduleEnabled(manifest) {
+		log.Info("  disabled in this environment")
+
+		return nil
+	}
+
+	if manifest.Schedule.Payload != "" {
+		log.Infof("  payload: %s", manifest.Schedule.Payload)
+	}
+
+	schedule, err := helpers.ParseSchedule(manifest.Schedule.Expression)
+
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	log.Infof("  next %d fire times (UTC):", scheduleFireTimes)
+
+	for _, fireTime := range schedule.Next(time.Now(), scheduleFireTimes) {
+		log.Infof("    %s", fireTime.UTC().Format("Mon 2006-01-02 15:04"))
+	}
+
+	return nil
+}
+
+func (s *PlanService) printQueue(manifest models.Manifest) {
+	if manifest.Trigger != triggerQueue {
+		return
+	}
+
+	queue := manifest.Queue
+
+	if queue == nil {
+		queue = &models.ManifestQueue{}
+	}
+
+	log.Info("queue:")
+	log.Infof("  batch size: %s", formatSetting(queue.BatchSize, "%d"))
+	log.Infof("  batch window: %s", formatSetting(queue.BatchWindow, "%d s"))
+	log.Infof("  visibility timeout: %s", formatSetting(queue.VisibilityTimeout, "%d s"))
+
+	if queue.DeadLetterQueue != nil && *queue.DeadLetterQueue {
+		log.Infof("  dead letter queue after %s receive(s)", formatSetting(queue.MaxReceiveCount, "%d"))
+	} else {
+		log.Info("  no dead letter queue")
+	}
+}
+
+func (s *PlanService) printDomains(manifestEnvironment models.ManifestEnvironment) {
+	if len(manifestEnvironment.Domains) == 0 {
+		return
+	}
+
+	log.Info("domains:")
+
+	for _, domain := range manifestEnvironment.Domains {
+		log.Infof("  %s", domain)
+	}
+}
+
+func (s *PlanService) printDatabases(manifestEnvironment models.ManifestEnvironment, deployedEnvironment *models.Environment) {
+	if len(manifestEnvironment.Databases) == 0 {
+		return
+	}
+
+	log.Info("databases:")
+
+	for _, database := range manifestEnvironment.Databases {
+		var deployedDatabase *models.Database
+
+		if deployedEnvironment != nil {
+			deployedDatabase, _ = lo.Find[*models.Database](lo.ToSlicePtr[models.Database](deployedEnvironment.Databases), func(deployedDatabase *models.Database) bool {
+				return strings.EqualFold(deployedDatabase.Name, database.Name)
+			})
+		}
+
+		changes := s.getDatabaseChanges(database, deployedDatabase)
+
+		switch {
+		case deployedDatabase == nil:
+			log.Infof("  + %s (%s) will be created", database.Name, database.Driver)
+		case len(changes) > 0:
+			log.Infof("  ~ %s (%s) will be updated", database.Name, database.Driver)
+		default:
+			log.Infof("  %s (%s) is unchanged", database.Name, database.Driver)
+		}
+
+		for _, change := range changes {
+			log.Infof("      %s", change)
+		}
+	}
+}
+
+// getDatabaseChanges lists the settings of the database that differ from the deployed database, or the settings
+// of the database when it is not deployed yet. Settings that are not set in the manifest are not listed.
+func (s *PlanService) getDatabaseChanges(database models.ManifestDatabase, deployedDatabase *models.Database) []string {
+	var changes []string
+	created := deployedDatabase == nil
+
+	addChange := func(name string, deployed string, planned string) {
+		switch {
+		case planned == defaultSetting:
+		case created:
+			changes = append(changes, fmt.Sprintf("%s: %s", name, planned))
+		case planned != deployed:
+			changes = append(changes, fmt.Sprintf("%s: %s -> %s", name, deployed, planned))
+		}
+	}
+
+	if created {
+		deployedDatabase = &models.Database{}
+	}
+
+	addChange("min capacity", formatSetting(deployedDatabase.MinCapacity, "%d"), formatSetting(database.MinCapacity, "%d"))
+	addChange("max capacity", formatSetting(deployedDatabase.MaxCapacity, "%d"), formatSetting(database.MaxCapacity, "%d"))
+	addChange("engine version", formatTextSetting(deployedDatabase.EngineVersion), formatTextSetting(database.EngineVersion))
+	addChange("auto pause", formatBoolSetting(deployedDatabase.AutoPause), formatBoolSetting(database.AutoPause))
+	addChange("backup retention", formatSetting(deployedDatabase.BackupRetention, "%d days"), formatSetting(database.BackupRetention, "%d days"))
+
+	return changes
+}
+
+func (s *PlanService) printVariables(manifestEnvironment models.ManifestEnvironment) {
+	if len(manifestEnvironment.Variables) == 0 {
+		return
+	}
+
+	log.Info("variables:")
+
+	for _, variable := range manifestEnvironment.Variables {
+		switch {
+		case helpers.IsEncrypted(variable.Value):
+			log.Infof("  %s=%s (encrypted)", variable.Key, secretMask)
+		case variable.Secret:
+			log.Infof("  %s=%s%s (secret)", variable.Key, secretMask, formatVariableSource(variable.ValueFrom))
+		case variable.ValueFrom != nil:
+			log.Infof("  %s=%s", variable.Key, strings.TrimSpace(formatVariableSource(variable.ValueFrom)))
+		case strings.Contains(variable.Value, "\n"):
+			lines := strings.Split(variable.Value, "\n")
+			log.Infof("  %s=%s... (%d lines)", variable.Key, lines[0], len(lines))
+		default:
+			log.Infof("  %s=%s", variable.Key, variable.Value)
+		}
+	}
+
+	size := lo.SumBy[models.ManifestVariable, int](manifestEnvironment.Variables, variableSize)
+
+	if lo.ContainsBy[models.ManifestVariable](manifestEnvironment.Variables, func(variable models.ManifestVariable) bool {
+		return variable.ValueFrom != nil
+	}) {
+		log.Infof("  size: %d of %d bytes, without the values resolved at deploy time", size, maxVariablesSize)
+	} else {
+		log.Infof("  size: %d of %d bytes", size, maxVariablesSize)
+	}
+}
+
+// formatSetting formats an optional setting, unset settings being left to the platform defaults
+func formatSetting(value *int64, format string) string {
+	if value == nil {
+		return defaultSetting
+	}
+
+	return fmt.Sprintf(format, *value)
+}
+
+func formatTextSetting(value string) string {
+	if value == "" {
+		return defaultSetting
+	}
+
+	return value
+}
+
+func formatBoolSetting(value *bool) string {
+	if value == nil {
+		return defaultSetting
+	}
+
+	return fmt.Sprint(*value)
+}
+
+// formatVariableSource describes where the value of a variable is read from at deploy time, values are never
+// resolved by a plan
+func formatVariableSource(source *models.ManifestVariableSource) string {
+	switch {
+	case source == nil:
+		return ""
+	case source.Env != "":
+		return fmt.Sprintf(" from env %s", source.Env)
+	case source.File != "":
+		return fmt.Sprintf(" from file %s", source.File)
+	}
+
+	return fmt.Sprintf(" from command %q", source.Command)
+}
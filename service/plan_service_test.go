@@ -0,0 +1,217 @@
+package service
+
+import (
+	"github.com/getflight/flight/mocks"
+	"github.com/getflight/flight/models"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestPlanService(t *testing.T) {
+	t.Run("Plan prints environment settings", func(t *testing.T) {
+		// given
+		hook := test.NewGlobal()
+
+		configuration := &mocks.ConfigurationMock{}
+		configuration.On("Init").Return(nil)
+		configuration.On("GetManifest").Return(getManifest(), nil)
+
+		planService := PlanService{
+			Configuration:     configuration,
+			TokenHelper:       getLoggedOutTokenHelperMock(),
+			ValidationService: getValidationServiceMock(),
+		}
+
+		// when
+		err := planService.Plan("dev")
+
+		// then
+		assert.Nil(t, err)
+		assert.Contains(t, getLogs(hook), "trigger: queue")
+		assert.Contains(t, getLogs(hook), "db (mysql)")
+		assert.Contains(t, getLogs(hook), "var1=value1")
+		assert.Contains(t, getLogs(hook), "size: 20 of 4096 bytes")
+		configuration.AssertExpectations(t)
+	})
+
+	t.Run("Plan prints sources of variables without resolving them", func(t *testing.T) {
+		// given
+		hook := test.NewGlobal()
+
+		manifest := getManifest()
+		manifest.Environments[0].Variables = []models.ManifestVariable{
+			{Key: "DB_PASSWORD", Secret: true, ValueFrom: &models.ManifestVariableSource{Command: "pass show db"}},
+			{Key: "REGION", ValueFrom: &models.ManifestVariableSource{Env: "AWS_REGION"}},
+		}
+
+		configuration := &mocks.ConfigurationMock{}
+		configuration.On("Init").Return(nil)
+		configuration.On("GetManifest").Return(manifest, nil)
+
+		planService := PlanService{
+			Configuration:     configuration,
+			TokenHelper:       getLoggedOutTokenHelperMock(),
+			ValidationService: getValidationServiceMock(),
+		}
+
+		// when
+		err := planService.Plan("dev")
+
+		// then
+		assert.Nil(t, err)
+		assert.Contains(t, getLogs(hook), `DB_PASSWORD=**** from command "pass show db" (secret)`)
+		assert.Contains(t, getLogs(hook), "REGION=from env AWS_REGION")
+	})
+
+	t.Run("Plan with schedule trigger prints next fire times", func(t *testing.T) {
+		// given
+		hook := test.NewGlobal()
+
+		manifest := getManifest()
+		manifest.Trigger = "schedule"
+		manifest.Schedule = &models.ManifestSchedule{Expression: "cron(0 2 * * ? *)"}
+
+		configuration := &mocks.ConfigurationMock{}
+		configuration.On("Init").Return(nil)
+		configuration.On("GetManifest").Return(manifest, nil)
+
+		planService := PlanService{
+			Configuration:     configuration,
+			TokenHelper:       getLoggedOutTokenHelperMock(),
+			ValidationService: getValidationServiceMock(),
+		}
+
+		// when
+		err := planService.Plan("dev")
+
+		// then
+		assert.Nil(t, err)
+		assert.Contains(t, getLogs(hook), "schedule: cron(0 2 * * ? *)")
+		assert.Equal(t, 5, strings.Count(getLogs(hook), " 02:00"))
+	})
+
+	t.Run("Plan with schedule disabled in environment prints disabled schedule", func(t *testing.T) {
+		// given
+		hook := test.NewGlobal()
+		enabled := false
+
+		manifest := getManifest()
+		manifest.Trigger = "schedule"
+		manifest.Schedule = &models.ManifestSchedule{Expression: "rate(1 hour)"}
+		manifest.Environments[0].Schedule = &models.ManifestEnvironmentSchedule{Enabled: &enabled}
+
+		configuration := &mocks.ConfigurationMock{}
+		configuration.On("Init").Return(nil)
+		configuration.On("GetManifest").Return(manifest, nil)
+
+		planService := PlanService{
+			Configuration:     configuration,
+			TokenHelper:       getLoggedOutTokenHelperMock(),
+			ValidationService: getValidationServiceMock(),
+		}
+
+		// when
+		err := planService.Plan("dev")
+
+		// then
+		assert.Nil(t, err)
+		assert.Contains(t, getLogs(hook), "disabled in this environment")
+	})
+
+	t.Run("Plan with invalid manifest returns error", func(t *testing.T) {
+		// given
+		manifest := getManifest()
+		manifest.Name = ""
+
+		configuration := &mocks.ConfigurationMock{}
+		configuration.On("Init").Return(nil)
+		configuration.On("GetManifest").Return(manifest, nil)
+
+		validationServiceMock := &mocks.ValidationServiceMock{}
+		validationServiceMock.On("ValidateManifest", manifest, "dev").Return(ValidationErrors{{Path: "name", Message: "is required"}})
+
+		planService := PlanService{
+			Configuration:     configuration,
+			TokenHelper:       getLoggedOutTokenHelperMock(),
+			ValidationService: validationServiceMock,
+		}
+
+		// when
+		err := planService.Plan("dev")
+
+		// then
+		var validationErrors ValidationErrors
+		assert.True(t, errors.As(err, &validationErrors))
+	})
+
+	t.Run("Plan prints database changes against deployed environment", func(t *testing.T) {
+		// given
+		hook := test.NewGlobal()
+		minCapacity := int64(1)
+		maxCapacity := int64(8)
+		plannedMaxCapacity := int64(16)
+		autoPause := true
+
+		manifest := getManifest()
+		manifest.Environments[0].Databases[0].MinCapacity = &minCapacity
+		manifest.Environments[0].Databases[0].MaxCapacity = &plannedMaxCapacity
+		manifest.Environments[0].Databases = append(manifest.Environments[0].Databases, models.ManifestDatabase{
+			Name:        "analytics",
+			Driver:      "postgresql",
+			MaxCapacity: &maxCapacity,
+			AutoPause:   &autoPause,
+		})
+
+		configuration := &mocks.ConfigurationMock{}
+		configuration.On("Init").Return(nil)
+		configuration.On("GetManifest").Return(manifest, nil)
+
+		tokenHelperMock := &mocks.TokenHelperMock{}
+		tokenHelperMock.On("TokenExists").Return(true)
+		tokenHelperMock.On("GetOrganisation").Return("1", nil)
+
+		clientMock := &mocks.ClientMock{}
+		clientMock.On("GetOrganisation", "1").Return(models.Organisation{Environments: []models.Environment{{ID: "2", Name: "dev"}}}, nil)
+		clientMock.On("GetEnvironment", "2").Return(models.Environment{
+			ID:        "2",
+			Name:      "dev",
+			Databases: []models.Database{{Name: "db", Driver: "mysql", MinCapacity: &minCapacity, MaxCapacity: &maxCapacity}},
+		}, nil)
+
+		planService := PlanService{
+			Client:            clientMock,
+			Configuration:     configuration,
+			TokenHelper:       tokenHelperMock,
+			ValidationService: getValidationServiceMock(),
+		}
+
+		// when
+		err := planService.Plan("dev")
+
+		// then
+		assert.Nil(t, err)
+		assert.Contains(t, getLogs(hook), "~ db (mysql) will be updated\n      max capacity: 8 -> 16\n")
+		assert.Contains(t, getLogs(hook), "+ analytics (postgresql) will be created\n      max capacity: 8\n      auto pause: true")
+		clientMock.AssertExpectations(t)
+	})
+}
+
+func getLoggedOutTokenHelperMock() *mocks.TokenHelperMock {
+	tokenHelperMock := &mocks.TokenHelperMock{}
+	tokenHelperMock.On("TokenExists").Return(false)
+
+	return tokenHelperMock
+}
+
+func getLogs(hook *test.Hook) string {
+	var messages []string
+
+	for _, entry := range hook.AllEntries() {
+		messages = append(messages, entry.Message)
+	}
+
+	return strings.Join(messages, "\n")
+}
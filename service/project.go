@@ -0,0 +1,68 @@
+package service
+
+import (
+	"fmt"
+	"github.com/getflight/flight/helpers"
+	"github.com/getflight/flight/http"
+	"github.com/getflight/flight/models"
+	"strings"
+
+	"github.com/samber/lo"
+
+	"github.com/pkg/errors"
+)
+
+// findProject returns the project named after the manifest in the given environment of the organisation
+func findProject(client http.ClientType, tokenHelper helpers.TokenHelperType, manifestEnvironment string, manifestProject string) (*models.Project, error) {
+	environment, err := findEnvironment(client, tokenHelper, manifestEnvironment)
+
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	if environment == nil {
+		return nil, errors.WithStack(errors.New(fmt.Sprintf("environment not found in organisation: %s", manifestEnvironment)))
+	}
+
+	project, found := lo.Find[models.Project](environment.Projects, func(project models.Project) bool {
+		return strings.EqualFold(project.Name, manifestProject)
+	})
+
+	if !found {
+		return nil, errors.WithStack(errors.New(fmt.Sprintf("project not found in organisation: %s", manifestProject)))
+	}
+
+	return &project, nil
+}
+
+// findEnvironment returns the environment of the organisation with its projects and databases, or nil when
+// the environment was never deployed
+func findEnvironment(client http.ClientType, tokenHelper helpers.TokenHelperType, manifestEnvironment string) (*models.Environment, error) {
+	organisationId, err := tokenHelper.GetOrganisation()
+
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	organisation, err := client.GetOrganisation(organisationId)
+
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	environment, found := lo.Find[models.Environment](organisation.Environments, func(environment models.Environment) bool {
+		return strings.EqualFold(environment.Name, manifestEnvironment)
+	})
+
+	if !found {
+		return nil, nil
+	}
+
+	environment, err = client.GetEnvironment(environment.ID)
+
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return &environment, nil
+}
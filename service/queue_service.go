@@ -0,0 +1,120 @@
+package service
+
+import (
+	"fmt"
+	"github.com/getflight/flight/context"
+	"github.com/getflight/flight/helpers"
+	"github.com/getflight/flight/http"
+	"github.com/getflight/flight/models"
+
+	"github.com/pkg/errors"
+
+	log "github.com/sirupsen/logrus"
+)
+
+type QueueServiceType interface {
+	Status(environment string) error
+	Redrive(environment string) error
+}
+
+type QueueService struct {
+	Client        http.ClientType
+	Configuration context.ConfigurationType
+	TokenHelper   helpers.TokenHelperType
+}
+
+// Status prints the depth of the queue triggering the project and of its dead letter queue
+func (s *QueueService) Status(environment string) error {
+	project, err := s.getQueueProject(environment)
+
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	queue, err := s.Client.GetQueue(project.ID)
+
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	log.Infof("queue %s", queue.Name)
+	log.Infof("  messages available: %d", queue.Messages)
+	log.Infof("  messages in flight: %d", queue.MessagesInFlight)
+	log.Infof("  messages delayed: %d", queue.MessagesDelayed)
+
+	if queue.DeadLetterQueueName == "" {
+		log.Info("no dead letter queue configured")
+
+		return nil
+	}
+
+	log.Infof("dead letter queue %s", queue.DeadLetterQueueName)
+	log.Infof("  messages available: %d", queue.DeadLetterQueueMessages)
+
+	return nil
+}
+
+// Redrive moves the messages of the dead letter queue back to the queue triggering the project
+func (s *QueueService) Redrive(environment string) error {
+	project, err := s.getQueueProject(environment)
+
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	queue, err := s.Client.GetQueue(project.ID)
+
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	if queue.DeadLetterQueueName == "" {
+		return errors.New(fmt.Sprintf("queue %s has no dead letter queue, enable queue.dead_letter_queue in flight.yml and deploy", queue.Name))
+	}
+
+	if queue.DeadLetterQueueMessages == 0 {
+		log.Infof("dead letter queue %s is empty", queue.DeadLetterQueueName)
+
+		return nil
+	}
+
+	redrive, err := s.Client.RedriveQueue(project.ID)
+
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	log.Infof("redriving %d message(s) from %s to %s", redrive.Messages, queue.DeadLetterQueueName, queue.Name)
+
+	return nil
+}
+
+func (s *QueueService) getQueueProject(environment string) (*models.Project, error) {
+	if !s.TokenHelper.TokenExists() {
+		return nil, errors.New("token not found, login to inspect queues")
+	}
+
+	err := s.Configuration.Init()
+
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	manifest, err := s.Configuration.GetManifest()
+
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	if manifest.Trigger != triggerQueue {
+		return nil, errors.New(fmt.Sprintf("project %s is triggered by %s, not by a queue", manifest.Name, manifest.Trigger))
+	}
+
+	project, err := findProject(s.Client, s.TokenHelper, environment, manifest.Name)
+
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return project, nil
+}
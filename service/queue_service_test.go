@@ -0,0 +1,160 @@
+package service
+
+import (
+	"github.com/getflight/flight/mocks"
+	"github.com/getflight/flight/models"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestQueueService(t *testing.T) {
+	t.Run("Status prints queue and dead letter queue depth", func(t *testing.T) {
+		// given
+		hook := test.NewGlobal()
+		queue := models.Queue{Name: "app-dev", Messages: 12, DeadLetterQueueName: "app-dev-dlq", DeadLetterQueueMessages: 3}
+
+		clientMock := getQueueClientMock()
+		clientMock.On("GetQueue", "3").Return(queue, nil)
+
+		queueService := QueueService{
+			Client:        clientMock,
+			Configuration: getQueueConfigurationMock("queue"),
+			TokenHelper:   getQueueTokenHelperMock(),
+		}
+
+		// when
+		err := queueService.Status("dev")
+
+		// then
+		assert.Nil(t, err)
+		assert.Contains(t, getLogs(hook), "messages available: 12")
+		assert.Contains(t, getLogs(hook), "dead letter queue app-dev-dlq\n  messages available: 3")
+		clientMock.AssertExpectations(t)
+	})
+
+	t.Run("Status with project not triggered by queue returns error", func(t *testing.T) {
+		// given
+		queueService := QueueService{
+			Configuration: getQueueConfigurationMock("gateway"),
+			TokenHelper:   getQueueTokenHelperMock(),
+		}
+
+		// when
+		err := queueService.Status("dev")
+
+		// then
+		assert.NotNil(t, err)
+	})
+
+	t.Run("Redrive redrives dead letter queue messages", func(t *testing.T) {
+		// given
+		queue := models.Queue{Name: "app-dev", DeadLetterQueueName: "app-dev-dlq", DeadLetterQueueMessages: 3}
+
+		clientMock := getQueueClientMock()
+		clientMock.On("GetQueue", "3").Return(queue, nil)
+		clientMock.On("RedriveQueue", "3").Return(models.QueueRedrive{Messages: 3}, nil)
+
+		queueService := QueueService{
+			Client:        clientMock,
+			Configuration: getQueueConfigurationMock("queue"),
+			TokenHelper:   getQueueTokenHelperMock(),
+		}
+
+		// when
+		err := queueService.Redrive("dev")
+
+		// then
+		assert.Nil(t, err)
+		clientMock.AssertExpectations(t)
+	})
+
+	t.Run("Redrive with empty dead letter queue does not redrive", func(t *testing.T) {
+		// given
+		queue := models.Queue{Name: "app-dev", DeadLetterQueueName: "app-dev-dlq"}
+
+		clientMock := getQueueClientMock()
+		clientMock.On("GetQueue", "3").Return(queue, nil)
+
+		queueService := QueueService{
+			Client:        clientMock,
+			Configuration: getQueueConfigurationMock("queue"),
+			TokenHelper:   getQueueTokenHelperMock(),
+		}
+
+		// when
+		err := queueService.Redrive("dev")
+
+		// then
+		assert.Nil(t, err)
+		clientMock.AssertNotCalled(t, "RedriveQueue", "3")
+	})
+
+	t.Run("Redrive without dead letter queue returns error", func(t *testing.T) {
+		// given
+		clientMock := getQueueClientMock()
+		clientMock.On("GetQueue", "3").Return(models.Queue{Name: "app-dev"}, nil)
+
+		queueService := QueueService{
+			Client:        clientMock,
+			Configuration: getQueueConfigurationMock("queue"),
+			TokenHelper:   getQueueTokenHelperMock(),
+		}
+
+		// when
+		err := queueService.Redrive("dev")
+
+		// then
+		assert.NotNil(t, err)
+	})
+
+	t.Run("Redrive with client error returns error", func(t *testing.T) {
+		// given
+		clientMock := getQueueClientMock()
+		clientMock.On("GetQueue", "3").Return(models.Queue{}, errors.New("test error"))
+
+		queueService := QueueService{
+			Client:        clientMock,
+			Configuration: getQueueConfigurationMock("queue"),
+			TokenHelper:   getQueueTokenHelperMock(),
+		}
+
+		// when
+		err := queueService.Redrive("dev")
+
+		// then
+		assert.NotNil(t, err)
+	})
+}
+
+func getQueueConfigurationMock(trigger string) *mocks.ConfigurationMock {
+	manifest := getManifest()
+	manifest.Trigger = trigger
+
+	configuration := &mocks.ConfigurationMock{}
+	configuration.On("Init").Return(nil)
+	configuration.On("GetManifest").Return(manifest, nil)
+
+	return configuration
+}
+
+func getQueueTokenHelperMock() *mocks.TokenHelperMock {
+	tokenHelperMock := &mocks.TokenHelperMock{}
+	tokenHelperMock.On("TokenExists").Return(true)
+	tokenHelperMock.On("GetOrganisation").Return("1", nil)
+
+	return tokenHelperMock
+}
+
+// getQueueClientMock returns a client finding project 3 named after the test manifest in environment dev
+func getQueueClientMock() *mocks.ClientMock {
+	organisation := models.Organisation{ID: "1", Environments: []models.Environment{{ID: "2", Name: "dev"}}}
+	environment := models.Environment{ID: "2", Name: "dev", Projects: []models.Project{{ID: "3", Name: "test"}}}
+
+	clientMock := &mocks.ClientMock{}
+	clientMock.On("GetOrganisation", "1").Return(organisation, nil)
+	clientMock.On("GetEnvironment", "2").Return(environment, nil)
+
+	return clientMock
+}
@@ -0,0 +1,204 @@
+package service
+
+import (
+	"encoding/json"
+	"github.com/getflight/flight/models"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	schemaDraft = "http://json-schema.org/draft-07/schema#"
+	schemaTitle = "flight.yml"
+)
+
+type SchemaServiceType interface {
+	GetSchema() (string, error)
+}
+
+type SchemaService struct {
+}
+
+// jsonSchema is the subset of the json schema specification needed to describe the manifest
+type jsonSchema struct {
+	Schema               string                 `json:"$schema,omitempty"`
+	Title                string                 `json:"title,omitempty"`
+	Ref                  string                 `json:"$ref,omitempty"`
+	Type                 string                 `json:"type,omitempty"`
+	Properties           map[string]*jsonSchema `json:"properties,omitempty"`
+	Required             []string               `json:"required,omitempty"`
+	AdditionalProperties *bool                  `json:"additionalProperties,omitempty"`
+	Items                *jsonSchema            `json:"items,omitempty"`
+	Enum                 []string               `json:"enum,omitempty"`
+	MinLength            *int64                 `json:"minLength,omitempty"`
+	MaxLength            *int64                 `json:"maxLength,omitempty"`
+	MinItems             *int64                 `json:"minItems,omitempty"`
+	MaxItems             *int64                 `json:"maxItems,omitempty"`
+	Minimum              *int64                 `json:"minimum,omitempty"`
+	Maximum              *int64                 `json:"maximum,omitempty"`
+	ExclusiveMinimum     *int64                 `json:"exclusiveMinimum,omitempty"`
+	ExclusiveMaximum     *int64                 `json:"exclusiveMaximum,omitempty"`
+	Definitions          map[string]*jsonSchema `json:"definitions,omitempty"`
+}
+
+// GetSchema returns the json schema of the manifest. The schema is generated from the manifest types and
+// their validate tags, so it always matches the validation done by ValidationService.
+func (s *SchemaService) GetSchema() (string, error) {
+	definitions := map[string]*jsonSchema{}
+
+	schema := s.structSchema(reflect.TypeOf(models.Manifest{}), definitions)
+	schema.Schema = schemaDraft
+	schema.Title = schemaTitle
+	schema.Definitions = definitions
+
+	content, err := json.MarshalIndent(schema, "", "  ")
+
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+
+	return string(content), nil
+}
+
+// typeSchema returns the schema of a type constrained by the given validate tag. Structs are added to the
+// definitions and referenced.
+func (s *SchemaService) typeSchema(t reflect.Type, tag string, definitions map[string]*jsonSchema) *jsonSchema {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	// constraints after dive apply to the items of a slice
+	var constraints []string
+	var itemConstraints []string
+
+	for i, constraint := range strings.Split(tag, ",") {
+		if constraint == "dive" {
+			itemConstraints = strings.Split(tag, ",")[i+1:]
+
+			break
+		}
+
+		constraints = append(constraints, constraint)
+	}
+
+	schema := &jsonSchema{}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		if _, found := definitions[t.Name()]; !found {
+			// reserve the definition first so recursive types reference it instead of looping
+			definitions[t.Name()] = nil
+			definitions[t.Name()] = s.structSchema(t, definitions)
+		}
+
+		return &jsonSchema{Ref: "#/definitions/" + t.Name()}
+	case reflect.Slice, reflect.Array:
+		schema.Type = "array"
+		schema.Items = s.typeSchema(t.Elem(), strings.Join(itemConstraints, ","), definitions)
+	case reflect.Map:
+		schema.Type = "object"
+	case reflect.String:
+		schema.Type = "string"
+	case reflect.Bool:
+		schema.Type = "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		schema.Type = "integer"
+	case reflect.Float32, reflect.Float64:
+		schema.Type = "number"
+	}
+
+	for _, constraint := range constraints {
+		name, param, _ := strings.Cut(constraint, "=")
+		s.applyConstraint(schema, name, param)
+	}
+
+	return schema
+}
+
+func (s *SchemaService) structSchema(t reflect.Type, definitions map[string]*jsonSchema) *jsonSchema {
+	additionalProperties := false
+	schema := &jsonSchema{
+		Type:                 "object",
+		Properties:           map[string]*jsonSchema{},
+		AdditionalProperties: &additionalProperties,
+	}
+
+	for _, field := range reflect.VisibleFields(t) {
+		name := manifestFieldName(field)
+
+		if name == "" || !field.IsExported() {
+			continue
+		}
+
+		tag := field.Tag.Get("validate")
+		schema.Properties[name] = s.typeSchema(field.Type, tag, definitions)
+
+		if strings.HasPrefix(tag, "required,") || tag == "required" {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+
+	return schema
+}
+
+// applyConstraint translates a validate tag to its json schema keyword, depending on the type it applies to.
+// Tags without an equivalent, such as cross field validations, are left to ValidationService.
+func (s *SchemaService) applyConstraint(schema *jsonSchema, name string, param string) {
+	switch name {
+	case "oneof":
+		schema.Enum = strings.Fields(param)
+
+		return
+	case "required":
+		// required rejects empty strings, which json schema only does through a minimum length
+		if schema.Type == "string" && schema.MinLength == nil {
+			minLength := int64(1)
+			schema.MinLength = &minLength
+		}
+
+		return
+	}
+
+	value, err := strconv.ParseInt(param, 10, 64)
+
+	if err != nil {
+		return
+	}
+
+	switch schema.Type {
+	case "string":
+		switch name {
+		case "min", "gte":
+			schema.MinLength = &value
+		case "max", "lte":
+			schema.MaxLength = &value
+		case "len":
+			schema.MinLength = &value
+			schema.MaxLength = &value
+		}
+	case "array":
+		switch name {
+		case "min", "gte":
+			schema.MinItems = &value
+		case "gt":
+			value++
+			schema.MinItems = &value
+		case "max", "lte":
+			schema.MaxItems = &value
+		}
+	case "integer", "number":
+		switch name {
+		case "min", "gte":
+			schema.Minimum = &value
+		case "max", "lte":
+			schema.Maximum = &value
+		case "gt":
+			schema.ExclusiveMinimum = &value
+		case "lt":
+			schema.ExclusiveMaximum = &value
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package service
+
+import (
+	"encoding/json"
+	"github.com/getflight/flight/models"
+	"github.com/stretchr/testify/assert"
+	"reflect"
+	"testing"
+)
+
+func TestSchemaService(t *testing.T) {
+	t.Run("GetSchema returns schema with every manifest field", func(t *testing.T) {
+		// given
+		schemaService := SchemaService{}
+
+		// when
+		content, err := schemaService.GetSchema()
+
+		// then
+		assert.Nil(t, err)
+
+		schema := &jsonSchema{}
+		assert.Nil(t, json.Unmarshal([]byte(content), schema))
+
+		assertSchemaFields(t, schema, reflect.TypeOf(models.Manifest{}))
+
+		for _, manifestType := range []any{models.ManifestEnvironment{}, models.ManifestDatabase{}, models.ManifestVariable{}, models.ManifestSchedule{}, models.ManifestEnvironmentSchedule{}} {
+			definition, found := schema.Definitions[reflect.TypeOf(manifestType).Name()]
+			assert.True(t, found)
+			assertSchemaFields(t, definition, reflect.TypeOf(manifestType))
+		}
+	})
+
+	t.Run("GetSchema returns schema with validate constraints", func(t *testing.T) {
+		// given
+		schemaService := SchemaService{}
+
+		// when
+		content, err := schemaService.GetSchema()
+
+		// then
+		assert.Nil(t, err)
+
+		schema := &jsonSchema{}
+		assert.Nil(t, json.Unmarshal([]byte(content), schema))
+
+		assert.Equal(t, []string{"name", "trigger", "environments"}, schema.Required)
+		assert.Equal(t, []string{"gateway", "queue", "schedule"}, schema.Properties["trigger"].Enum)
+		assert.Equal(t, int64(256), *schema.Properties["name"].MaxLength)
+		assert.Equal(t, int64(1), *schema.Properties["environments"].MinItems)
+		assert.Equal(t, "#/definitions/ManifestEnvironment", schema.Properties["environments"].Items.Ref)
+		assert.Equal(t, []string{"mysql", "postgresql"}, schema.Definitions["ManifestDatabase"].Properties["driver"].Enum)
+	})
+}
+
+func assertSchemaFields(t *testing.T, schema *jsonSchema, manifestType reflect.Type) {
+	assert.Equal(t, "object", schema.Type)
+	assert.False(t, *schema.AdditionalProperties)
+
+	for _, name := range manifestFieldNames(manifestType) {
+		_, found := schema.Properties[name]
+		assert.True(t, found, "field %s of %s missing from schema", name, manifestType.Name())
+	}
+}
@@ -0,0 +1,277 @@
+package service
+
+import (
+	"fmt"
+	"github.com/getflight/flight/context"
+	"github.com/getflight/flight/helpers"
+	"github.com/getflight/flight/models"
+	"regexp"
+	"strings"
+
+	"github.com/samber/lo"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+
+	log "github.com/sirupsen/logrus"
+)
+
+var (
+	encryptedValuePattern = regexp.MustCompile(`ENC\[[A-Za-z0-9+/=]+\]`)
+)
+
+type SecretServiceType interface {
+	Encrypt(environment string, value string) (string, error)
+	Decrypt(value string) (string, error)
+	PublicKey() (string, error)
+	RotateKey() error
+}
+
+type SecretService struct {
+	Configuration context.ConfigurationType
+	FileSystem    helpers.FileSystemType
+	SecretHelper  helpers.SecretHelperType
+}
+
+// Encrypt encrypts a value for the recipients of the given environment, or for the key of the user when the
+// environment is empty or has no recipients
+func (s *SecretService) Encrypt(environment string, value string) (string, error) {
+	var recipients []string
+
+	if environment != "" {
+		manifestEnvironment, err := s.getManifestEnvironment(environment)
+
+		if err != nil {
+			return "", errors.WithStack(err)
+		}
+
+		recipients = manifestEnvironment.Recipients
+	}
+
+	if len(recipients) > 0 {
+		publicKey, err := s.SecretHelper.PublicKey()
+
+		if err != nil {
+			return "", errors.WithStack(err)
+		}
+
+		if !lo.Contains[string](recipients, publicKey) {
+			log.Warnf("your public key is not a recipient of environment %s, you will not be able to decrypt this value", environment)
+		}
+	}
+
+	encrypted, err := s.SecretHelper.Encrypt(value, recipients)
+
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+
+	return encrypted, nil
+}
+
+// Decrypt decrypts a value encrypted for the key of the user
+func (s *SecretService) Decrypt(value string) (string, error) {
+	decrypted, err := s.SecretHelper.Decrypt(strings.TrimSpace(value))
+
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+
+	return decrypted, nil
+}
+
+// PublicKey returns the public key of the user, to be added to the recipients of an environment
+func (s *SecretService) PublicKey() (string, error) {
+	publicKey, err := s.SecretHelper.PublicKey()
+
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+
+	return publicKey, nil
+}
+
+// RotateKey replaces the key of the user and encrypts the values of the manifest again for their recipients,
+// the previous public key of the user being replaced by the new one. Every value is encrypted again before the
+// key is replaced, so that a value failing to decrypt leaves both the key and the manifest unchanged. Values not
+// encrypted for the user are kept as they are.
+func (s *SecretService) RotateKey() error {
+	err := s.Configuration.Init()
+
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	file := s.Configuration.GetManifestFile()
+
+	if !context.IsYamlManifest(file) {
+		return errors.New(fmt.Sprintf("the secrets of %s can not be rotated in place, only yaml manifests can", file))
+	}
+
+	content, err := s.FileSystem.ReadFile(file)
+
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	previousPublicKey, err := s.SecretHelper.PublicKey()
+
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	privateKey, publicKey, err := s.SecretHelper.NewKey()
+
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	// Recipients are replaced first, as they are found by their position in the original manifest
+	manifest, err := replaceRecipient(string(content), previousPublicKey, publicKey)
+
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	values := lo.Uniq[string](encryptedValuePattern.FindAllString(manifest, -1))
+	encryptedValues := 0
+
+	for _, value := range values {
+		recipients, err := s.SecretHelper.Recipients(value)
+
+		if err != nil {
+			return errors.WithStack(err)
+		}
+
+		if !lo.Contains[string](recipients, previousPublicKey) {
+			log.Warnf("value at line %d of %s is not encrypted for your key, it is kept as is", strings.Count(manifest[:strings.Index(manifest, value)], "\n")+1, file)
+
+			continue
+		}
+
+		encrypted, err := s.reencrypt(value, recipients, previousPublicKey, publicKey)
+
+		if err != nil {
+			return errors.WithStack(err)
+		}
+
+		manifest = strings.ReplaceAll(manifest, value, encrypted)
+		encryptedValues++
+	}
+
+	err = s.SecretHelper.RotateKey(privateKey)
+
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	err = s.FileSystem.WriteFile(file, []byte(manifest), 0644)
+
+	if err != nil {
+		return errors.Wrap(err, fmt.Sprintf("key rotated but %s could not be written, its values can still be decrypted with the previous key until the next rotation", file))
+	}
+
+	log.Infof("encrypted %d value(s) of %s again for your new key", encryptedValues, file)
+	log.Infof("your new public key is %s, share it with the other recipients", publicKey)
+
+	return nil
+}
+
+func (s *SecretService) reencrypt(value string, recipients []string, previousPublicKey string, publicKey string) (string, error) {
+	decrypted, err := s.SecretHelper.Decrypt(value)
+
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+
+	recipients = lo.Map[string, string](recipients, func(recipient string, _ int) string {
+		if recipient == previousPublicKey {
+			return publicKey
+		}
+
+		return recipient
+	})
+
+	encrypted, err := s.SecretHelper.Encrypt(decrypted, recipients)
+
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+
+	return encrypted, nil
+}
+
+// replaceRecipient replaces a public key in the recipients of the environments. The lines of the recipients are
+// edited in place, so that the formatting and the comments of the manifest are kept.
+func replaceRecipient(content string, previousPublicKey string, publicKey string) (string, error) {
+	document := &yaml.Node{}
+	err := yaml.Unmarshal([]byte(content), document)
+
+	if err != nil {
+		return "", errors.Wrap(err, "manifest is not valid yaml")
+	}
+
+	root := document
+
+	if root.Kind == yaml.DocumentNode && len(root.Content) > 0 {
+		root = root.Content[0]
+	}
+
+	environments := mappingValue(root, "environments")
+
+	if environments == nil || environments.Kind != yaml.SequenceNode {
+		return content, nil
+	}
+
+	lines := strings.Split(content, "\n")
+
+	for _, environment := range environments.Content {
+		recipients := mappingValue(environment, "recipients")
+
+		if recipients == nil || recipients.Kind != yaml.SequenceNode {
+			continue
+		}
+
+		for _, recipient := range recipients.Content {
+			if recipient.Value != previousPublicKey || recipient.Line < 1 || recipient.Line > len(lines) {
+				continue
+			}
+
+			line := lines[recipient.Line-1]
+			column := lo.Clamp[int](recipient.Column-1, 0, len(line))
+			index := strings.Index(line[column:], previousPublicKey)
+
+			if index < 0 {
+				continue
+			}
+
+			lines[recipient.Line-1] = line[:column+index] + publicKey + line[column+index+len(previousPublicKey):]
+		}
+	}
+
+	return strings.Join(lines, "\n"), nil
+}
+
+func (s *SecretService) getManifestEnvironment(environment string) (*models.ManifestEnvironment, error) {
+	err := s.Configuration.Init()
+
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	manifest, err := s.Configuration.GetManifest()
+
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	manifestEnvironment, found := lo.Find[models.ManifestEnvironment](manifest.Environments, func(manifestEnvironment models.ManifestEnvironment) bool {
+		return manifestEnvironment.Name == environment
+	})
+
+	if !found {
+		return nil, errors.New(fmt.Sprintf("environment not found in manifest: %s", environment))
+	}
+
+	return &manifestEnvironment, nil
+}
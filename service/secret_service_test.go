@@ -0,0 +1,174 @@
+package service
+
+import (
+	"github.com/getflight/flight/mocks"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"io/fs"
+	"testing"
+)
+
+func TestSecretService(t *testing.T) {
+	t.Run("Encrypt with environment encrypts for recipients of environment", func(t *testing.T) {
+		// given
+		manifest := getManifest()
+		manifest.Environments[0].Recipients = []string{"key1", "key2"}
+
+		configuration := &mocks.ConfigurationMock{}
+		configuration.On("Init").Return(nil)
+		configuration.On("GetManifest").Return(manifest, nil)
+
+		secretHelperMock := &mocks.SecretHelperMock{}
+		secretHelperMock.On("PublicKey").Return("key1", nil)
+		secretHelperMock.On("Encrypt", "secret", []string{"key1", "key2"}).Return("ENC[secret]", nil)
+
+		secretService := SecretService{
+			Configuration: configuration,
+			SecretHelper:  secretHelperMock,
+		}
+
+		// when
+		encrypted, err := secretService.Encrypt("dev", "secret")
+
+		// then
+		assert.Nil(t, err)
+		assert.Equal(t, "ENC[secret]", encrypted)
+		secretHelperMock.AssertExpectations(t)
+	})
+
+	t.Run("Encrypt with unknown environment returns error", func(t *testing.T) {
+		// given
+		configuration := &mocks.ConfigurationMock{}
+		configuration.On("Init").Return(nil)
+		configuration.On("GetManifest").Return(getManifest(), nil)
+
+		secretService := SecretService{
+			Configuration: configuration,
+		}
+
+		// when
+		_, err := secretService.Encrypt("prod", "secret")
+
+		// then
+		assert.NotNil(t, err)
+	})
+
+	t.Run("Encrypt without environment encrypts for own key", func(t *testing.T) {
+		// given
+		secretHelperMock := &mocks.SecretHelperMock{}
+		secretHelperMock.On("Encrypt", "secret", []string(nil)).Return("ENC[secret]", nil)
+
+		secretService := SecretService{
+			SecretHelper: secretHelperMock,
+		}
+
+		// when
+		encrypted, err := secretService.Encrypt("", "secret")
+
+		// then
+		assert.Nil(t, err)
+		assert.Equal(t, "ENC[secret]", encrypted)
+		secretHelperMock.AssertExpectations(t)
+	})
+
+	t.Run("RotateKey encrypts values of manifest again for new key", func(t *testing.T) {
+		// given
+		content := "environments:\n  - name: dev\n    recipients:\n      - oldkey # own key\n      - otherkey\n    variables:\n      - value: ENC[b2xk]\n      - value: ENC[b2xk]\n"
+
+		configuration := &mocks.ConfigurationMock{}
+		configuration.On("Init").Return(nil)
+		configuration.On("GetManifestFile").Return("flight.yml")
+
+		fileSystemMock := &mocks.FileSystemMock{}
+		fileSystemMock.On("ReadFile", "flight.yml").Return([]byte(content), nil)
+		fileSystemMock.On("WriteFile", "flight.yml", []byte("environments:\n  - name: dev\n    recipients:\n      - newkey # own key\n      - otherkey\n    variables:\n      - value: ENC[bmV3]\n      - value: ENC[bmV3]\n"), fs.FileMode(0644)).Return(nil)
+
+		secretHelperMock := &mocks.SecretHelperMock{}
+		secretHelperMock.On("PublicKey").Return("oldkey", nil)
+		secretHelperMock.On("NewKey").Return("private", "newkey", nil)
+		secretHelperMock.On("RotateKey", "private").Return(nil)
+		secretHelperMock.On("Decrypt", "ENC[b2xk]").Return("secret", nil).Once()
+		secretHelperMock.On("Recipients", "ENC[b2xk]").Return([]string{"oldkey", "otherkey"}, nil).Once()
+		secretHelperMock.On("Encrypt", "secret", []string{"newkey", "otherkey"}).Return("ENC[bmV3]", nil).Once()
+
+		secretService := SecretService{
+			Configuration: configuration,
+			FileSystem:    fileSystemMock,
+			SecretHelper:  secretHelperMock,
+		}
+
+		// when
+		err := secretService.RotateKey()
+
+		// then
+		assert.Nil(t, err)
+		fileSystemMock.AssertExpectations(t)
+		secretHelperMock.AssertExpectations(t)
+	})
+
+	t.Run("RotateKey keeps values not encrypted for own key", func(t *testing.T) {
+		// given
+		content := "name: oldkey\nenvironments:\n  - name: dev\n    variables:\n      - value: ENC[b3RoZXI=]\n"
+
+		configuration := &mocks.ConfigurationMock{}
+		configuration.On("Init").Return(nil)
+		configuration.On("GetManifestFile").Return("flight.yml")
+
+		fileSystemMock := &mocks.FileSystemMock{}
+		fileSystemMock.On("ReadFile", "flight.yml").Return([]byte(content), nil)
+		fileSystemMock.On("WriteFile", "flight.yml", []byte(content), fs.FileMode(0644)).Return(nil)
+
+		secretHelperMock := &mocks.SecretHelperMock{}
+		secretHelperMock.On("PublicKey").Return("oldkey", nil)
+		secretHelperMock.On("NewKey").Return("private", "newkey", nil)
+		secretHelperMock.On("RotateKey", "private").Return(nil)
+		secretHelperMock.On("Recipients", "ENC[b3RoZXI=]").Return([]string{"otherkey"}, nil)
+
+		secretService := SecretService{
+			Configuration: configuration,
+			FileSystem:    fileSystemMock,
+			SecretHelper:  secretHelperMock,
+		}
+
+		// when
+		err := secretService.RotateKey()
+
+		// then
+		assert.Nil(t, err)
+		fileSystemMock.AssertExpectations(t)
+		secretHelperMock.AssertNotCalled(t, "Decrypt", mock.Anything)
+	})
+
+	t.Run("RotateKey with value failing to decrypt keeps key and manifest", func(t *testing.T) {
+		// given
+		content := "environments:\n  - name: dev\n    recipients:\n      - oldkey\n    variables:\n      - value: ENC[b2xk]\n"
+
+		configuration := &mocks.ConfigurationMock{}
+		configuration.On("Init").Return(nil)
+		configuration.On("GetManifestFile").Return("flight.yml")
+
+		fileSystemMock := &mocks.FileSystemMock{}
+		fileSystemMock.On("ReadFile", "flight.yml").Return([]byte(content), nil)
+
+		secretHelperMock := &mocks.SecretHelperMock{}
+		secretHelperMock.On("PublicKey").Return("oldkey", nil)
+		secretHelperMock.On("NewKey").Return("private", "newkey", nil)
+		secretHelperMock.On("Recipients", "ENC[b2xk]").Return([]string{"oldkey"}, nil)
+		secretHelperMock.On("Decrypt", "ENC[b2xk]").Return("", errors.New("failed to decrypt value"))
+
+		secretService := SecretService{
+			Configuration: configuration,
+			FileSystem:    fileSystemMock,
+			SecretHelper:  secretHelperMock,
+		}
+
+		// when
+		err := secretService.RotateKey()
+
+		// then
+		assert.EqualError(t, err, "failed to decrypt value")
+		secretHelperMock.AssertNotCalled(t, "RotateKey", mock.Anything)
+		fileSystemMock.AssertNotCalled(t, "WriteFile", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
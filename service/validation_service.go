@@ -0,0 +1,654 @@
+package service
+
+import (
+	"fmt"
+	"github.com/getflight/flight/context"
+	"github.com/getflight/flight/helpers"
+	"github.com/getflight/flight/models"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+
+	"github.com/samber/lo"
+
+	"github.com/pkg/errors"
+
+	"gopkg.in/yaml.v3"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	maxBatchSizeWithoutWindow = 10
+)
+
+var (
+	// databaseCapacities are the capacity units a serverless database scales between
+	databaseCapacities = []int64{1, 2, 4, 8, 16, 32, 64, 128, 256}
+)
+
+type ValidationServiceType interface {
+	Validate(environment string) error
+	ValidateManifest(manifest models.Manifest, environment string) error
+}
+
+type ValidationService struct {
+	Configuration context.ConfigurationType
+}
+
+// ValidationError describes a single problem found in the manifest. Line and Column are only set
+// when the manifest file could be read, Allowed and Suggestion only when they apply to the problem.
+type ValidationError struct {
+	File       string
+	Path       string
+	Line       int
+	Column     int
+	Message    string
+	Allowed    []string
+	Suggestion string
+}
+
+func (e ValidationError) Error() string {
+	var b strings.Builder
+
+	if e.Line > 0 {
+		b.WriteString(fmt.Sprintf("%s:%d:%d: ", e.File, e.Line, e.Column))
+	}
+
+	b.WriteString(fmt.Sprintf("%s %s", e.Path, e.Message))
+
+	if len(e.Allowed) > 0 {
+		b.WriteString(fmt.Sprintf(" (allowed: %s)", strings.Join(e.Allowed, ", ")))
+	}
+
+	if e.Suggestion != "" {
+		b.WriteString(fmt.Sprintf(", did you mean %q?", e.Suggestion))
+	}
+
+	return b.String()
+}
+
+// ValidationErrors holds every problem found in the manifest
+type ValidationErrors []ValidationError
+
+func (e ValidationErrors) Error() string {
+	messages := lo.Map[ValidationError, string](e, func(validationError ValidationError, _ int) string {
+		return "  " + validationError.Error()
+	})
+
+	return fmt.Sprintf("manifest is invalid, %d problem(s) found:\n%s", len(e), strings.Join(messages, "\n"))
+}
+
+// Validate reads the manifest from the configuration and reports every problem found in it
+func (s *ValidationService) Validate(environment string) error {
+	err := s.Configuration.Init()
+
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	manifest, err := s.Configuration.GetManifest()
+
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	err = s.ValidateManifest(manifest, environment)
+
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	log.Info("manifest is valid")
+
+	return nil
+}
+
+// ValidateManifest validates the manifest against its constraints and the given environment, which is
+// skipped when empty. The returned error is of type ValidationErrors and lists every problem found.
+func (s *ValidationService) ValidateManifest(manifest models.Manifest, environment string) error {
+	node := s.getManifestNode()
+
+	var validationErrors ValidationErrors
+
+	validationErrors = append(validationErrors, s.validateKeys(node, reflect.TypeOf(manifest), "")...)
+	validationErrors = append(validationErrors, s.validateStruct(manifest)...)
+	validationErrors = append(validationErrors, s.validateDatabases(manifest)...)
+	validationErrors = append(validationErrors, s.validateDomains(manifest)...)
+	validationErrors = append(validationErrors, s.validateVariables(manifest)...)
+
+	// the queue settings are only used, and only checked, when the function is triggered by its queue
+	if manifest.Trigger == triggerQueue {
+		validationErrors = append(validationErrors, s.validateQueue(manifest)...)
+	}
+
+	if environment != "" {
+		validationErrors = append(validationErrors, s.validateEnvironment(manifest, environment)...)
+	}
+
+	if len(validationErrors) == 0 {
+		return nil
+	}
+
+	file := "flight.yml"
+
+	if s.Configuration != nil && s.Configuration.GetManifestFile() != "" {
+		file = s.Configuration.GetManifestFile()
+	}
+
+	for i := range validationErrors {
+		validationErrors[i].File = file
+		validationErrors[i].Line, validationErrors[i].Column = findManifestPosition(node, validationErrors[i].Path)
+	}
+
+	sort.SliceStable(validationErrors, func(i, j int) bool {
+		return validationErrors[i].Line < validationErrors[j].Line
+	})
+
+	return errors.WithStack(validationErrors)
+}
+
+func (s *ValidationService) getManifestNode() *yaml.Node {
+	if s.Configuration == nil {
+		return nil
+	}
+
+	node, err := s.Configuration.GetManifestNode()
+
+	if err != nil {
+		log.Debugf("%+v", err)
+
+		return nil
+	}
+
+	return node
+}
+
+// validateKeys walks the yaml document alongside the manifest type and reports keys that do not match
+// any field, as those would otherwise be silently ignored
+func (s *ValidationService) validateKeys(node *yaml.Node, t reflect.Type, path string) ValidationErrors {
+	var validationErrors ValidationErrors
+
+	if node == nil {
+		return validationErrors
+	}
+
+	if node.Kind == yaml.DocumentNode || node.Kind == yaml.AliasNode {
+		if node.Kind == yaml.AliasNode {
+			return s.validateKeys(node.Alias, t, path)
+		}
+
+		if len(node.Content) == 0 {
+			return validationErrors
+		}
+
+		return s.validateKeys(node.Content[0], t, path)
+	}
+
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch {
+	case t.Kind() == reflect.Struct && node.Kind == yaml.MappingNode:
+		fieldNames := manifestFieldNames(t)
+
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key := node.Content[i].Value
+			fieldPath := joinManifestPath(path, key)
+
+			field, found := lo.Find[reflect.StructField](reflect.VisibleFields(t), func(field reflect.StructField) bool {
+				return strings.EqualFold(manifestFieldName(field), key)
+			})
+
+			if !found {
+				validationErrors = append(validationErrors, ValidationError{
+					Path:       fieldPath,
+					Message:    "is not a known field",
+					Allowed:    fieldNames,
+					Suggestion: suggest(key, fieldNames),
+				})
+
+				continue
+			}
+
+			validationErrors = append(validationErrors, s.validateKeys(node.Content[i+1], field.Type, fieldPath)...)
+		}
+	case t.Kind() == reflect.Slice && node.Kind == yaml.SequenceNode:
+		for i, item := range node.Content {
+			validationErrors = append(validationErrors, s.validateKeys(item, t.Elem(), fmt.Sprintf("%s[%d]", path, i))...)
+		}
+	}
+
+	return validationErrors
+}
+
+func (s *ValidationService) validateStruct(manifest models.Manifest) ValidationErrors {
+	var validationErrors ValidationErrors
+
+	err := newManifestValidator().Struct(manifest)
+
+	if err == nil {
+		return validationErrors
+	}
+
+	fieldErrors, ok := err.(validator.ValidationErrors)
+
+	if !ok {
+		return append(validationErrors, ValidationError{Message: err.Error()})
+	}
+
+	for _, fieldError := range fieldErrors {
+		validationErrors = append(validationErrors, newValidationError(fieldError))
+	}
+
+	return validationErrors
+}
+
+// validateQueue checks the queue settings that depend on each other or on the function settings
+func (s *ValidationService) validateQueue(manifest models.Manifest) ValidationErrors {
+	var validationErrors ValidationErrors
+	queue := manifest.Queue
+
+	if queue == nil {
+		return validationErrors
+	}
+
+	if queue.BatchSize != nil && *queue.BatchSize > maxBatchSizeWithoutWindow && (queue.BatchWindow == nil || *queue.BatchWindow == 0) {
+		validationErrors = append(validationErrors, ValidationError{
+			Path:    "queue.batch_window",
+			Message: fmt.Sprintf("is required when batch_size is greater than %d", maxBatchSizeWithoutWindow),
+		})
+	}
+
+	if queue.MaxReceiveCount != nil && (queue.DeadLetterQueue == nil || !*queue.DeadLetterQueue) {
+		validationErrors = append(validationErrors, ValidationError{
+			Path:    "queue.max_receive_count",
+			Message: "requires dead_letter_queue to be enabled",
+		})
+	}
+
+	if queue.VisibilityTimeout == nil {
+		return validationErrors
+	}
+
+	for _, manifestEnvironment := range manifest.Environments {
+		timeout := resolveManifest(manifest, manifestEnvironment.Name).Timeout
+
+		if timeout != nil && *queue.VisibilityTimeout < *timeout {
+			validationErrors = append(validationErrors, ValidationError{
+				Path:    "queue.visibility_timeout",
+				Message: fmt.Sprintf("must be at least the timeout of %d s in environment %s, got %d", *timeout, manifestEnvironment.Name, *queue.VisibilityTimeout),
+			})
+		}
+	}
+
+	return validationErrors
+}
+
+// validateDatabases checks that the capacity range of each database is ordered
+func (s *ValidationService) validateDatabases(manifest models.Manifest) ValidationErrors {
+	var validationErrors ValidationErrors
+
+	for i, manifestEnvironment := range manifest.Environments {
+		for j, database := range manifestEnvironment.Databases {
+			if database.MinCapacity != nil && database.MaxCapacity != nil && *database.MaxCapacity < *database.MinCapacity {
+				validationErrors = append(validationErrors, ValidationError{
+					Path:    fmt.Sprintf("environments[%d].databases[%d].max_capacity", i, j),
+					Message: fmt.Sprintf("must be at least min_capacity %d, got %d", *database.MinCapacity, *database.MaxCapacity),
+				})
+			}
+		}
+	}
+
+	return validationErrors
+}
+
+// validateDomains checks that domains are only used by gateway projects and that each domain routes to a
+// single environment
+func (s *ValidationService) validateDomains(manifest models.Manifest) ValidationErrors {
+	var validationErrors ValidationErrors
+	environments := map[string]string{}
+
+	for i, manifestEnvironment := range manifest.Environments {
+		for j, domain := range manifestEnvironment.Domains {
+			path := fmt.Sprintf("environments[%d].domains[%d]", i, j)
+
+			if manifest.Trigger != triggerGateway {
+				validationErrors = append(validationErrors, ValidationError{
+					Path:    path,
+					Message: fmt.Sprintf("requires trigger gateway, got %s", manifest.Trigger),
+				})
+
+				continue
+			}
+
+			if environment, found := environments[strings.ToLower(domain)]; found {
+				validationErrors = append(validationErrors, ValidationError{
+					Path:    path,
+					Message: fmt.Sprintf("is already used by environment %s", environment),
+				})
+
+				continue
+			}
+
+			environments[strings.ToLower(domain)] = manifestEnvironment.Name
+		}
+	}
+
+	return validationErrors
+}
+
+// validateVariables checks that each variable has a single value source and that secret values are not
+// written in the manifest
+func (s *ValidationService) validateVariables(manifest models.Manifest) ValidationErrors {
+	var validationErrors ValidationErrors
+
+	for i, manifestEnvironment := range manifest.Environments {
+		for j, variable := range manifestEnvironment.Variables {
+			path := fmt.Sprintf("environments[%d].variables[%d]", i, j)
+
+			switch {
+			case variable.ValueFrom == nil && variable.Value == "":
+				validationErrors = append(validationErrors, ValidationError{
+					Path:    path + ".value",
+					Message: "is required",
+				})
+			case variable.ValueFrom == nil && variable.Secret && !helpers.IsEncrypted(variable.Value):
+				validationErrors = append(validationErrors, ValidationError{
+					Path:    path + ".value",
+					Message: "must be encrypted with flight secrets encrypt for secret variables, or use value_from instead",
+				})
+			case variable.ValueFrom != nil && variable.Value != "":
+				validationErrors = append(validationErrors, ValidationError{
+					Path:    path + ".value_from",
+					Message: "must not be set together with value",
+				})
+			case variable.ValueFrom != nil && len(lo.Compact[string]([]string{variable.ValueFrom.Env, variable.ValueFrom.File, variable.ValueFrom.Command})) != 1:
+				validationErrors = append(validationErrors, ValidationError{
+					Path:    path + ".value_from",
+					Message: "must set exactly one of env, file or command",
+				})
+			}
+		}
+	}
+
+	return validationErrors
+}
+
+func (s *ValidationService) validateEnvironment(manifest models.Manifest, environment string) ValidationErrors {
+	var validationErrors ValidationErrors
+
+	if lo.ContainsBy[models.ManifestEnvironment](manifest.Environments, func(manifestEnvironment models.ManifestEnvironment) bool {
+		return manifestEnvironment.Name == environment
+	}) {
+		return validationErrors
+	}
+
+	names := lo.Map[models.ManifestEnvironment, string](manifest.Environments, func(manifestEnvironment models.ManifestEnvironment, _ int) string {
+		return manifestEnvironment.Name
+	})
+
+	return append(validationErrors, ValidationError{
+		Path:       "environments",
+		Message:    fmt.Sprintf("does not contain environment %s, please configure environment before deploying", environment),
+		Allowed:    names,
+		Suggestion: suggest(environment, names),
+	})
+}
+
+// newManifestValidator returns a validator reporting fields by their manifest key instead of their go name,
+// along with the validations specific to the manifest
+func newManifestValidator() *validator.Validate {
+	validate := validator.New()
+	validate.RegisterTagNameFunc(manifestFieldName)
+
+	err := validate.RegisterValidation("schedule", func(field validator.FieldLevel) bool {
+		_, err := helpers.ParseSchedule(field.Field().String())
+
+		return err == nil
+	})
+
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	err = validate.RegisterValidation("recipient", func(field validator.FieldLevel) bool {
+		return helpers.IsPublicKey(field.Field().String())
+	})
+
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	err = validate.RegisterValidation("signing_key", func(field validator.FieldLevel) bool {
+		return helpers.IsSigningKey(field.Field().String())
+	})
+
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	err = validate.RegisterValidation("include", func(field validator.FieldLevel) bool {
+		_, err := helpers.ParseInclude(field.Field().String())
+
+		return err == nil
+	})
+
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	err = validate.RegisterValidation("capacity", func(field validator.FieldLevel) bool {
+		return lo.Contains[int64](databaseCapacities, field.Field().Int())
+	})
+
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	return validate
+}
+
+func newValidationError(fieldError validator.FieldError) ValidationError {
+	validationError := ValidationError{
+		// the namespace starts with the name of the validated struct, which is not part of the manifest path
+		Path: fieldError.Namespace()[strings.Index(fieldError.Namespace(), ".")+1:],
+	}
+
+	value := fmt.Sprintf("%v", fieldError.Value())
+
+	switch fieldError.Tag() {
+	case "required":
+		validationError.Message = "is required"
+	case "required_if":
+		condition := strings.Fields(fieldError.Param())
+		validationError.Message = fmt.Sprintf("is required when %s is %s", strings.ToLower(condition[0]), condition[1])
+	case "schedule":
+		_, err := helpers.ParseSchedule(value)
+		validationError.Message = fmt.Sprintf("has invalid schedule %q: %v", value, err)
+	case "signing_key":
+		validationError.Message = fmt.Sprintf("must be a public key printed by flight keys generate, got %q", value)
+	case "include":
+		_, err := helpers.ParseInclude(value)
+		validationError.Message = fmt.Sprintf("has invalid include %q: %v", value, err)
+	case "json":
+		validationError.Message = "must be valid json"
+	case "recipient":
+		validationError.Message = fmt.Sprintf("must be a public key printed by flight secrets public-key, got %q", value)
+	case "fqdn":
+		validationError.Message = fmt.Sprintf("must be a fully qualified domain name, got %q", value)
+	case "capacity":
+		validationError.Allowed = lo.Map[int64, string](databaseCapacities, func(capacity int64, _ int) string {
+			return fmt.Sprint(capacity)
+		})
+		validationError.Message = fmt.Sprintf("has invalid capacity %s", value)
+	case "oneof":
+		validationError.Allowed = strings.Fields(fieldError.Param())
+		validationError.Message = fmt.Sprintf("has invalid value %q", value)
+		validationError.Suggestion = suggest(value, validationError.Allowed)
+	case "min", "gte":
+		validationError.Message = boundMessage("at least", fieldError)
+	case "max", "lte":
+		validationError.Message = boundMessage("at most", fieldError)
+	case "gt":
+		validationError.Message = boundMessage("more than", fieldError)
+	case "lt":
+		validationError.Message = boundMessage("less than", fieldError)
+	default:
+		validationError.Message = fmt.Sprintf("failed on the '%s' validation, got %q", fieldError.Tag(), value)
+	}
+
+	return validationError
+}
+
+func boundMessage(comparison string, fieldError validator.FieldError) string {
+	switch fieldError.Kind() {
+	case reflect.String:
+		return fmt.Sprintf("must be %s %s characters long", comparison, fieldError.Param())
+	case reflect.Slice, reflect.Map, reflect.Array:
+		return fmt.Sprintf("must contain %s %s item(s)", comparison, fieldError.Param())
+	default:
+		return fmt.Sprintf("must be %s %s, got %v", comparison, fieldError.Param(), fieldError.Value())
+	}
+}
+
+// manifestFieldName returns the manifest key of a field, which is the name of its json tag
+func manifestFieldName(field reflect.StructField) string {
+	name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+
+	if name == "-" {
+		return ""
+	}
+
+	if name == "" {
+		return field.Name
+	}
+
+	return name
+}
+
+func manifestFieldNames(t reflect.Type) []string {
+	var names []string
+
+	for _, field := range reflect.VisibleFields(t) {
+		if name := manifestFieldName(field); name != "" && field.IsExported() {
+			names = append(names, name)
+		}
+	}
+
+	return names
+}
+
+func joinManifestPath(path string, key string) string {
+	if path == "" {
+		return key
+	}
+
+	return path + "." + key
+}
+
+// findManifestPosition returns the line and column of the node at the given path. When the path does not
+// exist in the document, for instance for a missing required field, the position of the closest parent is used.
+func findManifestPosition(root *yaml.Node, path string) (int, int) {
+	if root == nil {
+		return 0, 0
+	}
+
+	node := root
+
+	if node.Kind == yaml.DocumentNode && len(node.Content) > 0 {
+		node = node.Content[0]
+	}
+
+	for _, segment := range strings.Split(strings.ReplaceAll(path, "[", ".["), ".") {
+		if segment == "" {
+			continue
+		}
+
+		child := findChildNode(node, segment)
+
+		if child == nil {
+			break
+		}
+
+		node = child
+	}
+
+	return node.Line, node.Column
+}
+
+func findChildNode(node *yaml.Node, segment string) *yaml.Node {
+	if node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if strings.EqualFold(node.Content[i].Value, segment) {
+				return node.Content[i+1]
+			}
+		}
+	case yaml.SequenceNode:
+		var index int
+
+		if _, err := fmt.Sscanf(segment, "[%d]", &index); err == nil && index >= 0 && index < len(node.Content) {
+			return node.Content[index]
+		}
+	}
+
+	return nil
+}
+
+// suggest returns the candidate closest to the value when it looks like a typo of it
+func suggest(value string, candidates []string) string {
+	suggestion := ""
+	suggestionDistance := 0
+
+	for _, candidate := range candidates {
+		distance := levenshtein(strings.ToLower(value), strings.ToLower(candidate))
+
+		if distance > len(candidate)/3+1 {
+			continue
+		}
+
+		if suggestion == "" || distance < suggestionDistance {
+			suggestion = candidate
+			suggestionDistance = distance
+		}
+	}
+
+	return suggestion
+}
+
+func levenshtein(a string, b string) int {
+	source := []rune(a)
+	target := []rune(b)
+	previous := make([]int, len(target)+1)
+	current := make([]int, len(target)+1)
+
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(source); i++ {
+		current[0] = i
+
+		for j := 1; j <= len(target); j++ {
+			cost := 1
+
+			if source[i-1] == target[j-1] {
+				cost = 0
+			}
+
+			current[j] = lo.Min[int]([]int{previous[j] + 1, current[j-1] + 1, previous[j-1] + cost})
+		}
+
+		previous, current = current, previous
+	}
+
+	return previous[len(target)]
+}
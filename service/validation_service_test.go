@@ -0,0 +1,409 @@
+package service
+
+import (
+	"github.com/getflight/flight/mocks"
+	"github.com/getflight/flight/models"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+	"testing"
+)
+
+const validationManifest = `name: test
+trigger: queue
+environments:
+  - name: dev
+    databases:
+      - name: db
+        driver: postgres
+    variables:
+      - key: var1
+        vaule: value1
+`
+
+func TestValidationService(t *testing.T) {
+	t.Run("ValidateManifest with valid manifest returns nil", func(t *testing.T) {
+		// given
+		validationService := ValidationService{}
+
+		// when
+		err := validationService.ValidateManifest(getManifest(), "dev")
+
+		// then
+		assert.Nil(t, err)
+	})
+
+	t.Run("ValidateManifest with invalid manifest returns every error with position and suggestion", func(t *testing.T) {
+		// given
+		manifest := getManifest()
+		manifest.Environments[0].Databases[0].Driver = "postgres"
+		manifest.Environments[0].Variables = []models.ManifestVariable{{Key: "var1"}}
+
+		configuration := &mocks.ConfigurationMock{}
+		configuration.On("GetManifestFile").Return("flight.yml")
+		configuration.On("GetManifestNode").Return(getManifestNode(t, validationManifest), nil)
+
+		validationService := ValidationService{Configuration: configuration}
+
+		// when
+		err := validationService.ValidateManifest(manifest, "dev")
+
+		// then
+		var validationErrors ValidationErrors
+		assert.True(t, errors.As(err, &validationErrors))
+		assert.Equal(t, 3, len(validationErrors))
+
+		assert.Equal(t, "environments[0].databases[0].driver", validationErrors[0].Path)
+		assert.Equal(t, 7, validationErrors[0].Line)
+		assert.Equal(t, 17, validationErrors[0].Column)
+		assert.Equal(t, []string{"mysql", "postgresql"}, validationErrors[0].Allowed)
+		assert.Equal(t, "postgresql", validationErrors[0].Suggestion)
+		assert.Equal(t, `flight.yml:7:17: environments[0].databases[0].driver has invalid value "postgres" (allowed: mysql, postgresql), did you mean "postgresql"?`, validationErrors[0].Error())
+
+		assert.Equal(t, "environments[0].variables[0].value", validationErrors[1].Path)
+		assert.Equal(t, 9, validationErrors[1].Line)
+		assert.Equal(t, "is required", validationErrors[1].Message)
+
+		assert.Equal(t, "environments[0].variables[0].vaule", validationErrors[2].Path)
+		assert.Equal(t, 10, validationErrors[2].Line)
+		assert.Equal(t, "value", validationErrors[2].Suggestion)
+		configuration.AssertExpectations(t)
+	})
+
+	t.Run("ValidateManifest with runtime settings outside of lambda limits returns errors", func(t *testing.T) {
+		// given
+		memory := int64(64)
+		timeout := int64(901)
+
+		manifest := getManifest()
+		manifest.Memory = &memory
+		manifest.Environments[0].Timeout = &timeout
+		manifest.Environments[0].Architecture = "arm"
+
+		validationService := ValidationService{}
+
+		// when
+		err := validationService.ValidateManifest(manifest, "dev")
+
+		// then
+		var validationErrors ValidationErrors
+		assert.True(t, errors.As(err, &validationErrors))
+		assert.Equal(t, 3, len(validationErrors))
+		assert.Equal(t, "memory", validationErrors[0].Path)
+		assert.Equal(t, "must be at least 128, got 64", validationErrors[0].Message)
+		assert.Equal(t, "environments[0].timeout", validationErrors[1].Path)
+		assert.Equal(t, "environments[0].architecture", validationErrors[2].Path)
+		assert.Equal(t, "arm64", validationErrors[2].Suggestion)
+	})
+
+	t.Run("ValidateManifest with schedule trigger without schedule returns error", func(t *testing.T) {
+		// given
+		manifest := getManifest()
+		manifest.Trigger = "schedule"
+
+		validationService := ValidationService{}
+
+		// when
+		err := validationService.ValidateManifest(manifest, "dev")
+
+		// then
+		var validationErrors ValidationErrors
+		assert.True(t, errors.As(err, &validationErrors))
+		assert.Equal(t, 1, len(validationErrors))
+		assert.Equal(t, "schedule", validationErrors[0].Path)
+		assert.Equal(t, "is required when trigger is schedule", validationErrors[0].Message)
+	})
+
+	t.Run("ValidateManifest with invalid schedule returns errors", func(t *testing.T) {
+		// given
+		manifest := getManifest()
+		manifest.Trigger = "schedule"
+		manifest.Schedule = &models.ManifestSchedule{Expression: "cron(0 2 * * *)", Payload: "{"}
+		manifest.Environments[0].Schedule = &models.ManifestEnvironmentSchedule{Expression: "rate(1 days)"}
+
+		validationService := ValidationService{}
+
+		// when
+		err := validationService.ValidateManifest(manifest, "dev")
+
+		// then
+		var validationErrors ValidationErrors
+		assert.True(t, errors.As(err, &validationErrors))
+		assert.Equal(t, 3, len(validationErrors))
+		assert.Equal(t, "schedule.expression", validationErrors[0].Path)
+		assert.Contains(t, validationErrors[0].Message, "cron must have 6 fields")
+		assert.Equal(t, "schedule.payload", validationErrors[1].Path)
+		assert.Equal(t, "environments[0].schedule.expression", validationErrors[2].Path)
+	})
+
+	t.Run("ValidateManifest with inconsistent queue settings returns errors", func(t *testing.T) {
+		// given
+		batchSize := int64(100)
+		maxReceiveCount := int64(5)
+		visibilityTimeout := int64(30)
+		timeout := int64(60)
+
+		manifest := getManifest()
+		manifest.Queue = &models.ManifestQueue{
+			BatchSize:         &batchSize,
+			MaxReceiveCount:   &maxReceiveCount,
+			VisibilityTimeout: &visibilityTimeout,
+		}
+		manifest.Environments[0].Timeout = &timeout
+
+		validationService := ValidationService{}
+
+		// when
+		err := validationService.ValidateManifest(manifest, "dev")
+
+		// then
+		var validationErrors ValidationErrors
+		assert.True(t, errors.As(err, &validationErrors))
+		assert.Equal(t, 3, len(validationErrors))
+		assert.Equal(t, "queue.batch_window", validationErrors[0].Path)
+		assert.Equal(t, "queue.max_receive_count", validationErrors[1].Path)
+		assert.Equal(t, "queue.visibility_timeout", validationErrors[2].Path)
+		assert.Equal(t, "must be at least the timeout of 60 s in environment dev, got 30", validationErrors[2].Message)
+	})
+
+	t.Run("ValidateManifest with queue settings of gateway trigger does not check them", func(t *testing.T) {
+		// given
+		batchSize := int64(100)
+		maxReceiveCount := int64(5)
+
+		manifest := getManifest()
+		manifest.Trigger = "gateway"
+		manifest.Queue = &models.ManifestQueue{
+			BatchSize:       &batchSize,
+			MaxReceiveCount: &maxReceiveCount,
+		}
+
+		validationService := ValidationService{}
+
+		// when
+		err := validationService.ValidateManifest(manifest, "dev")
+
+		// then
+		assert.Nil(t, err)
+	})
+
+	t.Run("ValidateManifest with invalid database capacity returns errors", func(t *testing.T) {
+		// given
+		minCapacity := int64(8)
+		maxCapacity := int64(6)
+
+		manifest := getManifest()
+		manifest.Environments[0].Databases[0].MinCapacity = &minCapacity
+		manifest.Environments[0].Databases[0].MaxCapacity = &maxCapacity
+
+		validationService := ValidationService{}
+
+		// when
+		err := validationService.ValidateManifest(manifest, "dev")
+
+		// then
+		var validationErrors ValidationErrors
+		assert.True(t, errors.As(err, &validationErrors))
+		assert.Equal(t, 2, len(validationErrors))
+		assert.Equal(t, "environments[0].databases[0].max_capacity", validationErrors[0].Path)
+		assert.Equal(t, "has invalid capacity 6", validationErrors[0].Message)
+		assert.Contains(t, validationErrors[0].Allowed, "8")
+		assert.Equal(t, "environments[0].databases[0].max_capacity", validationErrors[1].Path)
+		assert.Equal(t, "must be at least min_capacity 8, got 6", validationErrors[1].Message)
+	})
+
+	t.Run("ValidateManifest with invalid domains returns errors", func(t *testing.T) {
+		// given
+		manifest := getManifest()
+		manifest.Trigger = "gateway"
+		manifest.Environments[0].Domains = []string{"api.example.com", "localhost"}
+		manifest.Environments = append(manifest.Environments, models.ManifestEnvironment{Name: "prod", Domains: []string{"API.example.com"}})
+
+		validationService := ValidationService{}
+
+		// when
+		err := validationService.ValidateManifest(manifest, "dev")
+
+		// then
+		var validationErrors ValidationErrors
+		assert.True(t, errors.As(err, &validationErrors))
+		assert.Equal(t, 2, len(validationErrors))
+		assert.Equal(t, "environments[0].domains[1]", validationErrors[0].Path)
+		assert.Equal(t, `must be a fully qualified domain name, got "localhost"`, validationErrors[0].Message)
+		assert.Equal(t, "environments[1].domains[0]", validationErrors[1].Path)
+		assert.Equal(t, "is already used by environment dev", validationErrors[1].Message)
+	})
+
+	t.Run("ValidateManifest with invalid includes returns errors", func(t *testing.T) {
+		// given
+		manifest := getManifest()
+		manifest.Package.Symlinks = "copy"
+		manifest.Environments[0].Package = &models.ManifestPackage{Includes: &[]string{"configs/dev.yml:config.yml", "configs/dev.yml:/etc/config.yml"}}
+
+		validationService := ValidationService{}
+
+		// when
+		err := validationService.ValidateManifest(manifest, "dev")
+
+		// then
+		var validationErrors ValidationErrors
+		assert.True(t, errors.As(err, &validationErrors))
+		assert.Equal(t, 2, len(validationErrors))
+		assert.Equal(t, "package.symlinks", validationErrors[0].Path)
+		assert.Equal(t, []string{"follow", "preserve"}, validationErrors[0].Allowed)
+		assert.Equal(t, "environments[0].package.includes[1]", validationErrors[1].Path)
+		assert.Equal(t, `has invalid include "configs/dev.yml:/etc/config.yml": destination /etc/config.yml must be inside the function directory`, validationErrors[1].Message)
+	})
+
+	t.Run("ValidateManifest with domains without gateway trigger returns error", func(t *testing.T) {
+		// given
+		manifest := getManifest()
+		manifest.Environments[0].Domains = []string{"api.example.com"}
+
+		validationService := ValidationService{}
+
+		// when
+		err := validationService.ValidateManifest(manifest, "dev")
+
+		// then
+		var validationErrors ValidationErrors
+		assert.True(t, errors.As(err, &validationErrors))
+		assert.Equal(t, 1, len(validationErrors))
+		assert.Equal(t, "requires trigger gateway, got queue", validationErrors[0].Message)
+	})
+
+	t.Run("ValidateManifest with invalid variable sources returns errors", func(t *testing.T) {
+		// given
+		manifest := getManifest()
+		manifest.Environments[0].Variables = []models.ManifestVariable{
+			{Key: "var1", Value: "value1", Secret: true},
+			{Key: "var2", Value: "value2", ValueFrom: &models.ManifestVariableSource{Env: "VAR2"}},
+			{Key: "var3", ValueFrom: &models.ManifestVariableSource{Env: "VAR3", File: "var3.txt"}},
+		}
+
+		validationService := ValidationService{}
+
+		// when
+		err := validationService.ValidateManifest(manifest, "dev")
+
+		// then
+		var validationErrors ValidationErrors
+		assert.True(t, errors.As(err, &validationErrors))
+		assert.Equal(t, 3, len(validationErrors))
+		assert.Equal(t, "environments[0].variables[0].value", validationErrors[0].Path)
+		assert.Equal(t, "must be encrypted with flight secrets encrypt for secret variables, or use value_from instead", validationErrors[0].Message)
+		assert.Equal(t, "environments[0].variables[1].value_from", validationErrors[1].Path)
+		assert.Equal(t, "environments[0].variables[2].value_from", validationErrors[2].Path)
+		assert.Equal(t, "must set exactly one of env, file or command", validationErrors[2].Message)
+	})
+
+	t.Run("ValidateManifest with encrypted secret and invalid recipient returns error", func(t *testing.T) {
+		// given
+		manifest := getManifest()
+		manifest.Environments[0].Recipients = []string{"OZ+ZVTi0IK5ChO0zuOXSJmrVtoQ5C/TrtqjCGFSp3kE=", "invalid"}
+		manifest.Environments[0].Variables[0].Secret = true
+		manifest.Environments[0].Variables[0].Value = "ENC[value]"
+
+		validationService := ValidationService{}
+
+		// when
+		err := validationService.ValidateManifest(manifest, "dev")
+
+		// then
+		var validationErrors ValidationErrors
+		assert.True(t, errors.As(err, &validationErrors))
+		assert.Equal(t, 1, len(validationErrors))
+		assert.Equal(t, "environments[0].recipients[1]", validationErrors[0].Path)
+	})
+
+	t.Run("ValidateManifest with unknown environment suggests closest environment", func(t *testing.T) {
+		// given
+		validationService := ValidationService{}
+
+		// when
+		err := validationService.ValidateManifest(getManifest(), "deb")
+
+		// then
+		var validationErrors ValidationErrors
+		assert.True(t, errors.As(err, &validationErrors))
+		assert.Equal(t, 1, len(validationErrors))
+		assert.Equal(t, "environments", validationErrors[0].Path)
+		assert.Equal(t, "dev", validationErrors[0].Suggestion)
+	})
+
+	t.Run("ValidateManifest without environment skips environment validation", func(t *testing.T) {
+		// given
+		validationService := ValidationService{}
+
+		// when
+		err := validationService.ValidateManifest(getManifest(), "")
+
+		// then
+		assert.Nil(t, err)
+	})
+
+	t.Run("Validate reads manifest from configuration", func(t *testing.T) {
+		// given
+		configuration := &mocks.ConfigurationMock{}
+		configuration.On("Init").Return(nil)
+		configuration.On("GetManifest").Return(getManifest(), nil)
+		configuration.On("GetManifestNode").Return(&yaml.Node{}, nil)
+
+		validationService := ValidationService{Configuration: configuration}
+
+		// when
+		err := validationService.Validate("dev")
+
+		// then
+		assert.Nil(t, err)
+		configuration.AssertExpectations(t)
+	})
+
+	t.Run("Validate with configuration error returns error", func(t *testing.T) {
+		// given
+		configuration := &mocks.ConfigurationMock{}
+		configuration.On("Init").Return(errors.New("test error"))
+
+		validationService := ValidationService{Configuration: configuration}
+
+		// when
+		err := validationService.Validate("dev")
+
+		// then
+		assert.NotNil(t, err)
+		configuration.AssertExpectations(t)
+	})
+
+	t.Run("suggest returns closest candidate", func(t *testing.T) {
+		// given
+		candidates := []string{"gateway", "queue"}
+
+		// when
+		suggestion := suggest("gatway", candidates)
+
+		// then
+		assert.Equal(t, "gateway", suggestion)
+	})
+
+	t.Run("suggest without close candidate returns empty suggestion", func(t *testing.T) {
+		// given
+		candidates := []string{"gateway", "queue"}
+
+		// when
+		suggestion := suggest("http", candidates)
+
+		// then
+		assert.Equal(t, "", suggestion)
+	})
+}
+
+func getManifestNode(t *testing.T, content string) *yaml.Node {
+	node := &yaml.Node{}
+	err := yaml.Unmarshal([]byte(content), node)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return node
+}